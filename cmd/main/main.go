@@ -2,18 +2,50 @@ package main
 
 import (
 	"github.com/lactobasilusprotectus/go-template/docs"
+	accountCommon "github.com/lactobasilusprotectus/go-template/pkg/account/common"
+	accountDelivery "github.com/lactobasilusprotectus/go-template/pkg/account/delivery"
+	accountRepository "github.com/lactobasilusprotectus/go-template/pkg/account/repository"
+	accountUsecase "github.com/lactobasilusprotectus/go-template/pkg/account/usecase"
+	apiKeyRepository "github.com/lactobasilusprotectus/go-template/pkg/apikey/repository"
+	auditDelivery "github.com/lactobasilusprotectus/go-template/pkg/audit/delivery"
+	auditRepository "github.com/lactobasilusprotectus/go-template/pkg/audit/repository"
+	auditUsecase "github.com/lactobasilusprotectus/go-template/pkg/audit/usecase"
+	authCommon "github.com/lactobasilusprotectus/go-template/pkg/auth/common"
 	authDelivery "github.com/lactobasilusprotectus/go-template/pkg/auth/delivery"
 	authUsecase "github.com/lactobasilusprotectus/go-template/pkg/auth/usecase"
 	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/password"
 	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	deviceRepository "github.com/lactobasilusprotectus/go-template/pkg/device/repository"
 	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	identityRepository "github.com/lactobasilusprotectus/go-template/pkg/identity/repository"
+	invitationDelivery "github.com/lactobasilusprotectus/go-template/pkg/invitation/delivery"
+	invitationRepository "github.com/lactobasilusprotectus/go-template/pkg/invitation/repository"
+	invitationUsecase "github.com/lactobasilusprotectus/go-template/pkg/invitation/usecase"
+	jwksDelivery "github.com/lactobasilusprotectus/go-template/pkg/jwks/delivery"
+	jwksUsecase "github.com/lactobasilusprotectus/go-template/pkg/jwks/usecase"
+	mfaRepository "github.com/lactobasilusprotectus/go-template/pkg/mfa/repository"
+	oauthCommon "github.com/lactobasilusprotectus/go-template/pkg/oauth/common"
+	oauthDelivery "github.com/lactobasilusprotectus/go-template/pkg/oauth/delivery"
+	oauthRepository "github.com/lactobasilusprotectus/go-template/pkg/oauth/repository"
+	oauthUsecase "github.com/lactobasilusprotectus/go-template/pkg/oauth/usecase"
+	organizationDelivery "github.com/lactobasilusprotectus/go-template/pkg/organization/delivery"
+	organizationRepository "github.com/lactobasilusprotectus/go-template/pkg/organization/repository"
+	organizationUsecase "github.com/lactobasilusprotectus/go-template/pkg/organization/usecase"
+	rbacDelivery "github.com/lactobasilusprotectus/go-template/pkg/rbac/delivery"
+	rbacRepository "github.com/lactobasilusprotectus/go-template/pkg/rbac/repository"
+	rbacUsecase "github.com/lactobasilusprotectus/go-template/pkg/rbac/usecase"
 	rootDelivery "github.com/lactobasilusprotectus/go-template/pkg/root/delivery"
+	sessionRepository "github.com/lactobasilusprotectus/go-template/pkg/session/repository"
 	userRepository "github.com/lactobasilusprotectus/go-template/pkg/user/repository"
 	"github.com/lactobasilusprotectus/go-template/pkg/util/cronjob"
 	"github.com/lactobasilusprotectus/go-template/pkg/util/db"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/geoip"
 	_ "github.com/lactobasilusprotectus/go-template/pkg/util/http"
 	httputil "github.com/lactobasilusprotectus/go-template/pkg/util/http"
 	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/mail"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/oidc"
 	"github.com/lactobasilusprotectus/go-template/pkg/util/queue"
 	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
 	"log"
@@ -65,7 +97,7 @@ func main() {
 	}
 
 	// Init, Register, Start delivery layer (HTTP)
-	httpHandler := initHttpHandler(utils, uc, env)
+	httpHandler := initHttpHandler(utils, uc, cfg, env)
 	registerHttpHandler(utils.HttpServer, httpHandler)
 	utils.HttpServer.Run(env)
 
@@ -119,31 +151,78 @@ func initUtils(cfg config.Config) AppUtil {
 	// time module
 	timeModule := commonTime.New()
 
-	// JWT implementation
-	jwtModule := jwt.New(timeModule)
+	// password hashing
+	passwordHasher, err := password.New(cfg.Password)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	var breachedPasswords *password.BreachedList
+	if cfg.Password.BreachedListFile != "" {
+		if breachedPasswords, err = password.LoadBreachedList(cfg.Password.BreachedListFile); err != nil {
+			log.Fatalln(err)
+		}
+		log.Printf("breached password list loaded: %d hashes", breachedPasswords.Len())
+	}
+
+	// JWT implementation, tokens only this service consumes are kept apart from the published access token keys
+	jwtModule, err := jwt.New(timeModule, authCommon.RefreshTokenType, authCommon.MfaPendingTokenType,
+		authCommon.MagicLinkTokenType, authCommon.LoginReportTokenType, accountCommon.ExportTokenType,
+		oauthCommon.RefreshTokenType)
+	if err != nil {
+		log.Fatalln(err)
+	}
 
 	//queue
 	asynq := queue.NewClient(cfg.Redis)
 
+	// client IP to country resolution, nil when no database is configured
+	var geoIP geoip.Interface
+	if cfg.LoginNotification.GeoIPDatabaseFile != "" {
+		if geoIP, err = geoip.Open(cfg.LoginNotification.GeoIPDatabaseFile); err != nil {
+			log.Fatalln(err)
+		}
+		log.Printf("geoip database loaded: %s", cfg.LoginNotification.GeoIPDatabaseFile)
+	}
+
+	// external identity providers
+	var oidcProviders []oidc.Interface
+	for _, providerConfig := range cfg.OidcProviders {
+		oidcProviders = append(oidcProviders, oidc.NewProvider(providerConfig, timeModule))
+	}
+
 	return AppUtil{
-		HttpServer:   httputil.NewServer(cfg.Http),
-		DbConnection: dbConn,
-		Redis:        redisClient,
-		Jwt:          jwtModule,
-		Time:         timeModule,
-		Asynq:        asynq,
-		AsynqServer:  queue.NewAsynqServer(cfg.Redis),
-		Cron:         cronjob.NewCron(),
+		HttpServer:     httputil.NewServer(cfg.Http),
+		DbConnection:   dbConn,
+		Redis:          redisClient,
+		Jwt:            jwtModule,
+		Password:       passwordHasher,
+		PasswordPolicy: password.NewPolicy(cfg.Password, breachedPasswords),
+		Time:           timeModule,
+		Asynq:          asynq,
+		AsynqServer:    queue.NewAsynqServer(cfg.Redis),
+		Cron:           cronjob.NewCron(),
+		Mailer:         mail.NewMailer(cfg.Mail),
+		OidcProviders:  oidcProviders,
+		GeoIP:          geoIP,
 	}
 }
 
 // initHttpHandler initialises http handler for the app
-func initHttpHandler(ut AppUtil, uc AppUseCase, env string) AppHttpHandler {
+func initHttpHandler(ut AppUtil, uc AppUseCase, cfg config.Config, env string) AppHttpHandler {
 	rootHandler := rootDelivery.NewRootHandler(env)
 
 	return AppHttpHandler{
-		RootHttpHandler: rootHandler,
-		AuthHttpHandler: authDelivery.NewAuthHttpHandler(uc.AuthUseCase, uc.AuthUseCase),
+		RootHttpHandler:    rootHandler,
+		AuthHttpHandler:    authDelivery.NewAuthHttpHandler(uc.AuthUseCase, uc.AuthUseCase, cfg.Http.Cookie),
+		RbacHttpHandler:    rbacDelivery.NewRbacHttpHandler(uc.AuthUseCase, uc.RbacUseCase),
+		OAuthHttpHandler:   oauthDelivery.NewOAuthHttpHandler(uc.AuthUseCase, uc.OAuthUseCase, uc.OAuthUseCase),
+		JwksHttpHandler:    jwksDelivery.NewJwksHttpHandler(uc.JwksUseCase),
+		AuditHttpHandler:   auditDelivery.NewAuditHttpHandler(uc.AuthUseCase, uc.AuditUseCase),
+		AccountHttpHandler: accountDelivery.NewAccountHttpHandler(uc.AuthUseCase, uc.AccountUseCase),
+		OrganizationHttpHandler: organizationDelivery.NewOrganizationHttpHandler(uc.AuthUseCase,
+			uc.OrganizationUseCase),
+		InvitationHttpHandler: invitationDelivery.NewInvitationHttpHandler(uc.AuthUseCase, uc.InvitationUseCase),
 	}
 }
 
@@ -151,8 +230,44 @@ func initHttpHandler(ut AppUtil, uc AppUseCase, env string) AppHttpHandler {
 func initRepoAndUseCases(util AppUtil, cfg config.Config) (repo AppRepo, uc AppUseCase, err error) {
 	repo.User = userRepository.NewUserRepository(util.DbConnection, util.Time)
 
+	switch cfg.SessionStore {
+	case config.SessionStoreMemory:
+		repo.Session = sessionRepository.NewMemorySessionRepository(util.Time)
+	default:
+		repo.Session = sessionRepository.NewRedisSessionRepository(util.Redis, util.Time)
+	}
+
+	repo.RecoveryCode = mfaRepository.NewRecoveryCodeRepository(util.DbConnection, util.Time)
+	repo.Rbac = rbacRepository.NewRbacRepository(util.DbConnection, util.Time)
+	repo.ApiKey = apiKeyRepository.NewApiKeyRepository(util.DbConnection, util.Time)
+	repo.OAuth = oauthRepository.NewOAuthRepository(util.DbConnection, util.Time)
+	repo.UserIdentity = identityRepository.NewUserIdentityRepository(util.DbConnection, util.Time)
+	repo.Audit = auditRepository.NewAuditRepository(util.DbConnection, util.Time)
+	repo.Organization = organizationRepository.NewOrganizationRepository(util.DbConnection, util.Time)
+	repo.Device = deviceRepository.NewDeviceRepository(util.DbConnection, util.Time)
+	repo.Invitation = invitationRepository.NewInvitationRepository(util.DbConnection, util.Time)
+	repo.Account = accountRepository.NewAccountRepository(util.DbConnection, util.Time, listModels(AppModels{})...)
+
 	//usecase
-	uc.AuthUseCase = authUsecase.NewAuthUseCase(repo.User, util.Jwt, util.Redis, util.Time, cfg, util.Asynq)
+	uc.AuditUseCase = auditUsecase.NewAuditUseCase(repo.Audit, util.Asynq, util.Time, cfg)
+	uc.AuthUseCase = authUsecase.NewAuthUseCase(repo.User, repo.Session, repo.RecoveryCode, repo.Rbac, repo.ApiKey,
+		repo.UserIdentity, repo.Organization, repo.Device, repo.Invitation, util.OidcProviders, util.Jwt,
+		util.Password, util.PasswordPolicy, util.Redis, util.Time, cfg, util.Asynq, util.Mailer, uc.AuditUseCase,
+		util.GeoIP)
+	uc.RbacUseCase = rbacUsecase.NewRbacUseCase(repo.Rbac, repo.User, cfg)
+	uc.OAuthUseCase = oauthUsecase.NewOAuthUseCase(repo.OAuth, repo.User, uc.AuthUseCase, util.Jwt, util.Redis,
+		util.Time, cfg)
+	uc.JwksUseCase = jwksUsecase.NewJwksUseCase(util.Jwt, cfg)
+	uc.OrganizationUseCase = organizationUsecase.NewOrganizationUseCase(repo.Organization, repo.User, util.Time, cfg)
+	uc.AccountUseCase = accountUsecase.NewAccountUseCase(repo.Account, repo.User, uc.AuthUseCase, uc.AuditUseCase,
+		util.Jwt, util.Redis, util.Time, cfg, util.Asynq, util.Mailer)
+	uc.InvitationUseCase = invitationUsecase.NewInvitationUseCase(repo.Invitation, repo.User, repo.Rbac,
+		repo.Organization, uc.AuditUseCase, util.Redis, util.Time, cfg, util.Asynq, util.Mailer)
+
+	// built-in roles must exist before anything can be authorized
+	if err = uc.RbacUseCase.SeedDefaultRoles(); err != nil {
+		return repo, uc, err
+	}
 
 	return repo, uc, nil
 }
@@ -183,6 +298,18 @@ func registerModels(dbConn *db.DatabaseConnection, models AppModels) {
 	}
 }
 
+// listModels lists an instance of each of our models.
+// The purpose of this function is to let repositories reach every table, e.g. to export or delete the data of a user.
+func listModels(models AppModels) (instances []interface{}) {
+	m := reflect.ValueOf(models)
+
+	for i := 0; i < m.NumField(); i++ {
+		instances = append(instances, reflect.New(m.Field(i).Type().Elem()).Interface())
+	}
+
+	return instances
+}
+
 // registerCron registers our use cases as cron handler
 // reflect docs: https://golang.org/pkg/reflect/
 func registerCron(c *cronjob.Cron, uc AppUseCase) {
@@ -215,33 +342,77 @@ func registerQueue(as *queue.AsynqServer, uc AppUseCase) {
 
 // AppUtil wraps utility layer with the app, includes delivery and database
 type AppUtil struct {
-	HttpServer   *httputil.Server
-	DbConnection *db.DatabaseConnection
-	Redis        redis.Interface
-	Jwt          *jwt.JwtModule
-	Time         *commonTime.Time
-	Asynq        queue.Interface
-	AsynqServer  *queue.AsynqServer
-	Cron         *cronjob.Cron
+	HttpServer     *httputil.Server
+	DbConnection   *db.DatabaseConnection
+	Redis          redis.Interface
+	Jwt            *jwt.JwtModule
+	Password       *password.Hasher
+	PasswordPolicy *password.Policy
+	Time           *commonTime.Time
+	Asynq          queue.Interface
+	AsynqServer    *queue.AsynqServer
+	Cron           *cronjob.Cron
+	Mailer         mail.Interface
+	OidcProviders  []oidc.Interface
+	GeoIP          geoip.Interface
 }
 
 // AppHttpHandler wraps HTTP handlers exposed by the app as a delivery layer
 type AppHttpHandler struct {
-	RootHttpHandler *rootDelivery.RootHandler
-	AuthHttpHandler *authDelivery.AuthHttpHandler
+	RootHttpHandler         *rootDelivery.RootHandler
+	AuthHttpHandler         *authDelivery.AuthHttpHandler
+	RbacHttpHandler         *rbacDelivery.RbacHttpHandler
+	OAuthHttpHandler        *oauthDelivery.OAuthHttpHandler
+	JwksHttpHandler         *jwksDelivery.JwksHttpHandler
+	AuditHttpHandler        *auditDelivery.AuditHttpHandler
+	AccountHttpHandler      *accountDelivery.AccountHttpHandler
+	OrganizationHttpHandler *organizationDelivery.OrganizationHttpHandler
+	InvitationHttpHandler   *invitationDelivery.InvitationHttpHandler
 }
 
 // AppUseCase wraps use case layer within the app
 type AppUseCase struct {
-	AuthUseCase *authUsecase.AuthUseCase
+	AuthUseCase         *authUsecase.AuthUseCase
+	RbacUseCase         *rbacUsecase.RbacUseCase
+	OAuthUseCase        *oauthUsecase.OAuthUseCase
+	JwksUseCase         *jwksUsecase.JwksUseCase
+	AuditUseCase        *auditUsecase.AuditUseCase
+	AccountUseCase      *accountUsecase.AccountUseCase
+	OrganizationUseCase *organizationUsecase.OrganizationUseCase
+	InvitationUseCase   *invitationUsecase.InvitationUseCase
 }
 
 // AppRepo wraps repository layer within the app
 type AppRepo struct {
-	User *userRepository.UserRepository
+	User         *userRepository.UserRepository
+	Session      domain.SessionRepository
+	RecoveryCode *mfaRepository.RecoveryCodeRepository
+	Rbac         *rbacRepository.RbacRepository
+	ApiKey       *apiKeyRepository.ApiKeyRepository
+	OAuth        *oauthRepository.OAuthRepository
+	UserIdentity *identityRepository.UserIdentityRepository
+	Audit        *auditRepository.AuditRepository
+	Account      *accountRepository.AccountRepository
+	Organization *organizationRepository.OrganizationRepository
+	Device       *deviceRepository.DeviceRepository
+	Invitation   *invitationRepository.InvitationRepository
 }
 
 // AppModels wraps domain models within the app
 type AppModels struct {
-	User *domain.User
+	User               *domain.User
+	RecoveryCode       *domain.RecoveryCode
+	Role               *domain.Role
+	Permission         *domain.Permission
+	UserRole           *domain.UserRole
+	ApiKey             *domain.ApiKey
+	OAuthClient        *domain.OAuthClient
+	OAuthConsent       *domain.OAuthConsent
+	UserIdentity       *domain.UserIdentity
+	AuditEvent         *domain.AuditEvent
+	Organization       *domain.Organization
+	OrganizationMember *domain.OrganizationMember
+	KnownDevice        *domain.KnownDevice
+	LoginRecord        *domain.LoginRecord
+	Invitation         *domain.Invitation
 }
@@ -0,0 +1,49 @@
+package common
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	ErrExportTooFrequent = fmt.Errorf("an export was requested recently, try again later")
+	ErrExportInvalid     = fmt.Errorf("export link invalid or expired")
+)
+
+const (
+	ExportTokenType       = "account_export"
+	ExportRequestInterval = time.Hour // 1 hour
+)
+
+// A list of task types.
+const (
+	TypeExportPersonalData = "account:export"
+)
+
+type DownloadExportRequest struct {
+	Token string `form:"token" validate:"required"`
+}
+
+// ExportArchive is the personal data of a user as downloaded
+type ExportArchive struct {
+	UserID     int64                  `json:"user_id"`
+	ExportedAt time.Time              `json:"exported_at"`
+	Data       map[string]interface{} `json:"data"` // rows of the user, keyed by table name
+}
+
+// DeleteAccountRequest confirms the deletion by the password, or a TOTP or recovery code of two-factor
+// authentication
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required_without=Code"`
+	Code     string `json:"code" validate:"required_without=Password"`
+}
+
+type AccountDeletion struct {
+	Message string    `json:"message"`
+	PurgeAt time.Time `json:"purge_at"` // when the account is deleted for good
+}
+
+type ExportPayload struct {
+	UserID   int64  `json:"user_id"`
+	ExportID string `json:"export_id"`
+}
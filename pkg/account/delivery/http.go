@@ -0,0 +1,176 @@
+package delivery
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lactobasilusprotectus/go-template/pkg/account/common"
+	authCommon "github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	httputil "github.com/lactobasilusprotectus/go-template/pkg/util/http"
+	"net/http"
+)
+
+type AccountHttpHandler struct {
+	authMiddleware domain.GinAuthentication
+	accountUseCase domain.AccountUseCase
+}
+
+func NewAccountHttpHandler(authMiddleware domain.GinAuthentication,
+	accountUseCase domain.AccountUseCase) *AccountHttpHandler {
+	return &AccountHttpHandler{
+		authMiddleware: authMiddleware,
+		accountUseCase: accountUseCase,
+	}
+}
+
+func (a *AccountHttpHandler) Register(g *gin.Engine) {
+	g.GET("me/export", a.authMiddleware.MustLogin(), a.RequestExport)
+	g.GET("me/export/download", a.DownloadExport)
+	g.DELETE("me", a.authMiddleware.MustLogin(), a.DeleteAccount)
+}
+
+// RequestExport		godoc
+//
+//	@Summary		Export personal data.
+//	@Description	Start exporting every personal data of the current user, a download link is emailed once the
+//	@Description	archive is ready. Exports can be requested once an hour.
+//	@Produce		application/json
+//	@Tags			account
+//	@Security		JWT
+//	@Success		200	{object}	http.BaseResponse
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		403	{object}	http.BaseResponse
+//	@Failure		429	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/me/export [get]
+func (a *AccountHttpHandler) RequestExport(c *gin.Context) {
+	// call use case
+	err := a.accountUseCase.RequestExport(c.Request.Context())
+
+	// handle error
+	if errors.Is(err, common.ErrExportTooFrequent) {
+		httputil.WriteTooManyRequestsResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, authCommon.ErrImpersonationNotAllowed) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "Export requested, a download link will be emailed once it is ready")
+	return
+}
+
+// DownloadExport		godoc
+//
+//	@Summary		Download personal data export.
+//	@Description	Download the personal data archive using the token of the emailed link.
+//	@Produce		application/json
+//	@Tags			account
+//	@Param			token	query		string	true	"Export Token"
+//	@Success		200		{object}	common.ExportArchive
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/me/export/download [get]
+func (a *AccountHttpHandler) DownloadExport(c *gin.Context) {
+	// init request
+	var downloadRequest common.DownloadExportRequest
+
+	//bind request query
+	if err := c.ShouldBindQuery(&downloadRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request
+	if err := validator.New().Struct(&downloadRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	archive, err := a.accountUseCase.DownloadExport(c.Request.Context(), downloadRequest.Token)
+
+	// handle error
+	if errors.Is(err, common.ErrExportInvalid) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", `attachment; filename="personal-data.json"`)
+	c.Data(http.StatusOK, "application/json", archive)
+	return
+}
+
+// DeleteAccount		godoc
+//
+//	@Summary		Delete account.
+//	@Description	Delete the current user and log out every session, confirmed by the password or a TOTP or
+//	@Description	recovery code. Wrong confirmations count towards the lockout of the account. The account and its
+//	@Description	data are purged for good after a grace period.
+//	@Produce		application/json
+//	@Tags			account
+//	@Security		JWT
+//	@Param			body	body		common.DeleteAccountRequest	true	"Delete Account Request"
+//	@Success		200		{object}	http.BaseResponse{data=common.AccountDeletion}
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		429		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/me [delete]
+func (a *AccountHttpHandler) DeleteAccount(c *gin.Context) {
+	// init request body
+	var deleteRequest common.DeleteAccountRequest
+
+	//bind request body
+	if err := c.ShouldBindJSON(&deleteRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&deleteRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	deletion, err := a.accountUseCase.DeleteAccount(c.Request.Context(), deleteRequest.Password, deleteRequest.Code)
+
+	// handle error
+	if errors.Is(err, authCommon.ErrImpersonationNotAllowed) || errors.Is(err, authCommon.ErrApiKeyNotAllowed) ||
+		errors.Is(err, authCommon.ErrInvalidCredentials) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, authCommon.ErrTooManyRequests) || errors.Is(err, authCommon.ErrAccountLocked) {
+		httputil.WriteTooManyRequestsResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, deletion)
+	return
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"fmt"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/db"
+	"gorm.io/gorm"
+	"reflect"
+	"time"
+)
+
+// AccountRepository reaches the rows of a user across every model, a model belongs to the user through its
+// user_id column, models without one are left alone.
+type AccountRepository struct {
+	dbClient *db.DatabaseConnection
+	time     commonTime.TimeInterface
+	models   []interface{}
+}
+
+func NewAccountRepository(dbClient *db.DatabaseConnection, time commonTime.TimeInterface,
+	models ...interface{}) *AccountRepository {
+	return &AccountRepository{
+		dbClient: dbClient,
+		time:     time,
+		models:   models,
+	}
+}
+
+// FindPersonalData returns the rows of the user in every model, keyed by table name
+func (r *AccountRepository) FindPersonalData(userID int64) (map[string]interface{}, error) {
+	data := make(map[string]interface{}, len(r.models))
+
+	for _, model := range r.models {
+		table, column, err := r.ownerColumn(model)
+		if err != nil {
+			return nil, err
+		}
+
+		if column == "" {
+			continue
+		}
+
+		// a slice of the model itself, so its json tags decide what is exported
+		rows := reflect.New(reflect.SliceOf(reflect.TypeOf(model).Elem()))
+
+		result := r.dbClient.Slave.Where(fmt.Sprintf("%s = ?", column), userID).Find(rows.Interface())
+
+		if result.Error != nil {
+			return nil, result.Error
+		}
+
+		data[table] = rows.Elem().Interface()
+	}
+
+	return data, nil
+}
+
+func (r *AccountRepository) FindUserIDsDeletedBefore(before time.Time) ([]int64, error) {
+	var userIDs []int64
+
+	result := r.dbClient.Slave.Unscoped().Model(&domain.User{}).Where("deleted_at < ?", before).Pluck("id", &userIDs)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return userIDs, nil
+}
+
+// PurgeUser deletes the user and its rows in every model for good, rows of models retaining personal data are
+// anonymized instead
+func (r *AccountRepository) PurgeUser(userID int64) (err error) {
+	return r.dbClient.Master.Transaction(func(tx *gorm.DB) error {
+		for _, model := range r.models {
+			_, column, err := r.ownerColumn(model)
+			if err != nil {
+				return err
+			}
+
+			if column == "" {
+				continue
+			}
+
+			query := tx.Unscoped().Model(model).Where(fmt.Sprintf("%s = ?", column), userID)
+
+			if retainer, ok := model.(domain.PersonalDataRetainer); ok {
+				err = query.Updates(retainer.AnonymizedColumns()).Error
+			} else {
+				err = query.Delete(model).Error
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// find the table of the model and its column referencing the user, empty when the model doesn't belong to users
+func (r *AccountRepository) ownerColumn(model interface{}) (table, column string, err error) {
+	stmt := &gorm.Statement{DB: r.dbClient.Slave}
+	if err = stmt.Parse(model); err != nil {
+		return "", "", fmt.Errorf("parse model err: %+v", err)
+	}
+
+	if _, ok := model.(*domain.User); ok {
+		return stmt.Schema.Table, stmt.Schema.PrioritizedPrimaryField.DBName, nil
+	}
+
+	if field := stmt.Schema.LookUpField("user_id"); field != nil {
+		return stmt.Schema.Table, field.DBName, nil
+	}
+
+	return stmt.Schema.Table, "", nil
+}
@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"github.com/lactobasilusprotectus/go-template/pkg/util/cronjob"
+)
+
+func (u *AccountUseCase) RegisterCron(c *cronjob.Cron) {
+	if u.config.Account.PurgeSchedule == "" {
+		return
+	}
+
+	c.AddFunc("account-purge", u.config.Account.PurgeSchedule, u.PurgeDeletedAccounts)
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hibiken/asynq"
+	"github.com/lactobasilusprotectus/go-template/pkg/account/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/queue"
+	"log"
+)
+
+func (u *AccountUseCase) RegisterQueue(as *queue.AsynqServer) {
+	as.AddHandlerFunc(common.TypeExportPersonalData, u.HandleExportPersonalData)
+}
+
+// HandleExportPersonalData assembles the personal data archive requested by RequestExport, keeps it for the
+// export lifetime and emails the user a link to download it.
+func (u *AccountUseCase) HandleExportPersonalData(ctx context.Context, task *asynq.Task) error {
+	var p common.ExportPayload
+	if err := json.Unmarshal(task.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	user, err := u.userRepo.FindUserByID(ctx, p.UserID)
+	if err != nil {
+		log.Printf("export requested by deleted user: user_id=%d", p.UserID)
+		return nil
+	}
+
+	data, err := u.accountRepo.FindPersonalData(user.ID)
+	if err != nil {
+		return fmt.Errorf("find personal data err: %+v", err)
+	}
+
+	archive, err := json.Marshal(common.ExportArchive{
+		UserID:     user.ID,
+		ExportedAt: u.time.Now(),
+		Data:       data,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal export err: %+v", err)
+	}
+
+	lifetime := u.config.Account.ExportLifetime
+
+	err = u.redis.Set(exportCacheKey(p.ExportID), string(archive), int(lifetime.Seconds()))
+	if err != nil {
+		return fmt.Errorf("store export err: %+v", err)
+	}
+
+	// signed, the export id alone doesn't give the archive away
+	token, err := u.jwtModule.GenerateToken(ctx, jwt.JwtData{
+		TokenID:    p.ExportID,
+		IdentityID: user.ID,
+		Type:       common.ExportTokenType,
+		Lifetime:   lifetime,
+	})
+	if err != nil {
+		return fmt.Errorf("generate export token err: %+v", err)
+	}
+
+	link := fmt.Sprintf("%s/account/export?token=%s", u.config.ClientURL, token)
+	body := fmt.Sprintf("The export of your personal data is ready. Download it within %s from the following link:\n%s\n\n"+
+		"If you didn't request it, please change your password.", lifetime, link)
+
+	log.Printf("personal data exported: user_id=%d, export_id=%s", user.ID, p.ExportID)
+	return u.mailer.Send(user.Email, "Your personal data export", body)
+}
@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/lactobasilusprotectus/go-template/pkg/account/common"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	authCommon "github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/mail"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/queue"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"log"
+)
+
+type AccountUseCase struct {
+	accountRepo   domain.AccountRepository
+	userRepo      domain.UserRepository
+	authUseCase   domain.AuthUseCase
+	auditRecorder domain.AuditRecorder
+	jwtModule     jwt.JwtInterface
+	redis         redis.Interface
+	time          commonTime.TimeInterface
+	config        config.Config
+	client        queue.Interface
+	mailer        mail.Interface
+}
+
+func NewAccountUseCase(accountRepo domain.AccountRepository, userRepo domain.UserRepository,
+	authUseCase domain.AuthUseCase, auditRecorder domain.AuditRecorder, jwtModule jwt.JwtInterface,
+	redis redis.Interface, time commonTime.TimeInterface, config config.Config, client queue.Interface,
+	mailer mail.Interface) *AccountUseCase {
+	return &AccountUseCase{
+		accountRepo:   accountRepo,
+		userRepo:      userRepo,
+		authUseCase:   authUseCase,
+		auditRecorder: auditRecorder,
+		jwtModule:     jwtModule,
+		redis:         redis,
+		time:          time,
+		config:        config,
+		client:        client,
+		mailer:        mailer,
+	}
+}
+
+// RequestExport enqueues the export of the personal data of the current user, at most once per
+// ExportRequestInterval. The user is emailed a download link once the archive is ready.
+func (u *AccountUseCase) RequestExport(ctx context.Context) (err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		return authCommon.ErrAuthUnauthenticated
+	}
+
+	if _, ok = general.GetActorIDFromCtx(ctx); ok {
+		return authCommon.ErrImpersonationNotAllowed
+	}
+
+	ok, err = u.redis.SetNX(exportThrottleCacheKey(userID), "1", int(common.ExportRequestInterval.Seconds()))
+	if err != nil {
+		return fmt.Errorf("throttle export err: %+v", err)
+	}
+
+	if !ok {
+		return common.ErrExportTooFrequent
+	}
+
+	payload, err := json.Marshal(common.ExportPayload{
+		UserID:   userID,
+		ExportID: uuid.New().String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = u.client.EnqueueTaskContext(ctx, asynq.NewTask(common.TypeExportPersonalData, payload))
+	if err != nil {
+		return fmt.Errorf("enqueue export err: %+v", err)
+	}
+
+	u.auditRecorder.Record(ctx, domain.AuditEvent{Type: auditCommon.EventAccountExport})
+	return nil
+}
+
+// DownloadExport returns the archive the download link points to, as long as the link is valid and the account
+// wasn't deleted since.
+func (u *AccountUseCase) DownloadExport(ctx context.Context, token string) (archive []byte, err error) {
+	jwtData, err := u.jwtModule.ExtractToken(ctx, token, common.ExportTokenType)
+	if errors.Is(err, jwt.ErrTokenInvalid) || errors.Is(err, jwt.ErrTokenExpired) {
+		err = common.ErrExportInvalid
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("extract export token err: %+v", err)
+		return
+	}
+
+	if _, err = u.userRepo.FindUserByID(ctx, jwtData.IdentityID); err != nil {
+		err = common.ErrExportInvalid
+		return
+	}
+
+	reply, err := u.redis.Get(exportCacheKey(jwtData.TokenID))
+	if errors.Is(err, redis.ErrNilReturned) {
+		err = common.ErrExportInvalid
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("get export err: %+v", err)
+		return
+	}
+
+	return []byte(fmt.Sprint(reply)), nil
+}
+
+// DeleteAccount soft deletes the current user and revokes all of its sessions, once the user reauthenticated
+// with the password or a second factor code. The account and its data are purged for good once the deletion
+// grace period is over.
+func (u *AccountUseCase) DeleteAccount(ctx context.Context, password, code string) (
+	deletion common.AccountDeletion, err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = authCommon.ErrAuthUnauthenticated
+		return
+	}
+
+	if _, ok = general.GetActorIDFromCtx(ctx); ok {
+		err = authCommon.ErrImpersonationNotAllowed
+		return
+	}
+
+	// a leaked api key mustn't be enough to delete the account
+	if _, ok = general.GetApiKeyIDFromCtx(ctx); ok {
+		err = authCommon.ErrApiKeyNotAllowed
+		return
+	}
+
+	// a stolen session mustn't be enough either
+	if err = u.authUseCase.Reauthenticate(ctx, password, code); err != nil {
+		return
+	}
+
+	// deleted first, so the user can't log in again even if revoking a session fails
+	if err = u.userRepo.DeleteUser(ctx, userID); err != nil {
+		err = fmt.Errorf("delete user err: %+v", err)
+		return
+	}
+
+	if _, err = u.authUseCase.LogoutAll(ctx); err != nil {
+		return
+	}
+
+	log.Printf("account deleted: user_id=%d", userID)
+	u.auditRecorder.Record(ctx, domain.AuditEvent{Type: auditCommon.EventAccountDeletion})
+
+	deletion = common.AccountDeletion{
+		Message: "Account deleted",
+		PurgeAt: u.time.Now().Add(u.config.Account.DeletionGracePeriod),
+	}
+	return
+}
+
+// PurgeDeletedAccounts deletes accounts whose deletion grace period is over for good, along with their data.
+func (u *AccountUseCase) PurgeDeletedAccounts(ctx context.Context) (err error) {
+	userIDs, err := u.accountRepo.FindUserIDsDeletedBefore(u.time.Now().Add(-u.config.Account.DeletionGracePeriod))
+	if err != nil {
+		return fmt.Errorf("find deleted users err: %+v", err)
+	}
+
+	for _, userID := range userIDs {
+		if err = u.accountRepo.PurgeUser(userID); err != nil {
+			return fmt.Errorf("purge user err: %+v", err)
+		}
+
+		log.Printf("account purged: user_id=%d", userID)
+		u.auditRecorder.Record(ctx, domain.AuditEvent{
+			Type:   auditCommon.EventAccountPurge,
+			UserID: userID,
+		})
+	}
+
+	return nil
+}
+
+func exportCacheKey(exportID string) string {
+	return fmt.Sprintf("account-export:%s", exportID)
+}
+
+func exportThrottleCacheKey(userID int64) string {
+	return fmt.Sprintf("account-export-throttle:%d", userID)
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/db"
+	"gorm.io/gorm"
+	"time"
+)
+
+type ApiKeyRepository struct {
+	dbClient *db.DatabaseConnection
+	time     commonTime.TimeInterface
+}
+
+func NewApiKeyRepository(dbClient *db.DatabaseConnection, time commonTime.TimeInterface) *ApiKeyRepository {
+	return &ApiKeyRepository{
+		dbClient: dbClient,
+		time:     time,
+	}
+}
+
+func (r *ApiKeyRepository) InsertApiKey(apiKey *domain.ApiKey) (err error) {
+	result := r.dbClient.Master.Create(apiKey)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no row affected")
+	}
+
+	return nil
+}
+
+func (r *ApiKeyRepository) FindApiKeyByPrefix(prefix string) (domain.ApiKey, error) {
+	var apiKey domain.ApiKey
+
+	result := r.dbClient.Slave.Where("prefix = ?", prefix).First(&apiKey)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.ApiKey{}, common.ErrApiKeyNotFound
+	}
+
+	if result.Error != nil {
+		return domain.ApiKey{}, result.Error
+	}
+
+	return apiKey, nil
+}
+
+func (r *ApiKeyRepository) FindApiKeysByUserID(userID int64) ([]domain.ApiKey, error) {
+	var apiKeys []domain.ApiKey
+
+	result := r.dbClient.Slave.Where("user_id = ?", userID).Order("created_at DESC").Find(&apiKeys)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return apiKeys, nil
+}
+
+func (r *ApiKeyRepository) UpdateApiKeyLastUsedAt(id int64, lastUsedAt time.Time) (err error) {
+	result := r.dbClient.Master.Model(&domain.ApiKey{}).Where("id = ?", id).Update("last_used_at", lastUsedAt)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+// DeleteApiKey deletes the key only if it belongs to the given user
+func (r *ApiKeyRepository) DeleteApiKey(userID, id int64) (err error) {
+	result := r.dbClient.Master.Where("id = ? AND user_id = ?", id, userID).Delete(&domain.ApiKey{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return common.ErrApiKeyNotFound
+	}
+
+	return nil
+}
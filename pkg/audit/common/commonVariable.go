@@ -0,0 +1,78 @@
+package common
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	ErrTimeRangeInvalid      = fmt.Errorf("from must be before to")
+	ErrOrganizationForbidden = fmt.Errorf("events of another organization can't be listed within an organization")
+)
+
+// Audit event types
+const (
+	EventLoginSuccess     = "login.success"
+	EventLoginFailure     = "login.failure"
+	EventRegister         = "register"
+	EventLogout           = "logout"
+	EventPasswordChange   = "password.change"
+	EventTokenRefresh     = "token.refresh"
+	EventTokenReuse       = "token.reuse"
+	EventPermissionDenied = "permission.denied"
+
+	EventLoginNewDevice        = "login.new_device"
+	EventLoginImpossibleTravel = "login.impossible_travel"
+	EventLoginReported         = "login.reported"
+
+	EventImpersonationStart  = "impersonation.start"
+	EventImpersonatedRequest = "impersonation.request"
+
+	EventAccountExport   = "account.export"
+	EventAccountDeletion = "account.deletion"
+	EventAccountPurge    = "account.purge"
+
+	EventInvitationCreated  = "invitation.created"
+	EventInvitationRevoked  = "invitation.revoked"
+	EventInvitationAccepted = "invitation.accepted"
+)
+
+// A list of task types.
+const (
+	TypeRecordEvent = "audit:record"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+type ListEventsRequest struct {
+	OrganizationID int64      `form:"organization_id" validate:"omitempty,gt=0"`
+	UserID         int64      `form:"user_id" validate:"omitempty,gt=0"`
+	ActorID        int64      `form:"actor_id" validate:"omitempty,gt=0"`
+	Type           string     `form:"type"`
+	From           *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To             *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page           int        `form:"page" validate:"omitempty,gt=0"`
+	PageSize       int        `form:"page_size" validate:"omitempty,gt=0,lte=200"`
+}
+
+// Pagination returns the requested page and page size, defaulting to the first page of DefaultPageSize events
+func (r ListEventsRequest) Pagination() (page, pageSize int) {
+	page, pageSize = r.Page, r.PageSize
+
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	}
+
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	return page, pageSize
+}
@@ -0,0 +1,31 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestListEventsRequest_Pagination(t *testing.T) {
+	testCases := []struct {
+		name             string
+		request          ListEventsRequest
+		expectedPage     int
+		expectedPageSize int
+	}{
+		{name: "defaults", request: ListEventsRequest{}, expectedPage: 1, expectedPageSize: DefaultPageSize},
+		{name: "given", request: ListEventsRequest{Page: 3, PageSize: 20}, expectedPage: 3, expectedPageSize: 20},
+		{name: "negative", request: ListEventsRequest{Page: -1, PageSize: -5}, expectedPage: 1,
+			expectedPageSize: DefaultPageSize},
+		{name: "capped", request: ListEventsRequest{Page: 2, PageSize: 1000}, expectedPage: 2,
+			expectedPageSize: MaxPageSize},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, pageSize := tc.request.Pagination()
+
+			assert.Equal(t, tc.expectedPage, page)
+			assert.Equal(t, tc.expectedPageSize, pageSize)
+		})
+	}
+}
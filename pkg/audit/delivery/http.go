@@ -0,0 +1,92 @@
+package delivery
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	rbacCommon "github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	httputil "github.com/lactobasilusprotectus/go-template/pkg/util/http"
+)
+
+type AuditHttpHandler struct {
+	authMiddleware domain.GinAuthentication
+	auditUseCase   domain.AuditUseCase
+}
+
+func NewAuditHttpHandler(authMiddleware domain.GinAuthentication, auditUseCase domain.AuditUseCase) *AuditHttpHandler {
+	return &AuditHttpHandler{
+		authMiddleware: authMiddleware,
+		auditUseCase:   auditUseCase,
+	}
+}
+
+func (a *AuditHttpHandler) Register(g *gin.Engine) {
+	admin := g.Group("admin", a.authMiddleware.MustLogin())
+
+	admin.GET("audit-events", a.authMiddleware.RequirePermission(rbacCommon.PermissionAuditRead), a.ListEvents)
+}
+
+// ListEvents			godoc
+//
+//	@Summary		List audit events.
+//	@Description	List authentication audit events, most recent first. Events are written asynchronously and
+//	@Description	may take a moment to show up. Within an organization, only the events of that organization are
+//	@Description	listed.
+//	@Produce		application/json
+//	@Tags			admin
+//	@Security		JWT
+//	@Param			organization_id	query		int		false	"Organization ID"
+//	@Param			user_id			query		int		false	"User ID"
+//	@Param			actor_id		query		int		false	"Impersonating Admin ID"
+//	@Param			type			query		string	false	"Event Type"
+//	@Param			from			query		string	false	"From (RFC 3339), inclusive"
+//	@Param			to				query		string	false	"To (RFC 3339), exclusive"
+//	@Param			page			query		int		false	"Page, starting at 1"
+//	@Param			page_size		query		int		false	"Page Size, at most 200"
+//	@Success		200				{object}	http.BaseResponse{data=domain.AuditEventPage}
+//	@Failure		400				{object}	http.BaseResponse
+//	@Failure		401				{object}	http.BaseResponse
+//	@Failure		403				{object}	http.BaseResponse
+//	@Failure		500				{object}	http.BaseResponse
+//	@Router			/admin/audit-events [get]
+func (a *AuditHttpHandler) ListEvents(c *gin.Context) {
+	// init request
+	var listRequest common.ListEventsRequest
+
+	//bind request query
+	if err := c.ShouldBindQuery(&listRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request
+	if err := validator.New().Struct(&listRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	page, err := a.auditUseCase.ListEvents(c.Request.Context(), listRequest)
+
+	// handle error
+	if errors.Is(err, common.ErrTimeRangeInvalid) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrOrganizationForbidden) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, page)
+	return
+}
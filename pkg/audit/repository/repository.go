@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"fmt"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/db"
+	"gorm.io/gorm"
+	"time"
+)
+
+type AuditRepository struct {
+	dbClient *db.DatabaseConnection
+	time     commonTime.TimeInterface
+}
+
+func NewAuditRepository(dbClient *db.DatabaseConnection, time commonTime.TimeInterface) *AuditRepository {
+	return &AuditRepository{
+		dbClient: dbClient,
+		time:     time,
+	}
+}
+
+func (r *AuditRepository) InsertAuditEvent(event domain.AuditEvent) (err error) {
+	result := r.dbClient.Master.Create(&event)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no row affected")
+	}
+
+	return nil
+}
+
+// FindAuditEvents returns one page of the matching events, most recent first, along with the count of every match
+func (r *AuditRepository) FindAuditEvents(filter domain.AuditEventFilter) ([]domain.AuditEvent, int64, error) {
+	query := r.dbClient.Slave.Model(&domain.AuditEvent{})
+
+	if filter.OrganizationID != 0 {
+		query = query.Where("organization_id = ?", filter.OrganizationID)
+	}
+
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	// shared by the count and the page queries
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if result := query.Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	var events []domain.AuditEvent
+
+	result := query.Order("created_at DESC").Order("id DESC").Offset(filter.Offset).Limit(filter.Limit).Find(&events)
+
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return events, total, nil
+}
+
+func (r *AuditRepository) DeleteAuditEventsBefore(before time.Time) (int64, error) {
+	result := r.dbClient.Master.Where("created_at < ?", before).Delete(&domain.AuditEvent{})
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
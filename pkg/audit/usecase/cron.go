@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"github.com/lactobasilusprotectus/go-template/pkg/util/cronjob"
+)
+
+func (u *AuditUseCase) RegisterCron(c *cronjob.Cron) {
+	// zero retention keeps events forever
+	if u.config.Audit.Retention <= 0 || u.config.Audit.RetentionSchedule == "" {
+		return
+	}
+
+	c.AddFunc("audit-retention", u.config.Audit.RetentionSchedule, u.PurgeEvents)
+}
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hibiken/asynq"
+	"github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/queue"
+)
+
+func (u *AuditUseCase) RegisterQueue(as *queue.AsynqServer) {
+	as.AddHandlerFunc(common.TypeRecordEvent, u.HandleRecordEvent)
+}
+
+// HandleRecordEvent writes an audit event enqueued by Record.
+func (u *AuditUseCase) HandleRecordEvent(ctx context.Context, task *asynq.Task) error {
+	var event domain.AuditEvent
+	if err := json.Unmarshal(task.Payload(), &event); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if err := u.auditRepo.InsertAuditEvent(event); err != nil {
+		return fmt.Errorf("insert audit event err: %+v", err)
+	}
+
+	return nil
+}
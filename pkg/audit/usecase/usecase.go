@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hibiken/asynq"
+	"github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/queue"
+	"log"
+)
+
+type AuditUseCase struct {
+	auditRepo domain.AuditRepository
+	client    queue.Interface
+	time      commonTime.TimeInterface
+	config    config.Config
+}
+
+func NewAuditUseCase(auditRepo domain.AuditRepository, client queue.Interface, time commonTime.TimeInterface,
+	config config.Config) *AuditUseCase {
+	return &AuditUseCase{
+		auditRepo: auditRepo,
+		client:    client,
+		time:      time,
+		config:    config,
+	}
+}
+
+// Record enqueues the event to be written by the queue server, so auditing never slows down or fails the request.
+// User, session and client information missing from the event are taken from the context.
+func (u *AuditUseCase) Record(ctx context.Context, event domain.AuditEvent) {
+	if event.UserID == 0 {
+		event.UserID, _ = general.GetUserIDFromCtx(ctx)
+	}
+
+	if event.ActorID == 0 {
+		event.ActorID, _ = general.GetActorIDFromCtx(ctx)
+	}
+
+	if event.SessionID == "" {
+		event.SessionID, _ = general.GetSessionIDFromCtx(ctx)
+	}
+
+	if event.OrganizationID == 0 {
+		event.OrganizationID, _ = general.GetTenantIDFromCtx(ctx)
+	}
+
+	event.UserAgent, _ = general.GetUserAgentFromCtx(ctx)
+	event.ClientIP, _ = general.GetClientIPFromCtx(ctx)
+	event.CreatedAt = u.time.Now()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("marshal audit event err: %+v", err)
+		return
+	}
+
+	// not bound to the request context, a client going away mustn't drop the event
+	_, err = u.client.EnqueueTask(asynq.NewTask(common.TypeRecordEvent, payload))
+	if err != nil {
+		// keep the event in the logs rather than losing it
+		log.Printf("enqueue audit event err: %+v, event=%s", err, payload)
+	}
+}
+
+// ListEvents returns one page of the audit events matching the request, most recent first. Within an organization,
+// only the events of that organization are listed.
+func (u *AuditUseCase) ListEvents(ctx context.Context, request common.ListEventsRequest) (page domain.AuditEventPage,
+	err error) {
+	if request.From != nil && request.To != nil && !request.From.Before(*request.To) {
+		err = common.ErrTimeRangeInvalid
+		return
+	}
+
+	organizationID := request.OrganizationID
+	if tenantID, ok := general.GetTenantIDFromCtx(ctx); ok && tenantID != 0 {
+		if organizationID != 0 && organizationID != tenantID {
+			err = common.ErrOrganizationForbidden
+			return
+		}
+
+		organizationID = tenantID
+	}
+
+	page.Page, page.PageSize = request.Pagination()
+
+	page.Events, page.Total, err = u.auditRepo.FindAuditEvents(domain.AuditEventFilter{
+		OrganizationID: organizationID,
+		UserID:         request.UserID,
+		ActorID:        request.ActorID,
+		Type:           request.Type,
+		From:           request.From,
+		To:             request.To,
+		Offset:         (page.Page - 1) * page.PageSize,
+		Limit:          page.PageSize,
+	})
+	if err != nil {
+		err = fmt.Errorf("find audit events err: %+v", err)
+		return
+	}
+
+	if page.Events == nil {
+		page.Events = []domain.AuditEvent{}
+	}
+
+	return
+}
+
+// PurgeEvents deletes events older than the configured retention.
+func (u *AuditUseCase) PurgeEvents(ctx context.Context) (err error) {
+	count, err := u.auditRepo.DeleteAuditEventsBefore(u.time.Now().Add(-u.config.Audit.Retention))
+	if err != nil {
+		return fmt.Errorf("delete audit events err: %+v", err)
+	}
+
+	log.Printf("audit events purged: count=%d", count)
+	return nil
+}
@@ -7,34 +7,182 @@ import (
 
 var (
 	ErrAuthUnauthenticated = fmt.Errorf("unauthenticated")
-	ErrEmailNotFound       = fmt.Errorf("email not found")
-	ErrWrongPassword       = fmt.Errorf("wrong password")
+	ErrInvalidCredentials  = fmt.Errorf("invalid credentials")
+	ErrAccountLocked       = fmt.Errorf("too many failed attempts, account temporarily locked")
 	ErrUserNotFound        = fmt.Errorf("user not found")
+	ErrRefreshTokenInvalid = fmt.Errorf("refresh token invalid")
+	ErrRefreshTokenReused  = fmt.Errorf("refresh token reused")
+	ErrSessionNotFound     = fmt.Errorf("session not found")
+	ErrResetTokenInvalid   = fmt.Errorf("reset token invalid or expired")
+	ErrVerifyTokenInvalid  = fmt.Errorf("verification token invalid or expired")
+	ErrEmailNotVerified    = fmt.Errorf("email not verified")
+	ErrTooManyRequests     = fmt.Errorf("too many requests, try again later")
+	ErrMfaTokenInvalid     = fmt.Errorf("mfa token invalid or expired")
+	ErrMfaCodeInvalid      = fmt.Errorf("mfa code invalid")
+	ErrTotpAlreadyEnabled  = fmt.Errorf("totp already enabled")
+	ErrTotpNotEnrolled     = fmt.Errorf("totp enrollment not started")
+	ErrTotpNotEnabled      = fmt.Errorf("totp not enabled")
+	ErrApiKeyNotFound      = fmt.Errorf("api key not found")
+	ErrApiKeyScopeInvalid  = fmt.Errorf("api key scope not granted to user")
+	ErrApiKeyExpiryInvalid = fmt.Errorf("api key expiry must be in the future")
+	ErrApiKeyNotAllowed    = fmt.Errorf("api keys can't be managed with an api key")
+	ErrIdentityNotFound    = fmt.Errorf("identity not found")
+	ErrOidcProviderUnknown = fmt.Errorf("identity provider not configured")
+	ErrOidcStateInvalid    = fmt.Errorf("login state invalid or expired")
+	ErrOidcLoginFailed     = fmt.Errorf("identity provider login failed")
+	ErrOidcEmailRequired   = fmt.Errorf("identity provider didn't share an email")
+	ErrOidcAccountConflict = fmt.Errorf("an account with this email already exists")
+	ErrMagicLinkInvalid    = fmt.Errorf("magic link invalid or expired")
+	ErrDeviceNotFound      = fmt.Errorf("device not found")
+	ErrLoginRecordNotFound = fmt.Errorf("login record not found")
+	ErrLoginReportInvalid  = fmt.Errorf("login report link invalid or expired")
+
+	ErrImpersonationNotAllowed    = fmt.Errorf("not allowed while impersonating")
+	ErrImpersonationTargetInvalid = fmt.Errorf("user can't be impersonated")
 )
 
 const (
-	AccessTokenType      = "access_token"
-	RefreshTokenType     = "refresh_token"
-	AccessTokenLifetime  = time.Minute * 5    // 5 mins
-	RefreshTokenLifetime = time.Hour * 24 * 2 // 48 hours
+	AccessTokenType         = "access_token"
+	RefreshTokenType        = "refresh_token"
+	MfaPendingTokenType     = "mfa_pending"
+	MagicLinkTokenType      = "magic_link"
+	LoginReportTokenType    = "login_report"
+	AccessTokenLifetime     = time.Minute * 5    // 5 mins
+	RefreshTokenLifetime    = time.Hour * 24 * 2 // 48 hours
+	MfaPendingTokenLifetime = time.Minute * 5    // 5 mins
+
+	// sessions are extended on refresh, but never past SessionLifetime after the login
+	SessionLifetime = time.Hour * 24 * 30 // 30 days
+
+	// impersonation tokens are access tokens without refresh token, the longest lived ones
+	ImpersonationTokenLifetime = time.Minute * 15 // 15 mins
+
+	MfaMaxAttempts    = 5
+	RecoveryCodeCount = 10
+
+	PasswordResetTokenLifetime  = time.Minute * 30 // 30 mins
+	PasswordResetResendInterval = time.Minute      // 1 min
+
+	EmailVerificationTokenLifetime  = time.Hour * 24 // 24 hours
+	EmailVerificationResendInterval = time.Minute    // 1 min
 
 	SessionInvalidated = "1"
+
+	OidcStateLifetime = time.Minute * 10 // 10 mins
+
+	MagicLinkTokenLifetime  = time.Minute * 15 // 15 mins
+	MagicLinkResendInterval = time.Minute      // 1 min
+
+	// the "this wasn't me" link of new sign-in emails outlives refresh tokens, as sessions are extended on refresh
+	LoginReportTokenLifetime = time.Hour * 24 * 7 // 7 days
+	LoginHistoryLimit        = 50
+
+	ApiKeyPrefix              = "gtk_"
+	ApiKeyLookupLength        = 12          // hex encoded lookup part following ApiKeyPrefix
+	ApiKeyLastUsedResolution  = time.Minute // last used time is only written once per resolution
+	SessionLastSeenResolution = time.Minute // last seen time is only written once per resolution
 )
 
 // A list of task types.
 const (
 	TypeWelcomeEmail  = "email:welcome"
 	TypeReminderEmail = "email:reminder"
+
+	TypePasswordResetEmail = "email:password-reset"
+	TypeVerificationEmail  = "email:verification"
+	TypeMagicLinkEmail     = "email:magic-link"
+	TypeNewSignInEmail     = "email:new-sign-in"
 )
 
 type LoginToken struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+
+	// set instead of the tokens above in the cookie mode, to be sent back in the X-CSRF-Token header
+	CsrfToken string `json:"csrf_token,omitempty"`
+
+	// set instead of the tokens above when a second factor is required, see POST /login/mfa
+	MfaRequired bool   `json:"mfa_required,omitempty"`
+	MfaToken    string `json:"mfa_token,omitempty"`
 }
 
 type LoginInfo struct {
-	UserID    int64  `json:"user_id"`
-	SessionID string `json:"session_uuid"`
+	UserID    int64     `json:"user_id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Age       int       `json:"age"`
+	SessionID string    `json:"session_uuid"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// organization the request is made within, 0 outside any organization
+	OrganizationID int64 `json:"organization_id"`
+
+	// set when an admin is acting as the user, so the client can show a banner
+	Impersonated   bool  `json:"impersonated"`
+	ImpersonatorID int64 `json:"impersonator_id,omitempty"`
+}
+
+// TokenIntrospection describes a first-party token, fields other than Active are only set for active tokens
+type TokenIntrospection struct {
+	Active    bool
+	UserID    int64
+	SessionID string
+	TokenID   string
+	TokenType string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+type SessionInfo struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	ClientIP   string    `json:"client_ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
+type ApiKeyInfo struct {
+	ID             int64      `json:"id"`
+	Name           string     `json:"name"`
+	Prefix         string     `json:"prefix"`
+	Scopes         []string   `json:"scopes"`
+	OrganizationID int64      `json:"organization_id"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// CreatedApiKey is returned once on creation, the key itself can't be retrieved later
+type CreatedApiKey struct {
+	ApiKeyInfo
+	Key string `json:"key"`
+}
+
+type CreateApiKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"omitempty,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type OidcAuthorization struct {
+	AuthorizationURL string `json:"authorization_url"`
+	StateBinding     string `json:"-"` // kept in a cookie, the callback must send it back along with the state
+}
+
+type OidcCallbackRequest struct {
+	Code  string `form:"code" validate:"required"`
+	State string `form:"state" validate:"required"`
+}
+
+type ImpersonateRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// ImpersonationToken is a short-lived access token acting as another user, it can't be refreshed
+type ImpersonationToken struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
 }
 
 type LogoutInfo struct {
@@ -46,9 +194,92 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required,min=6"`
 }
 
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"` // left out in the cookie mode
+}
+
 type RegisterRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Username string `json:"username" validate:"required,min=6"`
-	Password string `json:"password" validate:"required,min=6"`
+	Password string `json:"password" validate:"required"`
 	Age      int    `json:"age" validate:"required,gt=8"`
+
+	InvitationCode string `json:"invitation_code"` // required in the invite registration mode
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// PasswordResetPayload is the payload of TypePasswordResetEmail task, the token is issued by the task itself
+type PasswordResetPayload struct {
+	Email string `json:"email"`
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type MagicLinkCallbackRequest struct {
+	Token string `form:"token" validate:"required"`
+}
+
+// MagicLinkPayload is the payload of TypeMagicLinkEmail task
+type MagicLinkPayload struct {
+	Email string `json:"email"`
+	Token string `json:"token"`
+}
+
+type LoginReportRequest struct {
+	Token string `json:"token" form:"token" validate:"required"`
+}
+
+// NewSignInPayload is the payload of TypeNewSignInEmail task
+type NewSignInPayload struct {
+	Email            string    `json:"email"`
+	UserAgent        string    `json:"user_agent"`
+	ClientIP         string    `json:"client_ip"`
+	Country          string    `json:"country"`
+	SignedInAt       time.Time `json:"signed_in_at"`
+	NewDevice        bool      `json:"new_device"`
+	ImpossibleTravel bool      `json:"impossible_travel"`
+	Token            string    `json:"token"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type UnlockAccountRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// EmailVerificationPayload is the payload of TypeVerificationEmail task
+type EmailVerificationPayload struct {
+	Email string `json:"email"`
+	Token string `json:"token"`
+}
+
+type LoginMfaRequest struct {
+	MfaToken     string `json:"mfa_token" validate:"required"`
+	Code         string `json:"code" validate:"required_without=RecoveryCode"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code"`
+}
+
+type TotpCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TotpEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
 }
@@ -1,41 +1,87 @@
 package delivery
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/password"
 	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	invitationCommon "github.com/lactobasilusprotectus/go-template/pkg/invitation/common"
+	organizationCommon "github.com/lactobasilusprotectus/go-template/pkg/organization/common"
+	rbacCommon "github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
 	_ "github.com/lactobasilusprotectus/go-template/pkg/util/http"
 	httputil "github.com/lactobasilusprotectus/go-template/pkg/util/http"
+	"strconv"
 )
 
 type AuthHttpHandler struct {
 	authMiddleware domain.GinAuthentication
 	authUseCase    domain.AuthUseCase
+	cookieConfig   config.CookieConfig
 }
 
-func NewAuthHttpHandler(authMiddleware domain.GinAuthentication, authUseCase domain.AuthUseCase) *AuthHttpHandler {
+func NewAuthHttpHandler(authMiddleware domain.GinAuthentication, authUseCase domain.AuthUseCase,
+	cookieConfig config.CookieConfig) *AuthHttpHandler {
 	return &AuthHttpHandler{
 		authMiddleware: authMiddleware,
 		authUseCase:    authUseCase,
+		cookieConfig:   cookieConfig,
 	}
 }
 
 func (a *AuthHttpHandler) Register(g *gin.Engine) {
 	g.POST("login", a.Login)
+	g.POST("login/mfa", a.LoginMfa)
+	g.POST("login/magic-link", a.RequestMagicLink)
+	g.GET("login/magic-link/callback", a.MagicLinkCallback)
+	g.GET("login/report", a.FindReportedLogin)
+	g.POST("login/report", a.ReportLogin)
+	g.POST("refresh", a.Refresh)
+	g.POST("logout", a.authMiddleware.MustLogin(), a.Logout)
+	g.POST("logout-all", a.authMiddleware.MustLogin(), a.LogoutAll)
+	g.GET("me", a.authMiddleware.MustLogin(), a.Me)
+	g.GET("sessions", a.authMiddleware.MustLogin(), a.ListSessions)
+	g.DELETE("sessions/:id", a.authMiddleware.MustLogin(), a.RevokeSession)
+	g.GET("logins", a.authMiddleware.MustLogin(), a.ListLoginHistory)
+	g.POST("organizations/:id/switch", a.authMiddleware.MustLogin(), a.SwitchOrganization)
+	g.POST("mfa/totp/enroll", a.authMiddleware.MustLogin(), a.EnrollTotp)
+	g.POST("mfa/totp/confirm", a.authMiddleware.MustLogin(), a.ConfirmTotp)
+	g.POST("mfa/totp/disable", a.authMiddleware.MustLogin(), a.DisableTotp)
+	g.POST("api-keys", a.authMiddleware.MustLogin(), a.CreateApiKey)
+	g.GET("api-keys", a.authMiddleware.MustLogin(), a.ListApiKeys)
+	g.DELETE("api-keys/:id", a.authMiddleware.MustLogin(), a.RevokeApiKey)
+	g.GET("oidc/providers", a.ListOidcProviders)
+	g.GET("oidc/:provider/login", a.OidcAuthorize)
+	g.GET("oidc/:provider/callback", a.OidcCallback)
 	g.POST("register", a.Regis)
 	g.POST("send-email", a.SendEmail)
+	g.POST("password/forgot", a.ForgotPassword)
+	g.POST("password/reset", a.ResetPassword)
+	g.GET("verify-email", a.VerifyEmail)
+	g.POST("verify-email/resend", a.ResendVerificationEmail)
+	g.POST("admin/users/unlock", a.authMiddleware.MustLogin(),
+		a.authMiddleware.RequirePermission(rbacCommon.PermissionUsersUnlock), a.UnlockAccount)
+	g.POST("admin/users/:id/impersonate", a.authMiddleware.MustLogin(),
+		a.authMiddleware.RequirePermission(rbacCommon.PermissionUsersImpersonate), a.Impersonate)
 }
 
 // Login				godoc
 //
 //	@Summary		Login user to get token.
-//	@Description	Login user to get token.
+//	@Description	Login user to get token, users with two-factor authentication get an mfa token instead.
+//	@Description	In the cookie mode, the token pair is set as HttpOnly cookies and only a CSRF token is returned,
+//	@Description	to be sent back in the X-CSRF-Token header of state-changing requests.
 //	@Produce		application/json
 //	@Tags			auth
 //	@Param			body	body		common.LoginRequest	true	"Login Request"
 //	@Success		200		{object}	http.BaseResponse
 //	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		429		{object}	http.BaseResponse
 //	@Failure		500		{object}	http.BaseResponse
 //	@Router			/login [post]
 func (a *AuthHttpHandler) Login(c *gin.Context) {
@@ -55,91 +101,1421 @@ func (a *AuthHttpHandler) Login(c *gin.Context) {
 	}
 
 	// call use case
-	token, err := a.authUseCase.Login(c, loginRequest.Email, loginRequest.Password)
+	token, err := a.authUseCase.Login(c.Request.Context(), loginRequest.Email, loginRequest.Password)
 
 	// handle error
+	if errors.Is(err, common.ErrInvalidCredentials) {
+		httputil.WriteUnauthenticatedResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrTooManyRequests) || errors.Is(err, common.ErrAccountLocked) {
+		httputil.WriteTooManyRequestsResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrEmailNotVerified) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	a.writeLoginToken(c, token)
+	return
+}
+
+// RequestMagicLink		godoc
+//
+//	@Summary		Request a login link.
+//	@Description	Email a single use login link, which only works along with the nonce cookie set on the
+//	@Description	requesting browser. The response doesn't reveal whether the email is registered.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Param			body	body		common.MagicLinkRequest	true	"Magic Link Request"
+//	@Success		200		{object}	http.BaseResponse
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		429		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/login/magic-link [post]
+func (a *AuthHttpHandler) RequestMagicLink(c *gin.Context) {
+	// init request body
+	var magicLinkRequest common.MagicLinkRequest
+
+	//bind request body
+	if err := c.ShouldBindJSON(&magicLinkRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&magicLinkRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	nonce, err := a.authUseCase.RequestMagicLink(c.Request.Context(), magicLinkRequest.Email)
+
+	// handle error
+	if errors.Is(err, common.ErrTooManyRequests) {
+		httputil.WriteTooManyRequestsResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.SetBindingCookie(c, a.cookieConfig, general.MagicLinkNonceCookie, general.MagicLinkNonceCookiePath,
+		nonce, common.MagicLinkTokenLifetime)
+	httputil.WriteOkResponse(c, "If the email is registered, a login link has been sent")
+	return
+}
+
+// MagicLinkCallback		godoc
+//
+//	@Summary		Login with a login link.
+//	@Description	Consume the emailed login link for login token, users with two-factor authentication get an
+//	@Description	mfa token instead. The nonce cookie set when the link was requested must come along.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Param			token	query		string	true	"Login Link Token"
+//	@Success		200		{object}	http.BaseResponse{data=common.LoginToken}
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/login/magic-link/callback [get]
+func (a *AuthHttpHandler) MagicLinkCallback(c *gin.Context) {
+	// init request
+	var callbackRequest common.MagicLinkCallbackRequest
+
+	//bind request query
+	if err := c.ShouldBindQuery(&callbackRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request
+	if err := validator.New().Struct(&callbackRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	nonce, _ := c.Cookie(general.MagicLinkNonceCookie)
+
+	// call use case
+	token, err := a.authUseCase.MagicLinkCallback(c.Request.Context(), callbackRequest.Token, nonce)
+
+	// handle error
+	if errors.Is(err, common.ErrMagicLinkInvalid) {
+		httputil.WriteUnauthenticatedResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.ClearBindingCookie(c, a.cookieConfig, general.MagicLinkNonceCookie, general.MagicLinkNonceCookiePath)
+	a.writeLoginToken(c, token)
+	return
+}
+
+// LoginMfa			godoc
+//
+//	@Summary		Complete login with second factor.
+//	@Description	Exchange mfa token returned by login and a TOTP or recovery code for a token pair. Wrong codes
+//	@Description	count towards the lockout of the account, like wrong passwords.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Param			body	body		common.LoginMfaRequest	true	"Login MFA Request"
+//	@Success		200		{object}	http.BaseResponse
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		429		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/login/mfa [post]
+func (a *AuthHttpHandler) LoginMfa(c *gin.Context) {
+	// init request body
+	var mfaRequest common.LoginMfaRequest
+
+	//bind request body
+	if err := c.ShouldBindJSON(&mfaRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&mfaRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	token, err := a.authUseCase.LoginMfa(c.Request.Context(), mfaRequest.MfaToken, mfaRequest.Code,
+		mfaRequest.RecoveryCode)
+
+	// handle error
+	if errors.Is(err, common.ErrMfaTokenInvalid) || errors.Is(err, common.ErrMfaCodeInvalid) {
+		httputil.WriteUnauthenticatedResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrTooManyRequests) || errors.Is(err, common.ErrAccountLocked) {
+		httputil.WriteTooManyRequestsResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	a.writeLoginToken(c, token)
+	return
+}
+
+// Refresh				godoc
+//
+//	@Summary		Exchange refresh token for a new token pair.
+//	@Description	Rotate refresh token, a reused refresh token revokes the whole session. In the cookie mode, the
+//	@Description	refresh token cookie is used and the body can be left out.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Param			body	body		common.RefreshRequest	true	"Refresh Request"
+//	@Success		200		{object}	http.BaseResponse
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/refresh [post]
+func (a *AuthHttpHandler) Refresh(c *gin.Context) {
+	// init request body
+	var refreshRequest common.RefreshRequest
+
+	//bind request body, browsers of the cookie mode send the refresh token as a cookie instead
+	if cookie, err := c.Cookie(general.RefreshTokenCookie); a.cookieConfig.Enabled && err == nil {
+		refreshRequest.RefreshToken = cookie
+	} else if err = c.ShouldBindJSON(&refreshRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&refreshRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	token, err := a.authUseCase.Refresh(c.Request.Context(), refreshRequest.RefreshToken)
+
+	// handle error
+	if errors.Is(err, common.ErrRefreshTokenInvalid) || errors.Is(err, common.ErrRefreshTokenReused) {
+		if a.cookieConfig.Enabled {
+			httputil.ClearAuthCookies(c, a.cookieConfig)
+		}
+
+		httputil.WriteUnauthenticatedResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	a.writeLoginToken(c, token)
+	return
+}
+
+// Logout				godoc
+//
+//	@Summary		Logout current session.
+//	@Description	Revoke the session of the given token.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Security		JWT
+//	@Success		200	{object}	http.BaseResponse
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		403	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/logout [post]
+func (a *AuthHttpHandler) Logout(c *gin.Context) {
+	// call use case
+	info, err := a.authUseCase.Logout(c.Request.Context())
+
+	// handle error
+	if errors.Is(err, common.ErrApiKeyNotAllowed) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	if a.cookieConfig.Enabled {
+		httputil.ClearAuthCookies(c, a.cookieConfig)
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, info)
+	return
+}
+
+// LogoutAll			godoc
+//
+//	@Summary		Logout every session.
+//	@Description	Revoke every session of the current user.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Security		JWT
+//	@Success		200	{object}	http.BaseResponse
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		403	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/logout-all [post]
+func (a *AuthHttpHandler) LogoutAll(c *gin.Context) {
+	// call use case
+	info, err := a.authUseCase.LogoutAll(c.Request.Context())
+
+	// handle error
+	if errors.Is(err, common.ErrApiKeyNotAllowed) || errors.Is(err, common.ErrImpersonationNotAllowed) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	if a.cookieConfig.Enabled {
+		httputil.ClearAuthCookies(c, a.cookieConfig)
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, info)
+	return
+}
+
+// Me					godoc
+//
+//	@Summary		Get current user.
+//	@Description	Get profile and session information of the current user, flagged when an admin is impersonating.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Security		JWT
+//	@Success		200	{object}	http.BaseResponse{data=common.LoginInfo}
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		404	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/me [get]
+func (a *AuthHttpHandler) Me(c *gin.Context) {
+	// call use case
+	info, err := a.authUseCase.Info(c.Request.Context())
+
+	// handle error
+	if errors.Is(err, common.ErrUserNotFound) {
+		httputil.WriteNotFoundResponse(c, httputil.ResponseNotFoundError)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, info)
+	return
+}
+
+// ListSessions		godoc
+//
+//	@Summary		List active sessions.
+//	@Description	List every active session (device) of the current user.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Security		JWT
+//	@Success		200	{object}	http.BaseResponse{data=[]common.SessionInfo}
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/sessions [get]
+func (a *AuthHttpHandler) ListSessions(c *gin.Context) {
+	// call use case
+	sessions, err := a.authUseCase.ListSessions(c.Request.Context())
+
+	// handle error
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, sessions)
+	return
+}
+
+// RevokeSession		godoc
+//
+//	@Summary		Revoke a session.
+//	@Description	Revoke one of the sessions (devices) of the current user.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Security		JWT
+//	@Param			id	path		string	true	"Session ID"
+//	@Success		200	{object}	http.BaseResponse
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		403	{object}	http.BaseResponse
+//	@Failure		404	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/sessions/{id} [delete]
+func (a *AuthHttpHandler) RevokeSession(c *gin.Context) {
+	// call use case
+	err := a.authUseCase.RevokeSession(c.Request.Context(), c.Param("id"))
+
+	// handle error
+	if errors.Is(err, common.ErrApiKeyNotAllowed) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrSessionNotFound) {
+		httputil.WriteNotFoundResponse(c, httputil.ResponseNotFoundError)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "Session revoked")
+	return
+}
+
+// ListLoginHistory	godoc
+//
+//	@Summary		List login history.
+//	@Description	List the latest logins of the current user, flagging those from a new device or an unlikely place.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Security		JWT
+//	@Success		200	{object}	http.BaseResponse{data=[]domain.LoginRecord}
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/logins [get]
+func (a *AuthHttpHandler) ListLoginHistory(c *gin.Context) {
+	// call use case
+	records, err := a.authUseCase.ListLoginHistory(c.Request.Context())
+
+	// handle error
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, records)
+	return
+}
+
+// FindReportedLogin	godoc
+//
+//	@Summary		Show a login to report.
+//	@Description	Show the login the "this wasn't me" link of the new sign-in email is about, for the user to confirm
+//	@Description	reporting it with POST /login/report. Nothing is logged out by opening the link.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Param			token	query		string	true	"Login Report Token"
+//	@Success		200		{object}	http.BaseResponse{data=domain.LoginRecord}
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/login/report [get]
+func (a *AuthHttpHandler) FindReportedLogin(c *gin.Context) {
+	// init request
+	var reportRequest common.LoginReportRequest
+
+	//bind request query
+	if err := c.ShouldBindQuery(&reportRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request
+	if err := validator.New().Struct(&reportRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	record, err := a.authUseCase.FindReportedLogin(c.Request.Context(), reportRequest.Token)
+
+	// handle error
+	if errors.Is(err, common.ErrLoginReportInvalid) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, record)
+	return
+}
+
+// ReportLogin			godoc
+//
+//	@Summary		Report a login.
+//	@Description	Log out the session of a login the user didn't make, once the user confirms it on the page of the
+//	@Description	"this wasn't me" link of the new sign-in email.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Param			body	body		common.LoginReportRequest	true	"Login Report Request"
+//	@Success		200		{object}	http.BaseResponse
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/login/report [post]
+func (a *AuthHttpHandler) ReportLogin(c *gin.Context) {
+	// init request body
+	var reportRequest common.LoginReportRequest
+
+	//bind request body
+	if err := c.ShouldBindJSON(&reportRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request
+	if err := validator.New().Struct(&reportRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	err := a.authUseCase.ReportLogin(c.Request.Context(), reportRequest.Token)
+
+	// handle error
+	if errors.Is(err, common.ErrLoginReportInvalid) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "Session logged out, change your password to keep your account safe")
+	return
+}
+
+// CreateApiKey		godoc
+//
+//	@Summary		Create an api key.
+//	@Description	Create an api key of the current user, the key is only shown in this response.
+//	@Description	Send it as "Authorization: ApiKey <key>".
+//	@Produce		application/json
+//	@Tags			auth
+//	@Security		JWT
+//	@Param			body	body		common.CreateApiKeyRequest	true	"Create Api Key Request"
+//	@Success		200		{object}	http.BaseResponse{data=common.CreatedApiKey}
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/api-keys [post]
+func (a *AuthHttpHandler) CreateApiKey(c *gin.Context) {
+	// init request body
+	var createRequest common.CreateApiKeyRequest
+
+	//bind request body
+	if err := c.ShouldBindJSON(&createRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&createRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	key, err := a.authUseCase.CreateApiKey(c.Request.Context(), createRequest)
+
+	// handle error
+	if errors.Is(err, common.ErrApiKeyExpiryInvalid) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrApiKeyScopeInvalid) || errors.Is(err, common.ErrApiKeyNotAllowed) ||
+		errors.Is(err, common.ErrImpersonationNotAllowed) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, key)
+	return
+}
+
+// ListApiKeys			godoc
+//
+//	@Summary		List api keys.
+//	@Description	List api keys of the current user.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Security		JWT
+//	@Success		200	{object}	http.BaseResponse{data=[]common.ApiKeyInfo}
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/api-keys [get]
+func (a *AuthHttpHandler) ListApiKeys(c *gin.Context) {
+	// call use case
+	keys, err := a.authUseCase.ListApiKeys(c.Request.Context())
+
+	// handle error
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, keys)
+	return
+}
+
+// RevokeApiKey		godoc
+//
+//	@Summary		Revoke an api key.
+//	@Description	Revoke one of the api keys of the current user.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Security		JWT
+//	@Param			id	path		int	true	"Api Key ID"
+//	@Success		200	{object}	http.BaseResponse
+//	@Failure		400	{object}	http.BaseResponse
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		403	{object}	http.BaseResponse
+//	@Failure		404	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/api-keys/{id} [delete]
+func (a *AuthHttpHandler) RevokeApiKey(c *gin.Context) {
+	apiKeyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	err = a.authUseCase.RevokeApiKey(c.Request.Context(), apiKeyID)
+
+	// handle error
+	if errors.Is(err, common.ErrApiKeyNotFound) {
+		httputil.WriteNotFoundResponse(c, httputil.ResponseNotFoundError)
+		return
+	}
+
+	if errors.Is(err, common.ErrApiKeyNotAllowed) || errors.Is(err, common.ErrImpersonationNotAllowed) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "Api key revoked")
+	return
+}
+
+// ListOidcProviders	godoc
+//
+//	@Summary		List identity providers.
+//	@Description	List names of the external identity providers users can log in with.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Success		200	{object}	http.BaseResponse{data=[]string}
+//	@Router			/oidc/providers [get]
+func (a *AuthHttpHandler) ListOidcProviders(c *gin.Context) {
+	// call use case
+	providers := a.authUseCase.ListOidcProviders(c.Request.Context())
+
+	// write response
+	httputil.WriteOkResponse(c, providers)
+	return
+}
+
+// OidcAuthorize		godoc
+//
+//	@Summary		Start login with an identity provider.
+//	@Description	Return the url of the identity provider to send the user to. The provider sends the user back
+//	@Description	to the configured redirect url with code and state, to be passed to the callback endpoint.
+//	@Description	The login is bound to the browser by a short-lived cookie the callback requires.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Param			provider	path		string	true	"Provider Name"
+//	@Success		200			{object}	http.BaseResponse{data=common.OidcAuthorization}
+//	@Failure		404			{object}	http.BaseResponse
+//	@Failure		500			{object}	http.BaseResponse
+//	@Router			/oidc/{provider}/login [get]
+func (a *AuthHttpHandler) OidcAuthorize(c *gin.Context) {
+	// call use case
+	authorization, err := a.authUseCase.OidcAuthorize(c.Request.Context(), c.Param("provider"))
+
+	// handle error
+	if errors.Is(err, common.ErrOidcProviderUnknown) {
+		httputil.WriteNotFoundResponse(c, httputil.ResponseNotFoundError)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.SetBindingCookie(c, a.cookieConfig, general.OidcStateCookie, general.OidcStateCookiePath,
+		authorization.StateBinding, common.OidcStateLifetime)
+	httputil.WriteOkResponse(c, authorization)
+	return
+}
+
+// OidcCallback			godoc
+//
+//	@Summary		Complete login with an identity provider.
+//	@Description	Exchange code and state sent back by the identity provider for login token, the state cookie
+//	@Description	set by the login endpoint must come along. The identity is linked to the local user with the
+//	@Description	same verified email, or a new user is created. A pending invitation of the email is accepted,
+//	@Description	in the invite registration mode only invited emails get a user.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Param			provider	path		string	true	"Provider Name"
+//	@Param			code		query		string	true	"Authorization Code"
+//	@Param			state		query		string	true	"State"
+//	@Success		200			{object}	http.BaseResponse{data=common.LoginToken}
+//	@Failure		400			{object}	http.BaseResponse
+//	@Failure		401			{object}	http.BaseResponse
+//	@Failure		403			{object}	http.BaseResponse
+//	@Failure		404			{object}	http.BaseResponse
+//	@Failure		409			{object}	http.BaseResponse
+//	@Failure		500			{object}	http.BaseResponse
+//	@Router			/oidc/{provider}/callback [get]
+func (a *AuthHttpHandler) OidcCallback(c *gin.Context) {
+	// init request
+	var callbackRequest common.OidcCallbackRequest
+
+	//bind request query
+	if err := c.ShouldBindQuery(&callbackRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request
+	if err := validator.New().Struct(&callbackRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// the state binding is single use like the state
+	stateBinding, _ := c.Cookie(general.OidcStateCookie)
+	httputil.ClearBindingCookie(c, a.cookieConfig, general.OidcStateCookie, general.OidcStateCookiePath)
+
+	// call use case
+	token, err := a.authUseCase.OidcCallback(c.Request.Context(), c.Param("provider"), callbackRequest.State,
+		stateBinding, callbackRequest.Code)
+
+	// handle error
+	if errors.Is(err, common.ErrOidcProviderUnknown) {
+		httputil.WriteNotFoundResponse(c, httputil.ResponseNotFoundError)
+		return
+	}
+
+	if errors.Is(err, common.ErrOidcStateInvalid) || errors.Is(err, common.ErrOidcEmailRequired) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrOidcLoginFailed) {
+		httputil.WriteUnauthenticatedResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrEmailNotVerified) || errors.Is(err, invitationCommon.ErrInvitationRequired) ||
+		errors.Is(err, invitationCommon.ErrInvitationInvalid) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrOidcAccountConflict) {
+		httputil.WriteConflictResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	a.writeLoginToken(c, token)
+	return
+}
+
+// Regis				godoc
+//
+//	@Summary		Regis user.
+//	@Description	Regis user. An invitation code is required in the invite registration mode, invited users get
+//	@Description	the role and organization of their invitation.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Param			body	body		common.RegisterRequest	true	"Registration Request"
+//	@Success		200		{object}	http.BaseResponse
+//	@Failure		400		{object}	http.BaseResponse{data=[]http.FieldError}
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/register [post]
+func (a *AuthHttpHandler) Regis(c *gin.Context) {
+	// init request body
+	var regisRequest common.RegisterRequest
+
+	//bind request body
+	if err := c.ShouldBindJSON(&regisRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&regisRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	user := domain.User{
+		Email:    regisRequest.Email,
+		Password: regisRequest.Password,
+		Username: regisRequest.Username,
+		Age:      regisRequest.Age,
+	}
+
+	err := a.authUseCase.Register(c.Request.Context(), user, regisRequest.InvitationCode)
+
+	if errors.Is(err, invitationCommon.ErrInvitationRequired) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, invitationCommon.ErrInvitationInvalid) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		httputil.WriteValidationErrorResponse(c, passwordFieldErrors(policyErr))
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	httputil.WriteOkResponse(c, "Register success")
+	return
+}
+
+func (a *AuthHttpHandler) SendEmail(c *gin.Context) {
+	// init request body
+	var loginRequest common.LoginRequest
+
+	//bind request body
+	if err := c.ShouldBindJSON(&loginRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&loginRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	err := a.authUseCase.SendEmail(c, loginRequest)
+
+	// handle error
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "Email sent")
+	return
+}
+
+// ForgotPassword		godoc
+//
+//	@Summary		Request password reset.
+//	@Description	Email a password reset link, the response doesn't reveal whether the email is registered.
+//	@Description	A link can be requested once a minute per email, it replaces the links sent before.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Param			body	body		common.ForgotPasswordRequest	true	"Forgot Password Request"
+//	@Success		200		{object}	http.BaseResponse
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		429		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/password/forgot [post]
+func (a *AuthHttpHandler) ForgotPassword(c *gin.Context) {
+	// init request body
+	var forgotRequest common.ForgotPasswordRequest
+
+	//bind request body
+	if err := c.ShouldBindJSON(&forgotRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&forgotRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	err := a.authUseCase.ForgotPassword(c.Request.Context(), forgotRequest.Email)
+
+	// handle error
+	if errors.Is(err, common.ErrTooManyRequests) {
+		httputil.WriteTooManyRequestsResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "If the email is registered, a reset link has been sent")
+	return
+}
+
+// ResetPassword		godoc
+//
+//	@Summary		Reset password.
+//	@Description	Set a new password using the emailed reset token, every session of the user is revoked.
+//	@Description	Passwords rejected by the policy are listed as field errors and leave the token usable.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Param			body	body		common.ResetPasswordRequest	true	"Reset Password Request"
+//	@Success		200		{object}	http.BaseResponse
+//	@Failure		400		{object}	http.BaseResponse{data=[]http.FieldError}
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/password/reset [post]
+func (a *AuthHttpHandler) ResetPassword(c *gin.Context) {
+	// init request body
+	var resetRequest common.ResetPasswordRequest
+
+	//bind request body
+	if err := c.ShouldBindJSON(&resetRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&resetRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	err := a.authUseCase.ResetPassword(c.Request.Context(), resetRequest.Token, resetRequest.Password)
+
+	// handle error
+	if errors.Is(err, common.ErrResetTokenInvalid) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		httputil.WriteValidationErrorResponse(c, passwordFieldErrors(policyErr))
+		return
+	}
+
 	if err != nil {
 		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
 		return
 	}
 
 	// write response
-	httputil.WriteOkResponse(c, token)
+	httputil.WriteOkResponse(c, "Password has been reset")
 	return
 }
 
-// Regis				godoc
+// VerifyEmail			godoc
 //
-//	@Summary		Regis user.
-//	@Description	Regis user.
+//	@Summary		Verify email.
+//	@Description	Verify email address using the token sent on registration.
 //	@Produce		application/json
 //	@Tags			auth
-//	@Param			body	body		common.RegisterRequest	true	"Registration Request"
+//	@Param			token	query		string	true	"Verification Token"
 //	@Success		200		{object}	http.BaseResponse
 //	@Failure		400		{object}	http.BaseResponse
 //	@Failure		500		{object}	http.BaseResponse
-//	@Router			/register [post]
-func (a *AuthHttpHandler) Regis(c *gin.Context) {
+//	@Router			/verify-email [get]
+func (a *AuthHttpHandler) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+
+	if token == "" {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, common.ErrVerifyTokenInvalid)
+		return
+	}
+
+	// call use case
+	err := a.authUseCase.VerifyEmail(c.Request.Context(), token)
+
+	// handle error
+	if errors.Is(err, common.ErrVerifyTokenInvalid) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "Email verified")
+	return
+}
+
+// ResendVerificationEmail	godoc
+//
+//	@Summary		Resend verification email.
+//	@Description	Resend verification email, the response doesn't reveal whether the email is registered.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Param			body	body		common.ResendVerificationRequest	true	"Resend Verification Request"
+//	@Success		200		{object}	http.BaseResponse
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		429		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/verify-email/resend [post]
+func (a *AuthHttpHandler) ResendVerificationEmail(c *gin.Context) {
 	// init request body
-	var regisRequest common.RegisterRequest
+	var resendRequest common.ResendVerificationRequest
 
 	//bind request body
-	if err := c.ShouldBindJSON(&regisRequest); err != nil {
+	if err := c.ShouldBindJSON(&resendRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&resendRequest); err != nil {
 		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
 		return
+	}
+
+	// call use case
+	err := a.authUseCase.ResendVerificationEmail(c.Request.Context(), resendRequest.Email)
+
+	// handle error
+	if errors.Is(err, common.ErrTooManyRequests) {
+		httputil.WriteTooManyRequestsResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "If the email is registered and not verified yet, a verification link has been sent")
+	return
+}
+
+// EnrollTotp			godoc
+//
+//	@Summary		Start TOTP enrollment.
+//	@Description	Generate TOTP secret and otpauth URI, TOTP is enabled once confirmed.
+//	@Produce		application/json
+//	@Tags			mfa
+//	@Security		JWT
+//	@Success		200	{object}	http.BaseResponse{data=common.TotpEnrollment}
+//	@Failure		400	{object}	http.BaseResponse
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		403	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/mfa/totp/enroll [post]
+func (a *AuthHttpHandler) EnrollTotp(c *gin.Context) {
+	// call use case
+	enrollment, err := a.authUseCase.EnrollTotp(c.Request.Context())
+
+	// handle error
+	if errors.Is(err, common.ErrApiKeyNotAllowed) || errors.Is(err, common.ErrImpersonationNotAllowed) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrTotpAlreadyEnabled) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, enrollment)
+	return
+}
+
+// ConfirmTotp			godoc
+//
+//	@Summary		Confirm TOTP enrollment.
+//	@Description	Enable TOTP using a code from the authenticator, recovery codes are returned only once.
+//	@Produce		application/json
+//	@Tags			mfa
+//	@Security		JWT
+//	@Param			body	body		common.TotpCodeRequest	true	"TOTP Code Request"
+//	@Success		200		{object}	http.BaseResponse{data=common.RecoveryCodes}
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		429		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/mfa/totp/confirm [post]
+func (a *AuthHttpHandler) ConfirmTotp(c *gin.Context) {
+	// init request body
+	var codeRequest common.TotpCodeRequest
 
+	//bind request body
+	if err := c.ShouldBindJSON(&codeRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
 	}
 
 	// validate request body
-	if err := validator.New().Struct(&regisRequest); err != nil {
+	if err := validator.New().Struct(&codeRequest); err != nil {
 		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
 		return
 	}
 
-	user := domain.User{
-		Email:    regisRequest.Email,
-		Password: regisRequest.Password,
-		Username: regisRequest.Username,
-		Age:      regisRequest.Age,
+	// call use case
+	codes, err := a.authUseCase.ConfirmTotp(c.Request.Context(), codeRequest.Code)
+
+	// handle error
+	if errors.Is(err, common.ErrApiKeyNotAllowed) || errors.Is(err, common.ErrImpersonationNotAllowed) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
 	}
 
-	err := a.authUseCase.Register(user)
+	if errors.Is(err, common.ErrTooManyRequests) || errors.Is(err, common.ErrAccountLocked) {
+		httputil.WriteTooManyRequestsResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrTotpAlreadyEnabled) || errors.Is(err, common.ErrTotpNotEnrolled) ||
+		errors.Is(err, common.ErrMfaCodeInvalid) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
 
 	if err != nil {
 		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
 		return
 	}
 
-	httputil.WriteOkResponse(c, "Register success")
+	// write response
+	httputil.WriteOkResponse(c, codes)
 	return
 }
 
-func (a *AuthHttpHandler) SendEmail(c *gin.Context) {
+// DisableTotp			godoc
+//
+//	@Summary		Disable TOTP.
+//	@Description	Disable TOTP using a code from the authenticator or a recovery code.
+//	@Produce		application/json
+//	@Tags			mfa
+//	@Security		JWT
+//	@Param			body	body		common.TotpCodeRequest	true	"TOTP Code Request"
+//	@Success		200		{object}	http.BaseResponse
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		429		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/mfa/totp/disable [post]
+func (a *AuthHttpHandler) DisableTotp(c *gin.Context) {
 	// init request body
-	var loginRequest common.LoginRequest
+	var codeRequest common.TotpCodeRequest
 
 	//bind request body
-	if err := c.ShouldBindJSON(&loginRequest); err != nil {
+	if err := c.ShouldBindJSON(&codeRequest); err != nil {
 		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
 		return
 	}
 
 	// validate request body
-	if err := validator.New().Struct(&loginRequest); err != nil {
+	if err := validator.New().Struct(&codeRequest); err != nil {
 		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
 		return
 	}
 
 	// call use case
-	err := a.authUseCase.SendEmail(c, loginRequest)
+	err := a.authUseCase.DisableTotp(c.Request.Context(), codeRequest.Code)
 
 	// handle error
+	if errors.Is(err, common.ErrApiKeyNotAllowed) || errors.Is(err, common.ErrImpersonationNotAllowed) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrTooManyRequests) || errors.Is(err, common.ErrAccountLocked) {
+		httputil.WriteTooManyRequestsResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrTotpNotEnabled) || errors.Is(err, common.ErrMfaCodeInvalid) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
 	if err != nil {
 		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
 		return
 	}
 
 	// write response
-	httputil.WriteOkResponse(c, "Email sent")
+	httputil.WriteOkResponse(c, "TOTP disabled")
+	return
+}
+
+// UnlockAccount		godoc
+//
+//	@Summary		Unlock account.
+//	@Description	Clear failed login attempts and lockout of the given account.
+//	@Produce		application/json
+//	@Tags			admin
+//	@Security		JWT
+//	@Param			body	body		common.UnlockAccountRequest	true	"Unlock Account Request"
+//	@Success		200		{object}	http.BaseResponse
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/admin/users/unlock [post]
+func (a *AuthHttpHandler) UnlockAccount(c *gin.Context) {
+	// init request body
+	var unlockRequest common.UnlockAccountRequest
+
+	//bind request body
+	if err := c.ShouldBindJSON(&unlockRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&unlockRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	err := a.authUseCase.UnlockAccount(c.Request.Context(), unlockRequest.Email)
+
+	// handle error
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "Account unlocked")
+	return
+}
+
+// SwitchOrganization	godoc
+//
+//	@Summary		Switch organization.
+//	@Description	Scope the current session to another organization of the user, returning a new token pair.
+//	@Description	Tokens issued for the previous organization stop working right away.
+//	@Produce		application/json
+//	@Tags			organization
+//	@Security		JWT
+//	@Param			id	path		int	true	"Organization ID"
+//	@Success		200	{object}	http.BaseResponse{data=common.LoginToken}
+//	@Failure		400	{object}	http.BaseResponse
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		403	{object}	http.BaseResponse
+//	@Failure		404	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/organizations/{id}/switch [post]
+func (a *AuthHttpHandler) SwitchOrganization(c *gin.Context) {
+	organizationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	token, err := a.authUseCase.SwitchOrganization(c.Request.Context(), organizationID)
+
+	// handle error
+	if errors.Is(err, organizationCommon.ErrOrganizationNotFound) {
+		httputil.WriteNotFoundResponse(c, httputil.ResponseNotFoundError)
+		return
+	}
+
+	if errors.Is(err, common.ErrApiKeyNotAllowed) || errors.Is(err, common.ErrImpersonationNotAllowed) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrAuthUnauthenticated) {
+		httputil.WriteUnauthenticatedResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	a.writeLoginToken(c, token)
+	return
+}
+
+// Impersonate			godoc
+//
+//	@Summary		Impersonate a user.
+//	@Description	Issue a short lived access token to act as the given user, for support. The token can't be
+//	@Description	refreshed, every request made with it is audited along with the impersonating admin.
+//	@Description	Users holding permissions the admin lacks, or the permission to impersonate, can't be
+//	@Description	impersonated.
+//	@Produce		application/json
+//	@Tags			admin
+//	@Security		JWT
+//	@Param			id		path		int							true	"User ID"
+//	@Param			body	body		common.ImpersonateRequest	true	"Impersonate Request"
+//	@Success		200		{object}	http.BaseResponse{data=common.ImpersonationToken}
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		404		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/admin/users/{id}/impersonate [post]
+func (a *AuthHttpHandler) Impersonate(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// init request body
+	var impersonateRequest common.ImpersonateRequest
+
+	//bind request body
+	if err = c.ShouldBindJSON(&impersonateRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err = validator.New().Struct(&impersonateRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	token, err := a.authUseCase.Impersonate(c.Request.Context(), userID, impersonateRequest.Reason)
+
+	// handle error
+	if errors.Is(err, common.ErrUserNotFound) {
+		httputil.WriteNotFoundResponse(c, httputil.ResponseNotFoundError)
+		return
+	}
+
+	if errors.Is(err, common.ErrImpersonationTargetInvalid) || errors.Is(err, common.ErrImpersonationNotAllowed) ||
+		errors.Is(err, common.ErrApiKeyNotAllowed) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, token)
 	return
 }
+
+// list password policy violations as errors of the password field
+func passwordFieldErrors(policyErr *password.PolicyError) (fieldErrors []httputil.FieldError) {
+	for _, violation := range policyErr.Violations {
+		fieldErrors = append(fieldErrors, httputil.FieldError{
+			Field:   "password",
+			Rule:    violation.Rule,
+			Message: violation.Message,
+		})
+	}
+
+	return fieldErrors
+}
+
+// write the login token, in the cookie mode the token pair goes into cookies instead of the response body
+func (a *AuthHttpHandler) writeLoginToken(c *gin.Context, token common.LoginToken) {
+	// tokens waiting for a second factor are no login yet
+	if a.cookieConfig.Enabled && token.AccessToken != "" {
+		csrfToken, err := httputil.SetAuthCookies(c, a.cookieConfig, token.AccessToken, token.RefreshToken,
+			common.RefreshTokenLifetime)
+		if err != nil {
+			httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+			return
+		}
+
+		token = common.LoginToken{CsrfToken: csrfToken}
+	}
+
+	httputil.WriteOkResponse(c, token)
+}
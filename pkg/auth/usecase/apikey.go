@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	rbacCommon "github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	"log"
+	"strings"
+)
+
+// CreateApiKey creates a new api key of the current user, the key is only ever returned here.
+func (a *AuthUseCase) CreateApiKey(ctx context.Context, request common.CreateApiKeyRequest) (key common.CreatedApiKey,
+	err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = common.ErrAuthUnauthenticated
+		return
+	}
+
+	// a scoped key must not be able to mint a broader one
+	if _, ok = general.GetApiKeyIDFromCtx(ctx); ok {
+		err = common.ErrApiKeyNotAllowed
+		return
+	}
+
+	// nor an impersonation outlive its short lifetime
+	if err = rejectImpersonation(ctx); err != nil {
+		return
+	}
+
+	now := a.time.Now()
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(now) {
+		err = common.ErrApiKeyExpiryInvalid
+		return
+	}
+
+	// scopes can only narrow down what the user is allowed to do
+	if len(request.Scopes) > 0 {
+		granted, err := a.rbacRepo.FindPermissionsByUserID(userID)
+		if err != nil {
+			return key, fmt.Errorf("find permissions err: %+v", err)
+		}
+
+		for _, scope := range request.Scopes {
+			if !rbacCommon.PermissionGranted(granted, scope) {
+				return key, common.ErrApiKeyScopeInvalid
+			}
+		}
+	}
+
+	plainKey, prefix, err := generateApiKey()
+	if err != nil {
+		err = fmt.Errorf("generate api key err: %+v", err)
+		return
+	}
+
+	// the key is bound to the organization it is created within
+	organizationID, _ := general.GetTenantIDFromCtx(ctx)
+
+	apiKey := domain.ApiKey{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Name:           request.Name,
+		Prefix:         prefix,
+		KeyHash:        general.HashToken(plainKey),
+		Scopes:         strings.Join(request.Scopes, " "),
+		ExpiresAt:      request.ExpiresAt,
+		CreatedAt:      now,
+	}
+
+	if err = a.apiKeyRepo.InsertApiKey(&apiKey); err != nil {
+		err = fmt.Errorf("insert api key err: %+v", err)
+		return
+	}
+
+	log.Printf("api key created: user_id=%d, api_key_id=%d", userID, apiKey.ID)
+
+	key.ApiKeyInfo = toApiKeyInfo(apiKey)
+	key.Key = plainKey
+	return
+}
+
+// ListApiKeys lists api keys of the current user.
+func (a *AuthUseCase) ListApiKeys(ctx context.Context) (keys []common.ApiKeyInfo, err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = common.ErrAuthUnauthenticated
+		return
+	}
+
+	apiKeys, err := a.apiKeyRepo.FindApiKeysByUserID(userID)
+	if err != nil {
+		err = fmt.Errorf("find api keys err: %+v", err)
+		return
+	}
+
+	keys = make([]common.ApiKeyInfo, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		keys = append(keys, toApiKeyInfo(apiKey))
+	}
+
+	return
+}
+
+// reject requests authenticated with an api key, for actions only a logged in user may take
+func rejectApiKey(ctx context.Context) error {
+	if _, ok := general.GetApiKeyIDFromCtx(ctx); ok {
+		return common.ErrApiKeyNotAllowed
+	}
+
+	return nil
+}
+
+// RevokeApiKey deletes the given api key of the current user.
+func (a *AuthUseCase) RevokeApiKey(ctx context.Context, apiKeyID int64) (err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		return common.ErrAuthUnauthenticated
+	}
+
+	if _, ok = general.GetApiKeyIDFromCtx(ctx); ok {
+		return common.ErrApiKeyNotAllowed
+	}
+
+	if err = rejectImpersonation(ctx); err != nil {
+		return
+	}
+
+	err = a.apiKeyRepo.DeleteApiKey(userID, apiKeyID)
+	if errors.Is(err, common.ErrApiKeyNotFound) {
+		return err
+	}
+
+	if err != nil {
+		return fmt.Errorf("delete api key err: %+v", err)
+	}
+
+	log.Printf("api key revoked: user_id=%d, api_key_id=%d", userID, apiKeyID)
+	return nil
+}
+
+// validate api key presented by a client, valid is false for unknown, mismatched or expired keys
+func (a *AuthUseCase) validateApiKey(plainKey string) (valid bool, apiKey domain.ApiKey, err error) {
+	prefix, ok := apiKeyPrefix(plainKey)
+	if !ok {
+		return
+	}
+
+	apiKey, err = a.apiKeyRepo.FindApiKeyByPrefix(prefix)
+	if errors.Is(err, common.ErrApiKeyNotFound) {
+		return false, apiKey, nil
+	}
+
+	if err != nil {
+		err = fmt.Errorf("find api key err: %+v", err)
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(general.HashToken(plainKey)), []byte(apiKey.KeyHash)) != 1 {
+		return
+	}
+
+	now := a.time.Now()
+	if apiKey.ExpiresAt != nil && !apiKey.ExpiresAt.After(now) {
+		return
+	}
+
+	// avoid writing on every request
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= common.ApiKeyLastUsedResolution {
+		if err = a.apiKeyRepo.UpdateApiKeyLastUsedAt(apiKey.ID, now); err != nil {
+			err = fmt.Errorf("update api key err: %+v", err)
+			return
+		}
+	}
+
+	valid = true
+	return
+}
+
+// generate key formatted as <ApiKeyPrefix><lookup>_<secret>, the lookup part is stored in plain for lookup
+func generateApiKey() (plainKey, prefix string, err error) {
+	lookup := make([]byte, common.ApiKeyLookupLength/2)
+	if _, err = rand.Read(lookup); err != nil {
+		return
+	}
+
+	secret, err := general.GenerateRandomToken(32)
+	if err != nil {
+		return
+	}
+
+	prefix = common.ApiKeyPrefix + hex.EncodeToString(lookup)
+	plainKey = prefix + "_" + secret
+	return
+}
+
+func apiKeyPrefix(plainKey string) (prefix string, ok bool) {
+	length := len(common.ApiKeyPrefix) + common.ApiKeyLookupLength
+	if !strings.HasPrefix(plainKey, common.ApiKeyPrefix) || len(plainKey) <= length+1 || plainKey[length] != '_' {
+		return "", false
+	}
+
+	return plainKey[:length], true
+}
+
+func toApiKeyInfo(apiKey domain.ApiKey) common.ApiKeyInfo {
+	return common.ApiKeyInfo{
+		ID:             apiKey.ID,
+		Name:           apiKey.Name,
+		Prefix:         apiKey.Prefix,
+		Scopes:         strings.Fields(apiKey.Scopes),
+		OrganizationID: apiKey.OrganizationID,
+		ExpiresAt:      apiKey.ExpiresAt,
+		LastUsedAt:     apiKey.LastUsedAt,
+		CreatedAt:      apiKey.CreatedAt,
+	}
+}
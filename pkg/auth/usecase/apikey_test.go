@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	rbacCommon "github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAuthUseCase_CreateApiKey(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	ctx := general.SetUserIDIntoCtx(context.Background(), 1)
+
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		request common.CreateApiKeyRequest
+		granted []string // permissions of the user, looked up for scoped keys only
+		err     error
+	}{
+		{name: "unscoped", ctx: ctx, request: common.CreateApiKeyRequest{Name: "ci"}},
+		{name: "scoped", ctx: ctx, request: common.CreateApiKeyRequest{Name: "ci",
+			Scopes: []string{rbacCommon.PermissionAuditRead}}, granted: []string{rbacCommon.PermissionAll}},
+		{name: "scope beyond the user permissions", ctx: ctx, request: common.CreateApiKeyRequest{Name: "ci",
+			Scopes: []string{rbacCommon.PermissionRolesAssign}}, granted: []string{rbacCommon.PermissionAuditRead},
+			err: common.ErrApiKeyScopeInvalid},
+		{name: "expired", ctx: ctx, request: common.CreateApiKeyRequest{Name: "ci", ExpiresAt: &past},
+			err: common.ErrApiKeyExpiryInvalid},
+		{name: "created with an api key", ctx: general.SetApiKeyIntoCtx(ctx, 2, nil),
+			request: common.CreateApiKeyRequest{Name: "ci"}, err: common.ErrApiKeyNotAllowed},
+		{name: "impersonating", ctx: general.SetActorIDIntoCtx(ctx, 9),
+			request: common.CreateApiKeyRequest{Name: "ci"}, err: common.ErrImpersonationNotAllowed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			apiKeyRepo := domain.NewMockApiKeyRepository(ctrl)
+			rbacRepo := domain.NewMockRbacRepository(ctrl)
+			timeMock := commonTime.NewMockTimeInterface(ctrl)
+
+			timeMock.EXPECT().Now().Return(now).AnyTimes()
+
+			if tc.granted != nil {
+				rbacRepo.EXPECT().FindPermissionsByUserID(int64(1)).Return(tc.granted, nil)
+			}
+
+			var stored domain.ApiKey
+			if tc.err == nil {
+				apiKeyRepo.EXPECT().InsertApiKey(gomock.Any()).DoAndReturn(func(apiKey *domain.ApiKey) error {
+					apiKey.ID = 7
+					stored = *apiKey
+					return nil
+				})
+			}
+
+			a := &AuthUseCase{apiKeyRepo: apiKeyRepo, rbacRepo: rbacRepo, time: timeMock}
+
+			key, err := a.CreateApiKey(tc.ctx, tc.request)
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			// only the hash of the key is stored
+			assert.NoError(t, err)
+			assert.Equal(t, int64(7), key.ID)
+			assert.ElementsMatch(t, tc.request.Scopes, key.Scopes)
+			assert.Equal(t, general.HashToken(key.Key), stored.KeyHash)
+
+			prefix, ok := apiKeyPrefix(key.Key)
+			assert.True(t, ok)
+			assert.Equal(t, prefix, stored.Prefix)
+		})
+	}
+}
+
+func TestAuthUseCase_authenticateApiKey(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	recent := now.Add(-time.Second)
+
+	plainKey, prefix, err := generateApiKey()
+	assert.NoError(t, err)
+
+	apiKey := domain.ApiKey{ID: 7, UserID: 1, OrganizationID: 5, Prefix: prefix, KeyHash: general.HashToken(plainKey),
+		Scopes: rbacCommon.PermissionAuditRead}
+
+	testCases := []struct {
+		name      string
+		key       string
+		apiKey    *domain.ApiKey // found by the prefix, nil when there's none
+		touched   bool           // last use gets recorded
+		userFound bool
+		err       error
+	}{
+		{name: "authenticated", key: plainKey, apiKey: &apiKey, touched: true, userFound: true},
+		{name: "used recently", key: plainKey, apiKey: func() *domain.ApiKey {
+			k := apiKey
+			k.LastUsedAt = &recent
+			return &k
+		}(), userFound: true},
+		{name: "malformed", key: "not-a-key", err: common.ErrAuthUnauthenticated},
+		{name: "unknown", key: plainKey, err: common.ErrAuthUnauthenticated},
+		{name: "wrong secret", key: prefix + "_secret", apiKey: &apiKey, err: common.ErrAuthUnauthenticated},
+		{name: "expired", key: plainKey, apiKey: func() *domain.ApiKey {
+			k := apiKey
+			k.ExpiresAt = &past
+			return &k
+		}(), err: common.ErrAuthUnauthenticated},
+		{name: "owner removed", key: plainKey, apiKey: &apiKey, touched: true, err: common.ErrAuthUnauthenticated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			apiKeyRepo := domain.NewMockApiKeyRepository(ctrl)
+			userRepo := domain.NewMockUserRepository(ctrl)
+			timeMock := commonTime.NewMockTimeInterface(ctrl)
+
+			timeMock.EXPECT().Now().Return(now).AnyTimes()
+
+			if tc.key != "not-a-key" {
+				if tc.apiKey == nil {
+					apiKeyRepo.EXPECT().FindApiKeyByPrefix(prefix).Return(domain.ApiKey{}, common.ErrApiKeyNotFound)
+				} else {
+					apiKeyRepo.EXPECT().FindApiKeyByPrefix(prefix).Return(*tc.apiKey, nil)
+				}
+			}
+
+			if tc.touched {
+				apiKeyRepo.EXPECT().UpdateApiKeyLastUsedAt(int64(7), now)
+			}
+
+			// the owner is only looked up for a valid key
+			if tc.userFound {
+				userRepo.EXPECT().FindUserByID(gomock.Any(), int64(1)).Return(domain.User{ID: 1}, nil)
+			} else if tc.touched {
+				userRepo.EXPECT().FindUserByID(gomock.Any(), int64(1)).Return(domain.User{}, errors.New("not found"))
+			}
+
+			a := &AuthUseCase{apiKeyRepo: apiKeyRepo, userRepo: userRepo, time: timeMock}
+
+			ctx, err := a.authenticateApiKey(context.Background(), tc.key)
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			// the key acts as its owner, within its organization and scopes only
+			assert.NoError(t, err)
+
+			userID, _ := general.GetUserIDFromCtx(ctx)
+			tenantID, _ := general.GetTenantIDFromCtx(ctx)
+			apiKeyID, _ := general.GetApiKeyIDFromCtx(ctx)
+			scopes, _ := general.GetScopesFromCtx(ctx)
+
+			assert.Equal(t, int64(1), userID)
+			assert.Equal(t, int64(5), tenantID)
+			assert.Equal(t, int64(7), apiKeyID)
+			assert.Equal(t, []string{rbacCommon.PermissionAuditRead}, scopes)
+		})
+	}
+}
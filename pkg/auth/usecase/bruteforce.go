@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"log"
+	"strings"
+	"time"
+)
+
+// UnlockAccount clears failed login attempts and lockout of the given account.
+func (a *AuthUseCase) UnlockAccount(ctx context.Context, email string) (err error) {
+	account := normalizeEmail(email)
+
+	err = a.redis.Del(
+		loginFailuresCacheKey("account", account),
+		loginBackoffCacheKey("account", account),
+		loginLockoutCacheKey("account", account),
+	)
+	if err != nil {
+		return fmt.Errorf("unlock account err: %+v", err)
+	}
+
+	log.Printf("account unlocked: email=%s", account)
+	return nil
+}
+
+// check the account and the client are neither locked out nor backing off
+func (a *AuthUseCase) checkLoginAllowed(email, clientIP string) (err error) {
+	for _, subject := range loginSubjects(email, clientIP) {
+		locked, err := a.keyExists(loginLockoutCacheKey(subject.kind, subject.value))
+		if err != nil {
+			return err
+		}
+
+		if locked {
+			return common.ErrAccountLocked
+		}
+
+		backingOff, err := a.keyExists(loginBackoffCacheKey(subject.kind, subject.value))
+		if err != nil {
+			return err
+		}
+
+		if backingOff {
+			return common.ErrTooManyRequests
+		}
+	}
+
+	return nil
+}
+
+// count failed attempt of the account and the client, delay next attempt exponentially
+// and lock out once the threshold is reached. It returns ErrInvalidCredentials on success.
+func (a *AuthUseCase) recordLoginFailure(email, clientIP string) (err error) {
+	cfg := a.config.LoginProtection
+
+	for _, subject := range loginSubjects(email, clientIP) {
+		failures, err := a.redis.Incr(loginFailuresCacheKey(subject.kind, subject.value),
+			int(cfg.AttemptWindow.Seconds()))
+		if err != nil {
+			return fmt.Errorf("count login failures err: %+v", err)
+		}
+
+		maxAttempts := cfg.MaxAccountAttempts
+		if subject.kind == "ip" {
+			maxAttempts = cfg.MaxIPAttempts
+		}
+
+		if maxAttempts > 0 && failures >= int64(maxAttempts) {
+			err = a.redis.Set(loginLockoutCacheKey(subject.kind, subject.value), "1", int(cfg.LockoutDuration.Seconds()))
+			if err != nil {
+				return fmt.Errorf("lock out login err: %+v", err)
+			}
+
+			log.Printf("login locked out: %s=%s, failures=%d", subject.kind, subject.value, failures)
+			continue
+		}
+
+		delay := backoffDelay(cfg.BackoffBase, failures, cfg.LockoutDuration)
+		if delay < time.Second {
+			continue
+		}
+
+		if err = a.redis.Set(loginBackoffCacheKey(subject.kind, subject.value), "1", int(delay.Seconds())); err != nil {
+			return fmt.Errorf("back off login err: %+v", err)
+		}
+	}
+
+	return common.ErrInvalidCredentials
+}
+
+// forget failed attempts of the account after a successful login
+func (a *AuthUseCase) resetLoginFailures(email string) (err error) {
+	account := normalizeEmail(email)
+
+	err = a.redis.Del(loginFailuresCacheKey("account", account), loginBackoffCacheKey("account", account))
+	if err != nil {
+		return fmt.Errorf("reset login failures err: %+v", err)
+	}
+
+	return nil
+}
+
+func (a *AuthUseCase) keyExists(key string) (exists bool, err error) {
+	_, err = a.redis.Get(key)
+
+	if errors.Is(err, redis.ErrNilReturned) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("get %s err: %+v", key, err)
+	}
+
+	return true, nil
+}
+
+// backoffDelay returns base * 2^(failures-1), capped at max
+func backoffDelay(base time.Duration, failures int64, max time.Duration) time.Duration {
+	if base <= 0 || failures <= 0 {
+		return 0
+	}
+
+	delay := base
+	for i := int64(1); i < failures; i++ {
+		delay *= 2
+
+		if delay >= max {
+			return max
+		}
+	}
+
+	return delay
+}
+
+type loginSubject struct {
+	kind  string
+	value string
+}
+
+// attempts are tracked per account and per client IP
+func loginSubjects(email, clientIP string) []loginSubject {
+	subjects := []loginSubject{{kind: "account", value: normalizeEmail(email)}}
+
+	if clientIP != "" {
+		subjects = append(subjects, loginSubject{kind: "ip", value: clientIP})
+	}
+
+	return subjects
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func loginFailuresCacheKey(kind, value string) string {
+	return fmt.Sprintf("login-failures:%s:%s", kind, value)
+}
+
+func loginBackoffCacheKey(kind, value string) string {
+	return fmt.Sprintf("login-backoff:%s:%s", kind, value)
+}
+
+func loginLockoutCacheKey(kind, value string) string {
+	return fmt.Sprintf("login-lockout:%s:%s", kind, value)
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/password"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestAuthUseCase_Login_bruteForce(t *testing.T) {
+	ctx := general.SetClientInfoIntoCtx(context.Background(), "agent", "1.2.3.4")
+	subjects := loginSubjects("user@mail.com", "1.2.3.4")
+
+	testCases := []struct {
+		name        string
+		lockedOut   string // kind of the subject locked out already
+		backingOff  string // kind of the subject backing off already
+		unknown     bool   // the email isn't registered
+		failures    int64  // failed attempts of both subjects, with this one
+		setLockout  bool   // the account gets locked out
+		backoffSecs []int  // backoff of the account and the client set by this attempt, 0 when none
+		err         error
+	}{
+		{name: "account locked out", lockedOut: "account", err: common.ErrAccountLocked},
+		{name: "client backing off", backingOff: "ip", err: common.ErrTooManyRequests},
+		{name: "first failure", failures: 1, backoffSecs: []int{1, 1}, err: common.ErrInvalidCredentials},
+		{name: "backoff doubles", failures: 3, backoffSecs: []int{4, 4}, err: common.ErrInvalidCredentials},
+		{name: "locks the account out", failures: 5, setLockout: true, backoffSecs: []int{0, 16},
+			err: common.ErrInvalidCredentials},
+		{name: "unknown email counted", unknown: true, failures: 1, backoffSecs: []int{1, 1},
+			err: common.ErrInvalidCredentials},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			redisMock := redis.NewMockInterface(ctrl)
+			userRepo := domain.NewMockUserRepository(ctrl)
+			hasher := password.NewMockInterface(ctrl)
+			auditRecorder := domain.NewMockAuditRecorder(ctrl)
+
+			auditRecorder.EXPECT().Record(gomock.Any(), gomock.Any())
+
+			// checked until a subject is locked out or backing off
+			for _, subject := range subjects {
+				if tc.lockedOut == subject.kind {
+					redisMock.EXPECT().Get(loginLockoutCacheKey(subject.kind, subject.value)).Return("1", nil)
+					break
+				}
+				redisMock.EXPECT().Get(loginLockoutCacheKey(subject.kind, subject.value)).
+					Return(nil, redis.ErrNilReturned)
+
+				if tc.backingOff == subject.kind {
+					redisMock.EXPECT().Get(loginBackoffCacheKey(subject.kind, subject.value)).Return("1", nil)
+					break
+				}
+				redisMock.EXPECT().Get(loginBackoffCacheKey(subject.kind, subject.value)).
+					Return(nil, redis.ErrNilReturned)
+			}
+
+			if tc.failures > 0 {
+				if tc.unknown {
+					userRepo.EXPECT().FindUserByEmail(gomock.Any(), "user@mail.com").
+						Return(domain.User{}, gorm.ErrRecordNotFound)
+					hasher.EXPECT().Verify("wrong", "dummy")
+				} else {
+					userRepo.EXPECT().FindUserByEmail(gomock.Any(), "user@mail.com").
+						Return(domain.User{ID: 1, Email: "user@mail.com", Password: "hash"}, nil)
+					hasher.EXPECT().Verify("wrong", "hash").Return(false, nil)
+				}
+
+				for i, subject := range subjects {
+					redisMock.EXPECT().Incr(loginFailuresCacheKey(subject.kind, subject.value), 900).
+						Return(tc.failures, nil)
+
+					if subject.kind == "account" && tc.setLockout {
+						redisMock.EXPECT().Set(loginLockoutCacheKey(subject.kind, subject.value), "1", 900)
+					}
+
+					if tc.backoffSecs[i] > 0 {
+						redisMock.EXPECT().Set(loginBackoffCacheKey(subject.kind, subject.value), "1", tc.backoffSecs[i])
+					}
+				}
+			}
+
+			a := &AuthUseCase{
+				userRepo:          userRepo,
+				passwordHasher:    hasher,
+				redis:             redisMock,
+				auditRecorder:     auditRecorder,
+				dummyPasswordHash: "dummy",
+				config: config.Config{LoginProtection: config.LoginProtectionConfig{MaxAccountAttempts: 5,
+					MaxIPAttempts: 20, AttemptWindow: 15 * time.Minute, LockoutDuration: 15 * time.Minute,
+					BackoffBase: time.Second}},
+			}
+
+			_, err := a.Login(ctx, "user@mail.com", "wrong")
+			assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+		})
+	}
+}
+
+func Test_backoffDelay(t *testing.T) {
+	testCases := []struct {
+		failures int64
+		delay    time.Duration
+	}{
+		{failures: 0, delay: 0},
+		{failures: 1, delay: time.Second},
+		{failures: 4, delay: 8 * time.Second},
+		{failures: 20, delay: time.Minute},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.delay, backoffDelay(time.Second, tc.failures, time.Minute))
+	}
+}
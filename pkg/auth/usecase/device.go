@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"log"
+	"strings"
+	"time"
+)
+
+// ListLoginHistory returns the latest logins of the current user, most recent first.
+func (a *AuthUseCase) ListLoginHistory(ctx context.Context) (records []domain.LoginRecord, err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = common.ErrAuthUnauthenticated
+		return
+	}
+
+	records, err = a.deviceRepo.FindLoginRecordsByUserID(userID, common.LoginHistoryLimit)
+	if err != nil {
+		err = fmt.Errorf("find login records err: %+v", err)
+		return
+	}
+
+	return
+}
+
+// FindReportedLogin returns the login the link of a new sign-in email is about, for the user to confirm reporting
+// it. Nothing changes, so mail scanners and prefetchers opening the link don't log anyone out.
+func (a *AuthUseCase) FindReportedLogin(ctx context.Context, token string) (record domain.LoginRecord, err error) {
+	jwtData, err := a.jwtModule.ExtractToken(ctx, token, common.LoginReportTokenType)
+	if errors.Is(err, jwt.ErrTokenInvalid) || errors.Is(err, jwt.ErrTokenExpired) {
+		err = common.ErrLoginReportInvalid
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("extract login report token err: %+v", err)
+		return
+	}
+
+	record, err = a.deviceRepo.FindLoginRecordBySessionID(jwtData.SessionID)
+	if errors.Is(err, common.ErrLoginRecordNotFound) || (err == nil && record.UserID != jwtData.IdentityID) {
+		err = common.ErrLoginReportInvalid
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("find login record err: %+v", err)
+		return
+	}
+
+	return
+}
+
+// ReportLogin revokes the session of a login the user didn't make, once confirmed from the link of the new sign-in
+// email. The device is forgotten as well, so logging in from it again is notified again.
+func (a *AuthUseCase) ReportLogin(ctx context.Context, token string) (err error) {
+	record, err := a.FindReportedLogin(ctx, token)
+	if err != nil {
+		return
+	}
+
+	// the session might be gone already, reporting it again is harmless
+	if err = a.revokeSession(record.SessionID); err != nil {
+		return
+	}
+
+	err = a.sessionRepo.DeleteSession(record.SessionID)
+	if err != nil && !errors.Is(err, common.ErrSessionNotFound) {
+		return fmt.Errorf("delete session err: %+v", err)
+	}
+
+	err = a.deviceRepo.DeleteKnownDevice(record.UserID, record.DeviceID)
+	if err != nil && !errors.Is(err, common.ErrDeviceNotFound) {
+		return fmt.Errorf("delete known device err: %+v", err)
+	}
+
+	log.Printf("login reported, session revoked: user_id=%d, session_id=%s", record.UserID, record.SessionID)
+	a.auditRecorder.Record(ctx, domain.AuditEvent{
+		Type:      auditCommon.EventLoginReported,
+		UserID:    record.UserID,
+		SessionID: record.SessionID,
+	})
+
+	return nil
+}
+
+// HandleSendNewSignInEmail tells the user about a login from a new device or an unlikely place.
+func (a *AuthUseCase) HandleSendNewSignInEmail(ctx context.Context, task *asynq.Task) error {
+	var p common.NewSignInPayload
+	if err := json.Unmarshal(task.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	var reasons []string
+	if p.NewDevice {
+		reasons = append(reasons, "from a device you haven't used before")
+	}
+
+	if p.ImpossibleTravel {
+		reasons = append(reasons, "from a country far from your previous login")
+	}
+
+	location := p.ClientIP
+	if p.Country != "" {
+		location = fmt.Sprintf("%s (%s)", p.ClientIP, p.Country)
+	}
+
+	link := fmt.Sprintf("%s/login/report?token=%s", a.config.ClientURL, p.Token)
+	body := fmt.Sprintf("Your account was just signed in to %s.\n\nTime: %s\nDevice: %s\nIP address: %s\n\n"+
+		"If this was you, you can ignore this email. If it wasn't, open the following link and confirm to log "+
+		"that session out, then change your password:\n%s", strings.Join(reasons, " and "),
+		p.SignedInAt.UTC().Format(time.RFC1123), p.UserAgent, location, link)
+
+	return a.mailer.Send(p.Email, "New sign-in to your account", body)
+}
+
+// record the login of a new session in the user history, notifying the user when it comes from an unrecognized
+// device or too far from the previous login too soon. Login goes on whatever happens.
+func (a *AuthUseCase) recordLogin(ctx context.Context, userID int64, sessionID string) {
+	userAgent, _ := general.GetUserAgentFromCtx(ctx)
+	clientIP, _ := general.GetClientIPFromCtx(ctx)
+	now := a.time.Now()
+
+	record := domain.LoginRecord{
+		UserID:    userID,
+		SessionID: sessionID,
+		UserAgent: userAgent,
+		ClientIP:  clientIP,
+		Country:   a.lookupCountry(clientIP),
+		CreatedAt: now,
+	}
+
+	previous, err := a.deviceRepo.FindLastLoginRecord(userID)
+	if err != nil && !errors.Is(err, common.ErrLoginRecordNotFound) {
+		log.Printf("find last login record err: %+v", err)
+		return
+	}
+
+	// countries only tell the distance apart, so any other country within the window is too far
+	record.ImpossibleTravel = err == nil && previous.Country != "" && record.Country != "" &&
+		previous.Country != record.Country &&
+		now.Sub(previous.CreatedAt) < a.config.LoginNotification.ImpossibleTravelWindow
+
+	if record.DeviceID, record.NewDevice, err = a.rememberDevice(userID, userAgent, clientIP, now); err != nil {
+		log.Printf("remember device err: %+v", err)
+		return
+	}
+
+	if err = a.deviceRepo.InsertLoginRecord(&record); err != nil {
+		log.Printf("insert login record err: %+v", err)
+		return
+	}
+
+	if record.NewDevice {
+		a.auditRecorder.Record(ctx, domain.AuditEvent{
+			Type:      auditCommon.EventLoginNewDevice,
+			UserID:    userID,
+			SessionID: sessionID,
+		})
+	}
+
+	if record.ImpossibleTravel {
+		a.auditRecorder.Record(ctx, domain.AuditEvent{
+			Type:      auditCommon.EventLoginImpossibleTravel,
+			UserID:    userID,
+			SessionID: sessionID,
+			Detail: fmt.Sprintf("previous login from %s at %s", previous.Country,
+				previous.CreatedAt.Format(time.RFC3339)),
+		})
+	}
+
+	if !record.NewDevice && !record.ImpossibleTravel {
+		return
+	}
+
+	if err = a.sendNewSignInEmail(ctx, record); err != nil {
+		log.Printf("send new sign-in email err: %+v", err)
+	}
+}
+
+// remember the device the user logs in from, newDevice reports whether it is unrecognized. The first device of a
+// user is never new, there is nothing to recognize it from.
+func (a *AuthUseCase) rememberDevice(userID int64, userAgent, clientIP string, now time.Time) (deviceID int64,
+	newDevice bool, err error) {
+	fingerprint := loginFingerprint(userAgent, clientIP)
+
+	device, err := a.deviceRepo.FindKnownDevice(userID, fingerprint)
+	if err == nil {
+		return device.ID, false, a.deviceRepo.UpdateKnownDeviceLastSeenAt(device.ID, now)
+	}
+
+	if !errors.Is(err, common.ErrDeviceNotFound) {
+		return
+	}
+
+	count, err := a.deviceRepo.CountKnownDevices(userID)
+	if err != nil {
+		return
+	}
+
+	device = domain.KnownDevice{
+		UserID:      userID,
+		Fingerprint: fingerprint,
+		UserAgent:   userAgent,
+		ClientIP:    clientIP,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}
+
+	if err = a.deviceRepo.InsertKnownDevice(&device); err != nil {
+		return
+	}
+
+	return device.ID, count > 0, nil
+}
+
+// enqueue the new sign-in email, with a link revoking the session of the login
+func (a *AuthUseCase) sendNewSignInEmail(ctx context.Context, record domain.LoginRecord) (err error) {
+	user, err := a.userRepo.FindUserByID(ctx, record.UserID)
+	if err != nil {
+		return fmt.Errorf("find user err: %+v", err)
+	}
+
+	token, err := a.generateToken(ctx, uuid.New().String(), record.SessionID, record.UserID,
+		common.LoginReportTokenType, common.LoginReportTokenLifetime)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(common.NewSignInPayload{
+		Email:            user.Email,
+		UserAgent:        record.UserAgent,
+		ClientIP:         record.ClientIP,
+		Country:          record.Country,
+		SignedInAt:       record.CreatedAt,
+		NewDevice:        record.NewDevice,
+		ImpossibleTravel: record.ImpossibleTravel,
+		Token:            token,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = a.client.EnqueueTaskContext(ctx, asynq.NewTask(common.TypeNewSignInEmail, payload))
+	if err != nil {
+		return fmt.Errorf("enqueue new sign-in email err: %+v", err)
+	}
+
+	return nil
+}
+
+// resolve the country of the IP when a GeoIP database is configured, empty when unknown
+func (a *AuthUseCase) lookupCountry(clientIP string) string {
+	if a.geoIP == nil || clientIP == "" {
+		return ""
+	}
+
+	country, err := a.geoIP.Country(clientIP)
+	if err != nil {
+		log.Printf("geoip lookup err: %+v", err)
+		return ""
+	}
+
+	return country
+}
+
+// identify the device of a login by its user agent and client IP, moving to another network makes for another
+// device
+func loginFingerprint(userAgent, clientIP string) string {
+	return general.HashToken(userAgent + "\n" + clientIP)
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAuthUseCase_FindReportedLogin(t *testing.T) {
+	record := domain.LoginRecord{ID: 4, UserID: 1, SessionID: "session", DeviceID: 6, ClientIP: "1.2.3.4"}
+
+	testCases := []struct {
+		name       string
+		extractErr error
+		record     *domain.LoginRecord // nil when the login isn't recorded
+		err        error
+	}{
+		{name: "found", record: &record},
+		{name: "expired link", extractErr: jwt.ErrTokenExpired, err: common.ErrLoginReportInvalid},
+		{name: "unknown login", err: common.ErrLoginReportInvalid},
+		{name: "login of another user", record: &domain.LoginRecord{ID: 4, UserID: 2, SessionID: "session"},
+			err: common.ErrLoginReportInvalid},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			jwtModule := jwt.NewMockJwtInterface(ctrl)
+			deviceRepo := domain.NewMockDeviceRepository(ctrl)
+
+			jwtModule.EXPECT().ExtractToken(gomock.Any(), "token", common.LoginReportTokenType).
+				Return(jwt.JwtData{SessionID: "session", IdentityID: 1}, tc.extractErr)
+
+			if tc.extractErr == nil {
+				if tc.record == nil {
+					deviceRepo.EXPECT().FindLoginRecordBySessionID("session").
+						Return(domain.LoginRecord{}, common.ErrLoginRecordNotFound)
+				} else {
+					deviceRepo.EXPECT().FindLoginRecordBySessionID("session").Return(*tc.record, nil)
+				}
+			}
+
+			// looking only, the session is left alone
+			a := &AuthUseCase{jwtModule: jwtModule, deviceRepo: deviceRepo}
+
+			found, err := a.FindReportedLogin(context.Background(), "token")
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, record, found)
+		})
+	}
+}
+
+func TestAuthUseCase_ReportLogin(t *testing.T) {
+	testCases := []struct {
+		name       string
+		sessionErr error // of deleting the session
+		deviceErr  error // of forgetting the device
+	}{
+		{name: "reported"},
+		{name: "reported again", sessionErr: common.ErrSessionNotFound, deviceErr: common.ErrDeviceNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			jwtModule := jwt.NewMockJwtInterface(ctrl)
+			deviceRepo := domain.NewMockDeviceRepository(ctrl)
+			sessionRepo := domain.NewMockSessionRepository(ctrl)
+			redisMock := redis.NewMockInterface(ctrl)
+			auditRecorder := domain.NewMockAuditRecorder(ctrl)
+
+			jwtModule.EXPECT().ExtractToken(gomock.Any(), "token", common.LoginReportTokenType).
+				Return(jwt.JwtData{SessionID: "session", IdentityID: 1}, nil)
+			deviceRepo.EXPECT().FindLoginRecordBySessionID("session").
+				Return(domain.LoginRecord{ID: 4, UserID: 1, SessionID: "session", DeviceID: 6}, nil)
+
+			redisMock.EXPECT().TTL(refreshTokenCacheKey("session")).Return(-2, nil)
+			redisMock.EXPECT().Set(invalidSessionCacheKey("session"), common.SessionInvalidated,
+				int(common.ImpersonationTokenLifetime.Seconds()))
+			sessionRepo.EXPECT().DeleteSession("session").Return(tc.sessionErr)
+			deviceRepo.EXPECT().DeleteKnownDevice(int64(1), int64(6)).Return(tc.deviceErr)
+			auditRecorder.EXPECT().Record(gomock.Any(), domain.AuditEvent{Type: auditCommon.EventLoginReported,
+				UserID: 1, SessionID: "session"})
+
+			a := &AuthUseCase{
+				jwtModule:     jwtModule,
+				deviceRepo:    deviceRepo,
+				sessionRepo:   sessionRepo,
+				redis:         redisMock,
+				auditRecorder: auditRecorder,
+			}
+
+			assert.NoError(t, a.ReportLogin(context.Background(), "token"))
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	rbacCommon "github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"log"
+)
+
+// Impersonate issues a short-lived access token acting as the given user on behalf of the current admin.
+// The token carries the admin as its actor, can't be refreshed, and every request made with it is audited.
+func (a *AuthUseCase) Impersonate(ctx context.Context, userID int64, reason string) (token common.ImpersonationToken,
+	err error) {
+	adminID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = common.ErrAuthUnauthenticated
+		return
+	}
+
+	// impersonation is only started by an admin in person, never chained
+	if err = rejectImpersonation(ctx); err != nil {
+		return
+	}
+
+	if _, ok = general.GetApiKeyIDFromCtx(ctx); ok {
+		err = common.ErrApiKeyNotAllowed
+		return
+	}
+
+	if userID == adminID {
+		err = common.ErrImpersonationTargetInvalid
+		return
+	}
+
+	// within an organization, only its members can be found
+	if _, err = a.userRepo.FindUserByID(ctx, userID); err != nil {
+		err = common.ErrUserNotFound
+		return
+	}
+
+	// stay within the organization of the admin, or act within the organization the user would log in to
+	organizationID, ok := general.GetTenantIDFromCtx(ctx)
+	if !ok || organizationID == 0 {
+		if organizationID, err = a.defaultOrganization(userID); err != nil {
+			return
+		}
+	}
+
+	// acting as another admin would hand over whatever that admin is allowed to do, so the user may hold no
+	// permission beyond those of the admin, and none to impersonate in turn
+	granted, err := a.rbacRepo.FindPermissionsByUserID(userID)
+	if err != nil {
+		err = fmt.Errorf("find permissions err: %+v", err)
+		return
+	}
+
+	adminGranted, err := a.rbacRepo.FindPermissionsByUserID(adminID)
+	if err != nil {
+		err = fmt.Errorf("find permissions err: %+v", err)
+		return
+	}
+
+	if rbacCommon.PermissionGranted(granted, rbacCommon.PermissionUsersImpersonate) ||
+		!rbacCommon.PermissionsCovered(adminGranted, granted) {
+		err = common.ErrImpersonationTargetInvalid
+		return
+	}
+
+	// the session can be listed and revoked like any other, it has no refresh token
+	sessionID := uuid.New().String()
+	now := a.time.Now()
+	userAgent, _ := general.GetUserAgentFromCtx(ctx)
+	clientIP, _ := general.GetClientIPFromCtx(ctx)
+
+	err = a.sessionRepo.InsertSession(domain.Session{
+		ID:             sessionID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		UserAgent:      userAgent,
+		ClientIP:       clientIP,
+		CreatedAt:      now,
+		LastSeenAt:     now,
+		ExpiresAt:      now.Add(common.ImpersonationTokenLifetime),
+	})
+	if err != nil {
+		err = fmt.Errorf("register session err: %+v", err)
+		return
+	}
+
+	accessData := jwt.JwtData{
+		TokenID:    uuid.New().String(),
+		SessionID:  sessionID,
+		IdentityID: userID,
+		ActorID:    adminID,
+		TenantID:   organizationID,
+		Type:       common.AccessTokenType,
+		Lifetime:   common.ImpersonationTokenLifetime,
+	}
+
+	if a.config.JwtEmbedRoles {
+		accessData.Roles, err = a.userRoleNames(userID, nil)
+		if err != nil {
+			return
+		}
+	}
+
+	token.AccessToken, err = a.jwtModule.GenerateToken(ctx, accessData)
+	if err != nil {
+		return
+	}
+	token.ExpiresAt = now.Add(common.ImpersonationTokenLifetime)
+
+	log.Printf("impersonation started: admin_id=%d, user_id=%d, session_id=%s", adminID, userID, sessionID)
+	a.auditRecorder.Record(ctx, domain.AuditEvent{
+		Type:      auditCommon.EventImpersonationStart,
+		UserID:    userID,
+		ActorID:   adminID,
+		SessionID: sessionID,
+		Detail:    reason,
+	})
+
+	return
+}
+
+// reject requests made while impersonating, for actions only the user in person may take
+func rejectImpersonation(ctx context.Context) error {
+	if _, ok := general.GetActorIDFromCtx(ctx); ok {
+		return common.ErrImpersonationNotAllowed
+	}
+
+	return nil
+}
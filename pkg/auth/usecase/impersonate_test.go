@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	rbacCommon "github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestAuthUseCase_Impersonate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx := general.SetTenantIDIntoCtx(general.SetUserIDIntoCtx(context.Background(), 1), 5)
+	adminGranted := []string{rbacCommon.PermissionUsersImpersonate, rbacCommon.PermissionAuditRead}
+
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		userID   int64
+		notFound bool
+		granted  []string // permissions of the user, nil when not looked up
+		err      error
+	}{
+		{name: "started", ctx: ctx, userID: 2, granted: []string{rbacCommon.PermissionAuditRead}},
+		{name: "user with permissions beyond the admin", ctx: ctx, userID: 2,
+			granted: []string{rbacCommon.PermissionRolesAssign}, err: common.ErrImpersonationTargetInvalid},
+		{name: "another admin", ctx: ctx, userID: 2, granted: []string{rbacCommon.PermissionUsersImpersonate},
+			err: common.ErrImpersonationTargetInvalid},
+		{name: "oneself", ctx: ctx, userID: 1, err: common.ErrImpersonationTargetInvalid},
+		{name: "unknown user", ctx: ctx, userID: 2, notFound: true, err: common.ErrUserNotFound},
+		{name: "chained", ctx: general.SetActorIDIntoCtx(ctx, 9), userID: 2, err: common.ErrImpersonationNotAllowed},
+		{name: "api key", ctx: general.SetApiKeyIntoCtx(ctx, 3, nil), userID: 2, err: common.ErrApiKeyNotAllowed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userRepo := domain.NewMockUserRepository(ctrl)
+			rbacRepo := domain.NewMockRbacRepository(ctrl)
+			sessionRepo := domain.NewMockSessionRepository(ctrl)
+			jwtModule := jwt.NewMockJwtInterface(ctrl)
+			auditRecorder := domain.NewMockAuditRecorder(ctrl)
+			timeMock := commonTime.NewMockTimeInterface(ctrl)
+
+			timeMock.EXPECT().Now().Return(now).AnyTimes()
+
+			if tc.notFound {
+				userRepo.EXPECT().FindUserByID(gomock.Any(), int64(2)).Return(domain.User{}, gorm.ErrRecordNotFound)
+			}
+
+			if tc.granted != nil {
+				userRepo.EXPECT().FindUserByID(gomock.Any(), int64(2)).Return(domain.User{ID: 2}, nil)
+				rbacRepo.EXPECT().FindPermissionsByUserID(int64(2)).Return(tc.granted, nil)
+				rbacRepo.EXPECT().FindPermissionsByUserID(int64(1)).Return(adminGranted, nil)
+			}
+
+			var session domain.Session
+			if tc.err == nil {
+				sessionRepo.EXPECT().InsertSession(gomock.Any()).DoAndReturn(func(s domain.Session) error {
+					session = s
+					return nil
+				})
+				jwtModule.EXPECT().GenerateToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, data jwt.JwtData) (string, error) {
+						assert.Equal(t, int64(2), data.IdentityID)
+						assert.Equal(t, int64(1), data.ActorID)
+						assert.Equal(t, int64(5), data.TenantID)
+						assert.Equal(t, session.ID, data.SessionID)
+						assert.Equal(t, common.ImpersonationTokenLifetime, data.Lifetime)
+						return "access", nil
+					})
+				auditRecorder.EXPECT().Record(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, event domain.AuditEvent) {
+						assert.Equal(t, domain.AuditEvent{Type: auditCommon.EventImpersonationStart, UserID: 2,
+							ActorID: 1, SessionID: session.ID, Detail: "support"}, event)
+					})
+			}
+
+			a := &AuthUseCase{
+				userRepo:      userRepo,
+				rbacRepo:      rbacRepo,
+				sessionRepo:   sessionRepo,
+				jwtModule:     jwtModule,
+				time:          timeMock,
+				auditRecorder: auditRecorder,
+			}
+
+			token, err := a.Impersonate(tc.ctx, tc.userID, "support")
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			// the session is as short-lived as its only token
+			assert.NoError(t, err)
+			assert.Equal(t, "access", token.AccessToken)
+			assert.Equal(t, now.Add(common.ImpersonationTokenLifetime), token.ExpiresAt)
+			assert.Equal(t, domain.Session{ID: session.ID, UserID: 2, OrganizationID: 5, CreatedAt: now,
+				LastSeenAt: now, ExpiresAt: now.Add(common.ImpersonationTokenLifetime)}, session)
+		})
+	}
+}
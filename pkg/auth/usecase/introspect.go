@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"log"
+)
+
+// IntrospectToken tells whether a first-party access or refresh token is active, the hint picks which token
+// type is tried first.
+func (a *AuthUseCase) IntrospectToken(ctx context.Context, token, tokenTypeHint string) (
+	introspection common.TokenIntrospection, err error) {
+	tokenTypes := []string{common.AccessTokenType, common.RefreshTokenType}
+	if tokenTypeHint == common.RefreshTokenType {
+		tokenTypes = []string{common.RefreshTokenType, common.AccessTokenType}
+	}
+
+	for _, tokenType := range tokenTypes {
+		valid, user, data, err := a.extractAndValidateToken(ctx, token, tokenType)
+		if err != nil {
+			return introspection, err
+		}
+
+		if !valid {
+			continue
+		}
+
+		// only the newest refresh token of the session is usable
+		if tokenType == common.RefreshTokenType {
+			currentTokenID, err := a.redis.Get(refreshTokenCacheKey(data.SessionID))
+			if err != nil && !errors.Is(err, redis.ErrNilReturned) {
+				return introspection, fmt.Errorf("get refresh token err: %+v", err)
+			}
+
+			if currentTokenID != data.TokenID {
+				continue
+			}
+		}
+
+		return common.TokenIntrospection{
+			Active:    true,
+			UserID:    user.ID,
+			SessionID: data.SessionID,
+			TokenID:   data.TokenID,
+			TokenType: tokenType,
+			IssuedAt:  data.IssuedAt,
+			ExpiresAt: data.ExpiresAt,
+		}, nil
+	}
+
+	return common.TokenIntrospection{}, nil
+}
+
+// RevokeToken revokes the session a first-party token belongs to, inactive tokens are ignored.
+func (a *AuthUseCase) RevokeToken(ctx context.Context, token, tokenTypeHint string) (err error) {
+	introspection, err := a.IntrospectToken(ctx, token, tokenTypeHint)
+	if err != nil || !introspection.Active {
+		return
+	}
+
+	if err = a.revokeSession(introspection.SessionID); err != nil {
+		return
+	}
+
+	err = a.sessionRepo.DeleteSession(introspection.SessionID)
+	if err != nil && !errors.Is(err, common.ErrSessionNotFound) {
+		return fmt.Errorf("delete session err: %+v", err)
+	}
+
+	log.Printf("session revoked by token: user_id=%d, session_id=%s", introspection.UserID, introspection.SessionID)
+
+	return nil
+}
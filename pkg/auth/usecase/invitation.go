@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	invitationCommon "github.com/lactobasilusprotectus/go-template/pkg/invitation/common"
+	"log"
+)
+
+// find the pending invitation of the code, which must have been sent to the registering email
+func (a *AuthUseCase) findInvitation(email, code string) (invitation domain.Invitation, err error) {
+	invitation, err = a.invitationRepo.FindInvitationByCodeHash(general.HashToken(code))
+	if errors.Is(err, invitationCommon.ErrInvitationNotFound) {
+		err = invitationCommon.ErrInvitationInvalid
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("find invitation err: %+v", err)
+		return
+	}
+
+	if invitationCommon.Status(invitation.AcceptedAt, invitation.ExpiresAt, a.time.Now()) !=
+		invitationCommon.StatusPending || normalizeEmail(invitation.Email) != normalizeEmail(email) {
+		err = invitationCommon.ErrInvitationInvalid
+		return
+	}
+
+	return
+}
+
+// find the pending invitation of an email verified by an identity provider, users signing up through a provider
+// have no code but their email proves the invitation was for them. Required in the invite registration mode, the
+// invitation ID is 0 otherwise when there is none.
+func (a *AuthUseCase) findOidcInvitation(email string, emailVerified bool) (invitation domain.Invitation,
+	err error) {
+	required := a.config.Registration.Mode == config.RegistrationModeInvite
+
+	if !emailVerified {
+		if required {
+			err = invitationCommon.ErrInvitationRequired
+		}
+		return
+	}
+
+	invitation, err = a.invitationRepo.FindPendingInvitationByEmail(email, a.time.Now())
+	if errors.Is(err, invitationCommon.ErrInvitationNotFound) {
+		err = nil
+		if required {
+			err = invitationCommon.ErrInvitationRequired
+		}
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("find invitation err: %+v", err)
+		return
+	}
+
+	return
+}
+
+// insert the user along with accepting the invitation, so that a revoked or used invitation creates no user. The
+// invitation was sent to the email of the user, so that email is verified as well.
+func (a *AuthUseCase) insertInvitedUser(user *domain.User, invitation domain.Invitation) (err error) {
+	now := a.time.Now()
+	if user.EmailVerifiedAt == nil {
+		user.EmailVerifiedAt = &now
+	}
+
+	err = a.invitationRepo.InsertInvitedUser(invitation.ID, now, user)
+	if errors.Is(err, invitationCommon.ErrInvitationNotFound) {
+		log.Printf("invitation gone before acceptance: invitation_id=%d", invitation.ID)
+		return invitationCommon.ErrInvitationInvalid
+	}
+
+	if err != nil {
+		return fmt.Errorf("insert invited user err: %+v", err)
+	}
+
+	return nil
+}
+
+// grant the role and organization of the invitation accepted by the newly registered user
+func (a *AuthUseCase) acceptInvitation(ctx context.Context, user domain.User, invitation domain.Invitation) (
+	err error) {
+	if invitation.Role != "" {
+		var role domain.Role
+		if role, err = a.rbacRepo.FindRoleByName(invitation.Role); err != nil {
+			return fmt.Errorf("find role err: %+v", err)
+		}
+
+		if err = a.rbacRepo.AssignUserRole(user.ID, role.ID); err != nil {
+			return fmt.Errorf("assign role err: %+v", err)
+		}
+	}
+
+	if invitation.OrganizationID != 0 {
+		if err = a.organizationRepo.InsertMember(invitation.OrganizationID, user.ID); err != nil {
+			return fmt.Errorf("insert member err: %+v", err)
+		}
+	}
+
+	log.Printf("invitation accepted: invitation_id=%d, user_id=%d", invitation.ID, user.ID)
+	a.auditRecorder.Record(ctx, domain.AuditEvent{
+		Type:   auditCommon.EventInvitationAccepted,
+		UserID: user.ID,
+		Email:  user.Email,
+		Detail: fmt.Sprintf("invitation %d", invitation.ID),
+	})
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/password"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	invitationCommon "github.com/lactobasilusprotectus/go-template/pkg/invitation/common"
+	rbacCommon "github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAuthUseCase_Register_invitation(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	invitation := domain.Invitation{ID: 1, Email: "invited@mail.com", Role: "editor", OrganizationID: 3,
+		CodeHash: general.HashToken("code"), ExpiresAt: now.Add(time.Hour)}
+
+	testCases := []struct {
+		name       string
+		email      string
+		code       string
+		invitation *domain.Invitation // found by the code, nil when there's none
+		claimErr   error              // of claiming the invitation along with inserting the user
+		err        error
+	}{
+		{name: "accepted", email: "invited@mail.com", code: "code", invitation: &invitation},
+		{name: "revoked or used before acceptance", email: "invited@mail.com", code: "code",
+			invitation: &invitation, claimErr: invitationCommon.ErrInvitationNotFound,
+			err: invitationCommon.ErrInvitationInvalid},
+		{name: "sent to another email", email: "other@mail.com", code: "code", invitation: &invitation,
+			err: invitationCommon.ErrInvitationInvalid},
+		{name: "unknown code", email: "invited@mail.com", code: "other", err: invitationCommon.ErrInvitationInvalid},
+		{name: "no code", email: "invited@mail.com", err: invitationCommon.ErrInvitationRequired},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userRepo := domain.NewMockUserRepository(ctrl)
+			invitationRepo := domain.NewMockInvitationRepository(ctrl)
+			rbacRepo := domain.NewMockRbacRepository(ctrl)
+			organizationRepo := domain.NewMockOrganizationRepository(ctrl)
+			passwordHasher := password.NewMockInterface(ctrl)
+			passwordPolicy := password.NewMockValidator(ctrl)
+			auditRecorder := domain.NewMockAuditRecorder(ctrl)
+			timeMock := commonTime.NewMockTimeInterface(ctrl)
+
+			timeMock.EXPECT().Now().Return(now).AnyTimes()
+
+			if tc.code != "" {
+				if tc.invitation == nil {
+					invitationRepo.EXPECT().FindInvitationByCodeHash(general.HashToken(tc.code)).
+						Return(domain.Invitation{}, invitationCommon.ErrInvitationNotFound)
+				} else {
+					invitationRepo.EXPECT().FindInvitationByCodeHash(general.HashToken(tc.code)).
+						Return(*tc.invitation, nil)
+				}
+			}
+
+			// the user is only inserted along with claiming a valid invitation, never without it
+			var inserted domain.User
+			if tc.err == nil || tc.claimErr != nil {
+				passwordPolicy.EXPECT().Validate("password", "invited", tc.email)
+				passwordHasher.EXPECT().Hash("password").Return("hash", nil)
+				invitationRepo.EXPECT().InsertInvitedUser(int64(1), now, gomock.Any()).
+					DoAndReturn(func(id int64, acceptedAt time.Time, user *domain.User) error {
+						if tc.claimErr != nil {
+							return tc.claimErr
+						}
+						user.ID = 7
+						inserted = *user
+						return nil
+					})
+			}
+
+			if tc.err == nil {
+				auditRecorder.EXPECT().Record(gomock.Any(), domain.AuditEvent{Type: auditCommon.EventRegister,
+					Email: tc.email})
+				rbacRepo.EXPECT().FindRoleByName(rbacCommon.RoleUser).Return(domain.Role{ID: 1}, nil)
+				rbacRepo.EXPECT().AssignUserRole(int64(7), int64(1))
+				rbacRepo.EXPECT().FindRoleByName("editor").Return(domain.Role{ID: 2}, nil)
+				rbacRepo.EXPECT().AssignUserRole(int64(7), int64(2))
+				organizationRepo.EXPECT().InsertMember(int64(3), int64(7))
+				auditRecorder.EXPECT().Record(gomock.Any(), domain.AuditEvent{
+					Type: auditCommon.EventInvitationAccepted, UserID: 7, Email: tc.email, Detail: "invitation 1"})
+			}
+
+			a := &AuthUseCase{
+				userRepo:         userRepo,
+				invitationRepo:   invitationRepo,
+				rbacRepo:         rbacRepo,
+				organizationRepo: organizationRepo,
+				passwordHasher:   passwordHasher,
+				passwordPolicy:   passwordPolicy,
+				auditRecorder:    auditRecorder,
+				time:             timeMock,
+			}
+			a.config.Registration.Mode = config.RegistrationModeInvite
+
+			err := a.Register(context.Background(), domain.User{Username: "invited", Email: tc.email,
+				Password: "password"}, tc.code)
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			// the invitation was sent to the email, which is verified by registering with it
+			assert.NoError(t, err)
+			assert.Equal(t, "hash", inserted.Password)
+			if assert.NotNil(t, inserted.EmailVerifiedAt) {
+				assert.Equal(t, now, *inserted.EmailVerifiedAt)
+			}
+		})
+	}
+}
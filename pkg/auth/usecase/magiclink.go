@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"log"
+)
+
+// magicLink is what we remember between sending the link and its use
+type magicLink struct {
+	UserID    int64  `json:"user_id"`
+	NonceHash string `json:"nonce_hash"`
+}
+
+// RequestMagicLink emails a single use login link to the user, at most once per MagicLinkResendInterval.
+// The link only works along with the returned nonce, which is to be kept on the requesting device. Like
+// ForgotPassword, it doesn't reveal whether the email is registered, a nonce is returned either way.
+func (a *AuthUseCase) RequestMagicLink(ctx context.Context, email string) (nonce string, err error) {
+	ok, err := a.redis.SetNX(magicLinkThrottleCacheKey(normalizeEmail(email)), "1",
+		int(common.MagicLinkResendInterval.Seconds()))
+	if err != nil {
+		return "", fmt.Errorf("throttle magic link err: %+v", err)
+	}
+
+	if !ok {
+		return "", common.ErrTooManyRequests
+	}
+
+	nonce, err = general.GenerateRandomToken(32)
+	if err != nil {
+		return "", fmt.Errorf("generate magic link nonce err: %+v", err)
+	}
+
+	user, err := a.userRepo.FindUserByEmail(ctx, email)
+	if err != nil {
+		log.Printf("magic link requested for unknown email")
+		return nonce, nil
+	}
+
+	linkID := uuid.New().String()
+
+	link, err := json.Marshal(magicLink{UserID: user.ID, NonceHash: general.HashToken(nonce)})
+	if err != nil {
+		return "", fmt.Errorf("marshal magic link err: %+v", err)
+	}
+
+	err = a.redis.Set(magicLinkCacheKey(linkID), string(link), int(common.MagicLinkTokenLifetime.Seconds()))
+	if err != nil {
+		return "", fmt.Errorf("store magic link err: %+v", err)
+	}
+
+	// signed, so forged links are rejected without hitting the cache
+	token, err := a.generateToken(ctx, linkID, "", user.ID, common.MagicLinkTokenType,
+		common.MagicLinkTokenLifetime)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(common.MagicLinkPayload{
+		Email: user.Email,
+		Token: token,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	_, err = a.client.EnqueueTaskContext(ctx, asynq.NewTask(common.TypeMagicLinkEmail, payload))
+	if err != nil {
+		return "", fmt.Errorf("enqueue magic link email err: %+v", err)
+	}
+
+	return nonce, nil
+}
+
+// MagicLinkCallback consumes the magic link and logs its user in, just like Login does. The nonce returned when
+// the link was requested must come along.
+func (a *AuthUseCase) MagicLinkCallback(ctx context.Context, token, nonce string) (loginToken common.LoginToken,
+	err error) {
+	jwtData, err := a.jwtModule.ExtractToken(ctx, token, common.MagicLinkTokenType)
+	if errors.Is(err, jwt.ErrTokenInvalid) || errors.Is(err, jwt.ErrTokenExpired) {
+		err = common.ErrMagicLinkInvalid
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("extract magic link err: %+v", err)
+		return
+	}
+
+	reply, err := a.redis.Get(magicLinkCacheKey(jwtData.TokenID))
+	if errors.Is(err, redis.ErrNilReturned) {
+		err = common.ErrMagicLinkInvalid
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("get magic link err: %+v", err)
+		return
+	}
+
+	var link magicLink
+	if err = json.Unmarshal([]byte(fmt.Sprint(reply)), &link); err != nil || link.UserID != jwtData.IdentityID {
+		err = common.ErrMagicLinkInvalid
+		return
+	}
+
+	// checked before the link is consumed, so opening it on another device doesn't spoil it for the requesting one
+	if subtle.ConstantTimeCompare([]byte(link.NonceHash), []byte(general.HashToken(nonce))) != 1 {
+		log.Printf("magic link used from another device: user_id=%d", link.UserID)
+		a.auditRecorder.Record(ctx, domain.AuditEvent{
+			Type:   auditCommon.EventLoginFailure,
+			UserID: link.UserID,
+			Detail: "magic link used from another device",
+		})
+		err = common.ErrMagicLinkInvalid
+		return
+	}
+
+	// link is single use, only one of concurrent callbacks gets it
+	_, err = a.redis.GetDel(magicLinkCacheKey(jwtData.TokenID))
+	if errors.Is(err, redis.ErrNilReturned) {
+		err = common.ErrMagicLinkInvalid
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("consume magic link err: %+v", err)
+		return
+	}
+
+	user, err := a.userRepo.FindUserByID(ctx, link.UserID)
+	if err != nil {
+		err = common.ErrMagicLinkInvalid
+		return
+	}
+
+	// opening the link proves the user owns the email
+	if user.EmailVerifiedAt == nil {
+		now := a.time.Now()
+		if err = a.userRepo.UpdateUserEmailVerifiedAt(ctx, user.ID, now); err != nil {
+			err = fmt.Errorf("update email verified at err: %+v", err)
+			return
+		}
+
+		user.EmailVerifiedAt = &now
+	}
+
+	log.Printf("magic link login: user_id=%d", user.ID)
+	return a.completeLogin(ctx, user)
+}
+
+// HandleSendMagicLinkEmail sends the login link to the user.
+func (a *AuthUseCase) HandleSendMagicLinkEmail(ctx context.Context, task *asynq.Task) error {
+	var p common.MagicLinkPayload
+	if err := json.Unmarshal(task.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	link := fmt.Sprintf("%s/login/magic-link?token=%s", a.config.ClientURL, p.Token)
+	body := fmt.Sprintf("Open the following link within %s, in the browser you requested it from, to log in:\n%s\n\n"+
+		"If you didn't request it, you can ignore this email.", common.MagicLinkTokenLifetime, link)
+
+	return a.mailer.Send(p.Email, "Your login link", body)
+}
+
+func magicLinkCacheKey(linkID string) string {
+	return fmt.Sprintf("magic-link:%s", linkID)
+}
+
+func magicLinkThrottleCacheKey(email string) string {
+	return fmt.Sprintf("magic-link-throttle:%s", email)
+}
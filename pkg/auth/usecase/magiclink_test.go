@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/queue"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestAuthUseCase_RequestMagicLink(t *testing.T) {
+	testCases := []struct {
+		name      string
+		throttled bool
+		unknown   bool
+		err       error
+	}{
+		{name: "sent"},
+		{name: "unknown email", unknown: true},
+		{name: "throttled", throttled: true, err: common.ErrTooManyRequests},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			redisMock := redis.NewMockInterface(ctrl)
+			userRepo := domain.NewMockUserRepository(ctrl)
+			jwtModule := jwt.NewMockJwtInterface(ctrl)
+			client := queue.NewMockInterface(ctrl)
+
+			redisMock.EXPECT().SetNX(magicLinkThrottleCacheKey("user@mail.com"), "1",
+				int(common.MagicLinkResendInterval.Seconds())).Return(!tc.throttled, nil)
+
+			var stored magicLink
+			switch {
+			case tc.throttled:
+			case tc.unknown:
+				userRepo.EXPECT().FindUserByEmail(gomock.Any(), "user@mail.com").
+					Return(domain.User{}, gorm.ErrRecordNotFound)
+			default:
+				userRepo.EXPECT().FindUserByEmail(gomock.Any(), "user@mail.com").
+					Return(domain.User{ID: 1, Email: "user@mail.com"}, nil)
+				redisMock.EXPECT().Set(gomock.Any(), gomock.Any(), int(common.MagicLinkTokenLifetime.Seconds())).
+					DoAndReturn(func(key string, value interface{}, expireSeconds int) error {
+						return json.Unmarshal([]byte(value.(string)), &stored)
+					})
+				jwtModule.EXPECT().GenerateToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, data jwt.JwtData) (string, error) {
+						assert.Equal(t, common.MagicLinkTokenType, data.Type)
+						return "token", nil
+					})
+				client.EXPECT().EnqueueTaskContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, task *asynq.Task) (*asynq.TaskInfo, error) {
+						assert.JSONEq(t, `{"email":"user@mail.com","token":"token"}`, string(task.Payload()))
+						return &asynq.TaskInfo{}, nil
+					})
+			}
+
+			a := &AuthUseCase{userRepo: userRepo, redis: redisMock, jwtModule: jwtModule, client: client}
+
+			nonce, err := a.RequestMagicLink(context.Background(), "user@mail.com")
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			// a nonce is returned for unknown emails as well, only the one of a sent link is remembered
+			assert.NoError(t, err)
+			assert.NotEmpty(t, nonce)
+
+			if !tc.unknown {
+				assert.Equal(t, magicLink{UserID: 1, NonceHash: general.HashToken(nonce)}, stored)
+			}
+		})
+	}
+}
+
+func TestAuthUseCase_MagicLinkCallback(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	link, _ := json.Marshal(magicLink{UserID: 1, NonceHash: general.HashToken("nonce")})
+	tokenData := jwt.JwtData{TokenID: "link", IdentityID: 1, Type: common.MagicLinkTokenType}
+
+	testCases := []struct {
+		name       string
+		extractErr error
+		link       interface{} // remembered link, nil when it's gone
+		nonce      string
+		consumed   bool // the link gets used up
+		raced      bool // a concurrent callback used the link up first
+		verified   bool // the email of the user was verified before
+		err        error
+	}{
+		{name: "logged in", link: string(link), nonce: "nonce", consumed: true, verified: true},
+		{name: "email verified by the link", link: string(link), nonce: "nonce", consumed: true},
+		{name: "forged or expired", extractErr: jwt.ErrTokenInvalid, err: common.ErrMagicLinkInvalid},
+		{name: "used already", nonce: "nonce", err: common.ErrMagicLinkInvalid},
+		{name: "another device", link: string(link), nonce: "other", err: common.ErrMagicLinkInvalid},
+		{name: "used concurrently", link: string(link), nonce: "nonce", raced: true,
+			err: common.ErrMagicLinkInvalid},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			redisMock := redis.NewMockInterface(ctrl)
+			userRepo := domain.NewMockUserRepository(ctrl)
+			jwtModule := jwt.NewMockJwtInterface(ctrl)
+			auditRecorder := domain.NewMockAuditRecorder(ctrl)
+			timeMock := commonTime.NewMockTimeInterface(ctrl)
+
+			timeMock.EXPECT().Now().Return(now).AnyTimes()
+
+			jwtModule.EXPECT().ExtractToken(gomock.Any(), "token", common.MagicLinkTokenType).
+				Return(tokenData, tc.extractErr)
+
+			if tc.extractErr == nil {
+				if tc.link == nil {
+					redisMock.EXPECT().Get(magicLinkCacheKey("link")).Return(nil, redis.ErrNilReturned)
+				} else {
+					redisMock.EXPECT().Get(magicLinkCacheKey("link")).Return(tc.link, nil)
+				}
+			}
+
+			// the link is left for the requesting device
+			if tc.nonce == "other" {
+				auditRecorder.EXPECT().Record(gomock.Any(), gomock.Any())
+			}
+
+			if tc.raced {
+				redisMock.EXPECT().GetDel(magicLinkCacheKey("link")).Return(nil, redis.ErrNilReturned)
+			}
+
+			if tc.consumed {
+				redisMock.EXPECT().GetDel(magicLinkCacheKey("link")).Return(tc.link, nil)
+
+				// with a second factor, the login goes on with LoginMfa
+				user := domain.User{ID: 1, Email: "user@mail.com", TotpEnabledAt: &now}
+				if tc.verified {
+					user.EmailVerifiedAt = &now
+				} else {
+					userRepo.EXPECT().UpdateUserEmailVerifiedAt(gomock.Any(), int64(1), now)
+				}
+
+				userRepo.EXPECT().FindUserByID(gomock.Any(), int64(1)).Return(user, nil)
+				jwtModule.EXPECT().GenerateToken(gomock.Any(), gomock.Any()).Return("mfa", nil)
+			}
+
+			a := &AuthUseCase{
+				userRepo:      userRepo,
+				jwtModule:     jwtModule,
+				redis:         redisMock,
+				time:          timeMock,
+				auditRecorder: auditRecorder,
+			}
+
+			token, err := a.MagicLinkCallback(context.Background(), "token", tc.nonce)
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, token.MfaRequired)
+			assert.Equal(t, "mfa", token.MfaToken)
+		})
+	}
+}
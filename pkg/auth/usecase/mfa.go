@@ -0,0 +1,374 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/totp"
+	"log"
+	"strings"
+)
+
+// EnrollTotp starts TOTP enrollment of the current user, the secret is pending until ConfirmTotp.
+func (a *AuthUseCase) EnrollTotp(ctx context.Context) (enrollment common.TotpEnrollment, err error) {
+	if err = rejectApiKey(ctx); err != nil {
+		return
+	}
+
+	if err = rejectImpersonation(ctx); err != nil {
+		return
+	}
+
+	user, err := a.currentUser(ctx)
+	if err != nil {
+		return
+	}
+
+	if user.TotpEnabledAt != nil {
+		err = common.ErrTotpAlreadyEnabled
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		err = fmt.Errorf("generate totp secret err: %+v", err)
+		return
+	}
+
+	if err = a.userRepo.UpdateUserTotp(ctx, user.ID, secret, nil); err != nil {
+		err = fmt.Errorf("update totp err: %+v", err)
+		return
+	}
+
+	enrollment.Secret = secret
+	enrollment.URI = totp.URI(a.totpIssuer(), user.Email, secret)
+	return
+}
+
+// ConfirmTotp enables TOTP once the user proves the authenticator works, and returns fresh recovery codes.
+// Wrong codes count towards the lockout of the account like failed logins.
+func (a *AuthUseCase) ConfirmTotp(ctx context.Context, code string) (codes common.RecoveryCodes, err error) {
+	if err = rejectApiKey(ctx); err != nil {
+		return
+	}
+
+	if err = rejectImpersonation(ctx); err != nil {
+		return
+	}
+
+	user, err := a.currentUser(ctx)
+	if err != nil {
+		return
+	}
+
+	if user.TotpEnabledAt != nil {
+		err = common.ErrTotpAlreadyEnabled
+		return
+	}
+
+	if user.TotpSecret == "" {
+		err = common.ErrTotpNotEnrolled
+		return
+	}
+
+	clientIP, _ := general.GetClientIPFromCtx(ctx)
+	if err = a.checkLoginAllowed(user.Email, clientIP); err != nil {
+		return
+	}
+
+	valid, err := a.validateTotpCode(user, code)
+	if err != nil {
+		return
+	}
+
+	if !valid {
+		err = a.recordMfaFailure(ctx, user, clientIP)
+		return
+	}
+
+	now := a.time.Now()
+	if err = a.userRepo.UpdateUserTotp(ctx, user.ID, user.TotpSecret, &now); err != nil {
+		err = fmt.Errorf("update totp err: %+v", err)
+		return
+	}
+
+	codes, err = a.generateRecoveryCodes(user.ID)
+	if err != nil {
+		return
+	}
+
+	log.Printf("totp enabled: user_id=%d", user.ID)
+	return
+}
+
+// DisableTotp disables TOTP, the user has to provide a valid TOTP or recovery code.
+// Wrong codes count towards the lockout of the account like failed logins.
+func (a *AuthUseCase) DisableTotp(ctx context.Context, code string) (err error) {
+	if err = rejectApiKey(ctx); err != nil {
+		return
+	}
+
+	if err = rejectImpersonation(ctx); err != nil {
+		return
+	}
+
+	user, err := a.currentUser(ctx)
+	if err != nil {
+		return
+	}
+
+	if user.TotpEnabledAt == nil {
+		return common.ErrTotpNotEnabled
+	}
+
+	clientIP, _ := general.GetClientIPFromCtx(ctx)
+	if err = a.checkLoginAllowed(user.Email, clientIP); err != nil {
+		return
+	}
+
+	valid, err := a.verifySecondFactor(user, code, code)
+	if err != nil {
+		return
+	}
+
+	if !valid {
+		return a.recordMfaFailure(ctx, user, clientIP)
+	}
+
+	if err = a.userRepo.UpdateUserTotp(ctx, user.ID, "", nil); err != nil {
+		return fmt.Errorf("update totp err: %+v", err)
+	}
+
+	if err = a.recoveryCodeRepo.DeleteRecoveryCodesByUserID(user.ID); err != nil {
+		return fmt.Errorf("delete recovery codes err: %+v", err)
+	}
+
+	log.Printf("totp disabled: user_id=%d", user.ID)
+	return nil
+}
+
+// LoginMfa completes a login started by Login, exchanging the mfa pending token and
+// a TOTP or recovery code for the usual token pair.
+func (a *AuthUseCase) LoginMfa(ctx context.Context, mfaToken, code, recoveryCode string) (token common.LoginToken,
+	err error) {
+	valid, user, jwtData, err := a.extractAndValidateToken(ctx, mfaToken, common.MfaPendingTokenType)
+	if err != nil {
+		return
+	}
+
+	if !valid || user.TotpEnabledAt == nil {
+		err = common.ErrMfaTokenInvalid
+		return
+	}
+
+	// limit guesses per pending token
+	attempts, err := a.redis.Incr(mfaAttemptsCacheKey(jwtData.TokenID), int(common.MfaPendingTokenLifetime.Seconds()))
+	if err != nil {
+		err = fmt.Errorf("count mfa attempts err: %+v", err)
+		return
+	}
+
+	if attempts > common.MfaMaxAttempts {
+		err = common.ErrMfaTokenInvalid
+		return
+	}
+
+	// second factor failures count towards the lockout of the account, whatever the pending token
+	clientIP, _ := general.GetClientIPFromCtx(ctx)
+	if err = a.checkLoginAllowed(user.Email, clientIP); err != nil {
+		return
+	}
+
+	// pending token can only be exchanged once, claimed before checking so a replayed token can't burn
+	// recovery codes
+	consumed, err := a.redis.SetNX(mfaConsumedCacheKey(jwtData.TokenID), "1",
+		int(common.MfaPendingTokenLifetime.Seconds()))
+	if err != nil {
+		err = fmt.Errorf("consume mfa token err: %+v", err)
+		return
+	}
+
+	if !consumed {
+		err = common.ErrMfaTokenInvalid
+		return
+	}
+
+	valid, err = a.verifySecondFactor(user, code, recoveryCode)
+	if err != nil {
+		return
+	}
+
+	if !valid {
+		// give the token back for another attempt, still limited by MfaMaxAttempts
+		if err = a.redis.Del(mfaConsumedCacheKey(jwtData.TokenID)); err != nil {
+			err = fmt.Errorf("release mfa token err: %+v", err)
+			return
+		}
+
+		err = a.recordMfaFailure(ctx, user, clientIP)
+		return
+	}
+
+	if err = a.resetLoginFailures(user.Email); err != nil {
+		return
+	}
+
+	return a.generateLoginToken(ctx, user.ID)
+}
+
+// generate short-lived token proving the password was checked, only LoginMfa accepts it
+func (a *AuthUseCase) generateMfaPendingToken(ctx context.Context, userID int64) (token common.LoginToken, err error) {
+	mfaToken, err := a.generateToken(ctx, uuid.New().String(), uuid.New().String(), userID,
+		common.MfaPendingTokenType, common.MfaPendingTokenLifetime)
+	if err != nil {
+		return
+	}
+
+	token.MfaRequired = true
+	token.MfaToken = mfaToken
+	return
+}
+
+// count a wrong second factor code towards the lockout of the account, it returns ErrMfaCodeInvalid on success
+func (a *AuthUseCase) recordMfaFailure(ctx context.Context, user domain.User, clientIP string) (err error) {
+	a.auditRecorder.Record(ctx, domain.AuditEvent{
+		Type:   auditCommon.EventLoginFailure,
+		UserID: user.ID,
+		Detail: common.ErrMfaCodeInvalid.Error(),
+	})
+
+	if err = a.recordLoginFailure(user.Email, clientIP); errors.Is(err, common.ErrInvalidCredentials) {
+		err = common.ErrMfaCodeInvalid
+	}
+
+	return
+}
+
+// check TOTP code, or recovery code when given
+func (a *AuthUseCase) verifySecondFactor(user domain.User, code, recoveryCode string) (valid bool, err error) {
+	if code != "" {
+		valid, err = a.validateTotpCode(user, code)
+		if err != nil || valid {
+			return
+		}
+	}
+
+	if recoveryCode == "" {
+		return false, nil
+	}
+
+	valid, err = a.recoveryCodeRepo.UseRecoveryCode(user.ID, general.HashToken(normalizeRecoveryCode(recoveryCode)),
+		a.time.Now())
+	if err != nil {
+		return false, fmt.Errorf("use recovery code err: %+v", err)
+	}
+
+	if valid {
+		log.Printf("recovery code used: user_id=%d", user.ID)
+	}
+
+	return
+}
+
+// check TOTP code, every code is accepted only once
+func (a *AuthUseCase) validateTotpCode(user domain.User, code string) (valid bool, err error) {
+	valid, step := totp.Validate(user.TotpSecret, code, a.time.Now())
+	if !valid {
+		return false, nil
+	}
+
+	window := int(totp.Period.Seconds()) * (2*totp.Skew + 1)
+
+	valid, err = a.redis.SetNX(totpUsedCacheKey(user.ID, step), "1", window)
+	if err != nil {
+		return false, fmt.Errorf("check totp replay err: %+v", err)
+	}
+
+	return valid, nil
+}
+
+// replace recovery codes of the user, only their hashes are stored
+func (a *AuthUseCase) generateRecoveryCodes(userID int64) (codes common.RecoveryCodes, err error) {
+	if err = a.recoveryCodeRepo.DeleteRecoveryCodesByUserID(userID); err != nil {
+		err = fmt.Errorf("delete recovery codes err: %+v", err)
+		return
+	}
+
+	now := a.time.Now()
+	rows := make([]domain.RecoveryCode, 0, common.RecoveryCodeCount)
+	codes.Codes = make([]string, 0, common.RecoveryCodeCount)
+
+	for i := 0; i < common.RecoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err = rand.Read(b); err != nil {
+			err = fmt.Errorf("generate recovery code err: %+v", err)
+			return
+		}
+
+		// 8 base32 chars, displayed as xxxx-xxxx
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+		codes.Codes = append(codes.Codes, fmt.Sprintf("%s-%s", raw[:4], raw[4:]))
+
+		rows = append(rows, domain.RecoveryCode{
+			UserID:    userID,
+			CodeHash:  general.HashToken(raw),
+			CreatedAt: now,
+		})
+	}
+
+	if err = a.recoveryCodeRepo.InsertRecoveryCodes(rows); err != nil {
+		err = fmt.Errorf("insert recovery codes err: %+v", err)
+		return
+	}
+
+	return
+}
+
+// get user of the current request
+func (a *AuthUseCase) currentUser(ctx context.Context) (user domain.User, err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = common.ErrAuthUnauthenticated
+		return
+	}
+
+	user, err = a.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		err = common.ErrUserNotFound
+		return
+	}
+
+	return
+}
+
+func (a *AuthUseCase) totpIssuer() string {
+	if a.config.Title != "" {
+		return a.config.Title
+	}
+
+	return "go-template"
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
+
+func mfaAttemptsCacheKey(tokenID string) string {
+	return fmt.Sprintf("mfa-attempts:%s", tokenID)
+}
+
+func mfaConsumedCacheKey(tokenID string) string {
+	return fmt.Sprintf("mfa-consumed:%s", tokenID)
+}
+
+func totpUsedCacheKey(userID int64, step uint64) string {
+	return fmt.Sprintf("totp-used:%d:%d", userID, step)
+}
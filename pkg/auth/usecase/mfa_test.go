@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/totp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// mfaTestCase is shared by the tests of the code checks of the signed in user
+type mfaTestCase struct {
+	name     string
+	locked   bool  // the account is locked out already
+	valid    bool  // the code is right
+	failures int64 // failed attempts of the account, with this one
+	err      error
+}
+
+var mfaTestCases = []mfaTestCase{
+	{name: "valid code", valid: true},
+	{name: "wrong code counted", failures: 1, err: common.ErrMfaCodeInvalid},
+	{name: "wrong code locks out", failures: 5, err: common.ErrMfaCodeInvalid},
+	{name: "locked out", locked: true, err: common.ErrAccountLocked},
+}
+
+// expect the lockout check, and the failure count when the code is wrong
+func expectMfaAttempt(redisMock *redis.MockInterface, auditRecorder *domain.MockAuditRecorder, tc mfaTestCase) {
+	if tc.locked {
+		redisMock.EXPECT().Get(loginLockoutCacheKey("account", "user@mail.com")).Return("1", nil)
+		return
+	}
+
+	redisMock.EXPECT().Get(loginLockoutCacheKey("account", "user@mail.com")).Return(nil, redis.ErrNilReturned)
+	redisMock.EXPECT().Get(loginBackoffCacheKey("account", "user@mail.com")).Return(nil, redis.ErrNilReturned)
+
+	if tc.valid {
+		redisMock.EXPECT().SetNX(gomock.Any(), "1", gomock.Any()).Return(true, nil)
+		return
+	}
+
+	auditRecorder.EXPECT().Record(gomock.Any(), gomock.Any())
+	redisMock.EXPECT().Incr(loginFailuresCacheKey("account", "user@mail.com"), 900).Return(tc.failures, nil)
+
+	if tc.failures >= 5 {
+		redisMock.EXPECT().Set(loginLockoutCacheKey("account", "user@mail.com"), "1", 900)
+	}
+}
+
+func TestAuthUseCase_ConfirmTotp(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx := general.SetUserIDIntoCtx(context.Background(), 1)
+
+	secret, err := totp.GenerateSecret()
+	assert.NoError(t, err)
+
+	validCode, err := totp.GenerateCode(secret, now)
+	assert.NoError(t, err)
+
+	for _, tc := range mfaTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			redisMock := redis.NewMockInterface(ctrl)
+			userRepo := domain.NewMockUserRepository(ctrl)
+			recoveryCodeRepo := domain.NewMockRecoveryCodeRepository(ctrl)
+			auditRecorder := domain.NewMockAuditRecorder(ctrl)
+			timeMock := commonTime.NewMockTimeInterface(ctrl)
+
+			timeMock.EXPECT().Now().Return(now).AnyTimes()
+
+			userRepo.EXPECT().FindUserByID(gomock.Any(), int64(1)).
+				Return(domain.User{ID: 1, Email: "user@mail.com", TotpSecret: secret}, nil)
+
+			expectMfaAttempt(redisMock, auditRecorder, tc)
+
+			code := "wrong"
+			if tc.valid {
+				code = validCode
+				userRepo.EXPECT().UpdateUserTotp(gomock.Any(), int64(1), secret, &now)
+				recoveryCodeRepo.EXPECT().DeleteRecoveryCodesByUserID(int64(1))
+				recoveryCodeRepo.EXPECT().InsertRecoveryCodes(gomock.Any())
+			}
+
+			a := &AuthUseCase{
+				userRepo:         userRepo,
+				recoveryCodeRepo: recoveryCodeRepo,
+				redis:            redisMock,
+				time:             timeMock,
+				auditRecorder:    auditRecorder,
+				config: config.Config{LoginProtection: config.LoginProtectionConfig{MaxAccountAttempts: 5,
+					AttemptWindow: 15 * time.Minute, LockoutDuration: 15 * time.Minute}},
+			}
+
+			codes, err := a.ConfirmTotp(ctx, code)
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, codes.Codes, common.RecoveryCodeCount)
+		})
+	}
+}
+
+func TestAuthUseCase_DisableTotp(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx := general.SetUserIDIntoCtx(context.Background(), 1)
+
+	secret, err := totp.GenerateSecret()
+	assert.NoError(t, err)
+
+	validCode, err := totp.GenerateCode(secret, now)
+	assert.NoError(t, err)
+
+	for _, tc := range mfaTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			redisMock := redis.NewMockInterface(ctrl)
+			userRepo := domain.NewMockUserRepository(ctrl)
+			recoveryCodeRepo := domain.NewMockRecoveryCodeRepository(ctrl)
+			auditRecorder := domain.NewMockAuditRecorder(ctrl)
+			timeMock := commonTime.NewMockTimeInterface(ctrl)
+
+			timeMock.EXPECT().Now().Return(now).AnyTimes()
+
+			userRepo.EXPECT().FindUserByID(gomock.Any(), int64(1)).
+				Return(domain.User{ID: 1, Email: "user@mail.com", TotpSecret: secret, TotpEnabledAt: &now}, nil)
+
+			expectMfaAttempt(redisMock, auditRecorder, tc)
+
+			code := "wrong"
+			if tc.valid {
+				code = validCode
+				userRepo.EXPECT().UpdateUserTotp(gomock.Any(), int64(1), "", gomock.Nil())
+				recoveryCodeRepo.EXPECT().DeleteRecoveryCodesByUserID(int64(1))
+			} else if !tc.locked {
+				// the code is tried as a recovery code as well
+				recoveryCodeRepo.EXPECT().UseRecoveryCode(int64(1), general.HashToken("wrong"), now)
+			}
+
+			a := &AuthUseCase{
+				userRepo:         userRepo,
+				recoveryCodeRepo: recoveryCodeRepo,
+				redis:            redisMock,
+				time:             timeMock,
+				auditRecorder:    auditRecorder,
+				config: config.Config{LoginProtection: config.LoginProtectionConfig{MaxAccountAttempts: 5,
+					AttemptWindow: 15 * time.Minute, LockoutDuration: 15 * time.Minute}},
+			}
+
+			err := a.DisableTotp(ctx, code)
+			assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+		})
+	}
+}
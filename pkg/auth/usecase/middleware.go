@@ -5,57 +5,155 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
 	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
 	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
 	"github.com/lactobasilusprotectus/go-template/pkg/domain"
 	httputil "github.com/lactobasilusprotectus/go-template/pkg/util/http"
 	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"strings"
+	"time"
 )
 
+// MustLogin authenticates the request with either a Bearer access token or an ApiKey
 func (a *AuthUseCase) MustLogin() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
 
 		// Get token from request
-		token := general.GetTokenFromRequest(c)
+		scheme, token := general.GetAuthorizationFromRequest(c, a.config.Http.Cookie.Enabled)
 
 		if token == "" {
 			httputil.WriteUnauthorizedResponse(c)
+			c.Abort()
 			return
 		}
 
-		// Extract and validate token
-		tokenValid, user, sessionID, err := a.extractAndValidateToken(ctx, token, common.AccessTokenType)
+		var err error
+		if strings.EqualFold(scheme, general.AuthSchemeApiKey) {
+			ctx, err = a.authenticateApiKey(ctx, token)
+		} else {
+			ctx, err = a.authenticateAccessToken(ctx, token)
+		}
 
-		if err != nil {
+		if errors.Is(err, common.ErrAuthUnauthenticated) {
 			httputil.WriteUnauthorizedResponse(c)
+			c.Abort()
 			return
 		}
 
-		if !tokenValid {
-			httputil.WriteUnauthorizedResponse(c)
+		if err != nil {
+			httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+			c.Abort()
 			return
 		}
 
-		// write session information into context
-		ctx = general.SetUserIDIntoCtx(ctx, user.ID)      // int64
-		ctx = general.SetSessionIDIntoCtx(ctx, sessionID) // string
-
 		newReq := c.Request.WithContext(ctx)
 		c.Request = newReq
 
+		// whatever an admin does as another user is kept track of
+		if _, ok := general.GetActorIDFromCtx(ctx); ok {
+			a.auditRecorder.Record(ctx, domain.AuditEvent{
+				Type:   auditCommon.EventImpersonatedRequest,
+				Detail: fmt.Sprintf("%s %s", c.Request.Method, c.Request.URL.Path),
+			})
+		}
+
 		// Call the next handler, which can be another middleware in the chain, or the final handler.
 		c.Next()
 	}
 }
 
+// authenticate access token and write session information into context
+func (a *AuthUseCase) authenticateAccessToken(ctx context.Context, token string) (context.Context, error) {
+	// Extract and validate token
+	tokenValid, user, jwtData, err := a.extractAndValidateToken(ctx, token, common.AccessTokenType)
+
+	if err != nil || !tokenValid {
+		return ctx, common.ErrAuthUnauthenticated
+	}
+
+	// session must be registered, keep track of its activity
+	session, err := a.sessionRepo.FindSessionByID(jwtData.SessionID)
+
+	if err != nil {
+		return ctx, common.ErrAuthUnauthenticated
+	}
+
+	// the session must be the one of the user, tokens issued before switching organization are done with
+	if session.UserID != jwtData.IdentityID || session.OrganizationID != jwtData.TenantID {
+		return ctx, common.ErrAuthUnauthenticated
+	}
+
+	// saves a write on every request, the activity doesn't need to be precise. Only the activity is written, the
+	// session read above might have been changed or deleted meanwhile.
+	if now := a.time.Now(); now.Sub(session.LastSeenAt) >= common.SessionLastSeenResolution {
+		err = a.sessionRepo.TouchSession(session.ID, now)
+
+		if errors.Is(err, common.ErrSessionNotFound) {
+			return ctx, common.ErrAuthUnauthenticated
+		}
+
+		if err != nil {
+			return ctx, err
+		}
+	}
+
+	// write session information into context
+	ctx = general.SetUserIDIntoCtx(ctx, user.ID)                // int64
+	ctx = general.SetSessionIDIntoCtx(ctx, jwtData.SessionID)   // string
+	ctx = general.SetTokenExpiryIntoCtx(ctx, jwtData.ExpiresAt) // time.Time
+
+	if jwtData.Roles != nil {
+		ctx = general.SetRolesIntoCtx(ctx, jwtData.Roles) // []string
+	}
+
+	if jwtData.ActorID != 0 {
+		ctx = general.SetActorIDIntoCtx(ctx, jwtData.ActorID) // int64
+	}
+
+	if jwtData.TenantID != 0 {
+		ctx = general.SetTenantIDIntoCtx(ctx, jwtData.TenantID) // int64
+	}
+
+	return ctx, nil
+}
+
+// authenticate api key and write its owner and scopes into context
+func (a *AuthUseCase) authenticateApiKey(ctx context.Context, key string) (context.Context, error) {
+	valid, apiKey, err := a.validateApiKey(key)
+
+	if err != nil {
+		return ctx, err
+	}
+
+	if !valid {
+		return ctx, common.ErrAuthUnauthenticated
+	}
+
+	// the owner might have been removed since, from the organization as well
+	if apiKey.OrganizationID != 0 {
+		ctx = general.SetTenantIDIntoCtx(ctx, apiKey.OrganizationID) // int64
+	}
+
+	if _, err = a.userRepo.FindUserByID(ctx, apiKey.UserID); err != nil {
+		return ctx, common.ErrAuthUnauthenticated
+	}
+
+	ctx = general.SetUserIDIntoCtx(ctx, apiKey.UserID)                            // int64
+	ctx = general.SetApiKeyIntoCtx(ctx, apiKey.ID, strings.Fields(apiKey.Scopes)) // int64, []string
+
+	return ctx, nil
+}
+
 func (a *AuthUseCase) extractAndValidateToken(ctx context.Context, token string, tokenType string) (valid bool,
-	user domain.User, sessionID string, err error) {
+	user domain.User, data jwt.JwtData, err error) {
 	// initially, it is invalid
 	valid = false
 
 	// extract token
-	jwtData, err := a.jwtModule.ExtractToken(ctx, token)
+	jwtData, err := a.jwtModule.ExtractToken(ctx, token, tokenType)
 
 	if errors.Is(err, jwt.ErrTokenExpired) || errors.Is(err, jwt.ErrTokenInvalid) {
 		// token has expired or it is invalid
@@ -85,7 +183,7 @@ func (a *AuthUseCase) extractAndValidateToken(ctx context.Context, token string,
 	}
 
 	// get user from repository
-	users, err := a.userRepo.FindUserByID(userID)
+	users, err := a.userRepo.FindUserByID(ctx, userID)
 	if err != nil {
 		err = fmt.Errorf("GetUserByIDs error: %+v", err)
 		return
@@ -93,7 +191,7 @@ func (a *AuthUseCase) extractAndValidateToken(ctx context.Context, token string,
 
 	valid = true
 	user = users
-	sessionID = jwtData.SessionID
+	data = jwtData
 	return
 }
 
@@ -113,13 +211,43 @@ func (a *AuthUseCase) isSessionInvalidated(sessionID string) (invalidated bool,
 	}
 
 	// cache return err other than ErrNilReturned
-	if err != nil {
+	if err != nil && !errors.Is(err, redis.ErrNilReturned) {
 		return false, fmt.Errorf("isSessionInvalidated err: %+v", err)
 	}
 
 	return false, nil
 }
 
+// mark session as invalidated in cache, the marker lives as long as the session could still be used
+func (a *AuthUseCase) invalidateSession(sessionID string, ttl time.Duration) (err error) {
+	err = a.redis.Set(invalidSessionCacheKey(sessionID), common.SessionInvalidated, int(ttl.Seconds()))
+	if err != nil {
+		return fmt.Errorf("invalidateSession err: %+v", err)
+	}
+
+	return nil
+}
+
+// revoke session until its refresh token would have expired
+func (a *AuthUseCase) revokeSession(sessionID string) (err error) {
+	ttl, err := a.redis.TTL(refreshTokenCacheKey(sessionID))
+	if err != nil {
+		return fmt.Errorf("revokeSession err: %+v", err)
+	}
+
+	// access tokens of the session might outlive its refresh token, impersonation sessions have none at all
+	lifetime := time.Duration(ttl) * time.Second
+	if lifetime < common.ImpersonationTokenLifetime {
+		lifetime = common.ImpersonationTokenLifetime
+	}
+
+	return a.invalidateSession(sessionID, lifetime)
+}
+
 func invalidSessionCacheKey(token string) string {
 	return fmt.Sprintf("session-invalid:%s", token)
 }
+
+func refreshTokenCacheKey(sessionID string) string {
+	return fmt.Sprintf("session-refresh-token:%s", sessionID)
+}
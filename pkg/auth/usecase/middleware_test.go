@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAuthUseCase_authenticateAccessToken(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tokenData := jwt.JwtData{SessionID: "session", IdentityID: 1, TenantID: 2, Type: common.AccessTokenType}
+
+	testCases := []struct {
+		name     string
+		session  domain.Session
+		touchErr error // nil when the session is touched successfully
+		touched  bool
+		err      error
+	}{
+		{
+			name:    "recently seen",
+			session: domain.Session{ID: "session", UserID: 1, OrganizationID: 2, LastSeenAt: now.Add(-time.Second)},
+		},
+		{
+			name:    "activity recorded",
+			session: domain.Session{ID: "session", UserID: 1, OrganizationID: 2, LastSeenAt: now.Add(-time.Hour)},
+			touched: true,
+		},
+		{
+			name:     "deleted meanwhile",
+			session:  domain.Session{ID: "session", UserID: 1, OrganizationID: 2, LastSeenAt: now.Add(-time.Hour)},
+			touched:  true,
+			touchErr: common.ErrSessionNotFound,
+			err:      common.ErrAuthUnauthenticated,
+		},
+		{
+			name:    "session of another user",
+			session: domain.Session{ID: "session", UserID: 3, OrganizationID: 2},
+			err:     common.ErrAuthUnauthenticated,
+		},
+		{
+			name:    "organization switched since",
+			session: domain.Session{ID: "session", UserID: 1, OrganizationID: 4},
+			err:     common.ErrAuthUnauthenticated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			jwtModule := jwt.NewMockJwtInterface(ctrl)
+			redisMock := redis.NewMockInterface(ctrl)
+			userRepo := domain.NewMockUserRepository(ctrl)
+			sessionRepo := domain.NewMockSessionRepository(ctrl)
+			timeMock := commonTime.NewMockTimeInterface(ctrl)
+
+			timeMock.EXPECT().Now().Return(now).AnyTimes()
+			jwtModule.EXPECT().ExtractToken(gomock.Any(), "token", common.AccessTokenType).Return(tokenData, nil)
+			redisMock.EXPECT().Get(invalidSessionCacheKey("session")).Return(nil, redis.ErrNilReturned)
+			userRepo.EXPECT().FindUserByID(gomock.Any(), int64(1)).Return(domain.User{ID: 1}, nil)
+			sessionRepo.EXPECT().FindSessionByID("session").Return(tc.session, nil)
+
+			// the session itself is never written back, a concurrent change would be reverted
+			if tc.touched {
+				sessionRepo.EXPECT().TouchSession("session", now).Return(tc.touchErr)
+			}
+
+			a := &AuthUseCase{
+				userRepo:    userRepo,
+				sessionRepo: sessionRepo,
+				jwtModule:   jwtModule,
+				redis:       redisMock,
+				time:        timeMock,
+			}
+
+			ctx, err := a.authenticateAccessToken(context.Background(), "token")
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			sessionID, _ := general.GetSessionIDFromCtx(ctx)
+			assert.Equal(t, "session", sessionID)
+
+			tenantID, _ := general.GetTenantIDFromCtx(ctx)
+			assert.Equal(t, int64(2), tenantID)
+		})
+	}
+}
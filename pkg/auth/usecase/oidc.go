@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/oidc"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"gorm.io/gorm"
+	"log"
+	"sort"
+	"strings"
+)
+
+// oidcLoginState is what we remember between sending the user to the provider and the callback
+type oidcLoginState struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+// ListOidcProviders lists names of the configured identity providers.
+func (a *AuthUseCase) ListOidcProviders(ctx context.Context) (providers []string) {
+	providers = make([]string, 0, len(a.oidcProviders))
+	for name := range a.oidcProviders {
+		providers = append(providers, name)
+	}
+
+	sort.Strings(providers)
+	return providers
+}
+
+// OidcAuthorize returns the url of the provider the user has to log in at.
+func (a *AuthUseCase) OidcAuthorize(ctx context.Context, provider string) (authorization common.OidcAuthorization,
+	err error) {
+	idp, ok := a.oidcProviders[provider]
+	if !ok {
+		err = common.ErrOidcProviderUnknown
+		return
+	}
+
+	state, err := general.GenerateRandomToken(32)
+	if err != nil {
+		return
+	}
+
+	nonce, err := general.GenerateRandomToken(32)
+	if err != nil {
+		return
+	}
+
+	codeVerifier, err := general.GenerateRandomToken(32)
+	if err != nil {
+		return
+	}
+
+	loginState, err := json.Marshal(oidcLoginState{Provider: provider, Nonce: nonce, CodeVerifier: codeVerifier})
+	if err != nil {
+		err = fmt.Errorf("marshal login state err: %+v", err)
+		return
+	}
+
+	err = a.redis.Set(oidcStateCacheKey(state), string(loginState), int(common.OidcStateLifetime.Seconds()))
+	if err != nil {
+		err = fmt.Errorf("store login state err: %+v", err)
+		return
+	}
+
+	authorization.AuthorizationURL, err = idp.AuthCodeURL(ctx, state, nonce, oidc.CodeChallengeS256(codeVerifier))
+	if err != nil {
+		err = fmt.Errorf("build authorization url err: %+v", err)
+		return
+	}
+
+	// the state travels in urls, the binding only lives in the browser starting the login
+	authorization.StateBinding = general.HashToken(state)
+	return
+}
+
+// OidcCallback completes login at the provider, then logs in the linked local user, linking or creating one first
+// if needed. The state must come back along with the binding of the browser starting the login, so a callback url
+// of someone else's login can't be planted.
+func (a *AuthUseCase) OidcCallback(ctx context.Context, provider, state, stateBinding, code string) (
+	token common.LoginToken, err error) {
+	idp, ok := a.oidcProviders[provider]
+	if !ok {
+		err = common.ErrOidcProviderUnknown
+		return
+	}
+
+	// checked before the state is consumed, so another browser can't use up a leaked state
+	if subtle.ConstantTimeCompare([]byte(stateBinding), []byte(general.HashToken(state))) != 1 {
+		err = common.ErrOidcStateInvalid
+		return
+	}
+
+	// state is single use
+	reply, err := a.redis.GetDel(oidcStateCacheKey(state))
+	if errors.Is(err, redis.ErrNilReturned) {
+		err = common.ErrOidcStateInvalid
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("consume login state err: %+v", err)
+		return
+	}
+
+	var loginState oidcLoginState
+	if err = json.Unmarshal([]byte(fmt.Sprint(reply)), &loginState); err != nil || loginState.Provider != provider {
+		err = common.ErrOidcStateInvalid
+		return
+	}
+
+	identity, err := idp.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		log.Printf("oidc login failed: provider=%s, err=%+v", provider, err)
+		err = common.ErrOidcLoginFailed
+		return
+	}
+
+	user, err := a.resolveOidcUser(ctx, provider, identity)
+	if err != nil {
+		return
+	}
+
+	log.Printf("oidc login: provider=%s, user_id=%d", provider, user.ID)
+	return a.completeLogin(ctx, user)
+}
+
+// find the user linked to the identity, otherwise link the user owning the same verified email or create one
+func (a *AuthUseCase) resolveOidcUser(ctx context.Context, provider string, identity oidc.Identity) (user domain.User,
+	err error) {
+	linked, err := a.identityRepo.FindUserIdentity(provider, identity.Subject)
+	if err == nil {
+		user, err = a.userRepo.FindUserByID(ctx, linked.UserID)
+
+		// the linked user was deleted, the identity is purged along with it later
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("oidc login of deleted user: provider=%s, user_id=%d", provider, linked.UserID)
+			err = common.ErrOidcLoginFailed
+			return
+		}
+
+		if err != nil {
+			err = fmt.Errorf("find linked user err: %+v", err)
+		}
+		return
+	}
+
+	if !errors.Is(err, common.ErrIdentityNotFound) {
+		err = fmt.Errorf("find identity err: %+v", err)
+		return
+	}
+
+	if identity.Email == "" {
+		err = common.ErrOidcEmailRequired
+		return
+	}
+
+	email := normalizeEmail(identity.Email)
+
+	user, err = a.userRepo.FindUserByEmail(ctx, email)
+	if err == nil {
+		// linking on an unverified email would let anyone take over the account
+		if !identity.EmailVerified {
+			err = common.ErrOidcAccountConflict
+			return
+		}
+
+		if user.EmailVerifiedAt == nil {
+			if err = a.userRepo.UpdateUserEmailVerifiedAt(ctx, user.ID, a.time.Now()); err != nil {
+				err = fmt.Errorf("update email verified err: %+v", err)
+				return
+			}
+		}
+	} else {
+		if user, err = a.createOidcUser(ctx, email, identity); err != nil {
+			return
+		}
+	}
+
+	err = a.identityRepo.InsertUserIdentity(domain.UserIdentity{
+		UserID:    user.ID,
+		Provider:  provider,
+		Subject:   identity.Subject,
+		Email:     email,
+		CreatedAt: a.time.Now(),
+	})
+	if err != nil {
+		err = fmt.Errorf("link identity err: %+v", err)
+		return
+	}
+
+	log.Printf("oidc identity linked: provider=%s, user_id=%d", provider, user.ID)
+	return user, nil
+}
+
+// create local user for the identity, with an unusable random password which can be set through password reset.
+// A pending invitation of the email is accepted, in the invite registration mode only invited emails get a user.
+func (a *AuthUseCase) createOidcUser(ctx context.Context, email string, identity oidc.Identity) (user domain.User,
+	err error) {
+	invitation, err := a.findOidcInvitation(email, identity.EmailVerified)
+	if err != nil {
+		return
+	}
+
+	randomPassword, err := general.GenerateRandomToken(32)
+	if err != nil {
+		return
+	}
+
+	hashedPassword, err := a.passwordHasher.Hash(randomPassword)
+	if err != nil {
+		err = fmt.Errorf("something wrong: %w", err)
+		return
+	}
+
+	suffix, err := general.GenerateRandomToken(4)
+	if err != nil {
+		return
+	}
+
+	user = domain.User{
+		Username: fmt.Sprintf("%s-%s", strings.SplitN(email, "@", 2)[0], strings.ToLower(suffix)),
+		Email:    email,
+		Password: hashedPassword,
+	}
+
+	if identity.EmailVerified {
+		now := a.time.Now()
+		user.EmailVerifiedAt = &now
+	}
+
+	if invitation.ID != 0 {
+		if err = a.insertInvitedUser(&user, invitation); err != nil {
+			return
+		}
+	} else {
+		if err = a.userRepo.InsertUser(ctx, user); err != nil {
+			err = fmt.Errorf("insert user err: %+v", err)
+			return
+		}
+
+		// InsertUser doesn't return the generated ID
+		user, err = a.userRepo.FindUserByEmail(ctx, email)
+		if err != nil {
+			err = fmt.Errorf("find created user err: %+v", err)
+			return
+		}
+	}
+
+	log.Printf("user created from oidc identity: user_id=%d", user.ID)
+
+	if err = a.assignDefaultRole(user.ID); err != nil {
+		return
+	}
+
+	if invitation.ID != 0 {
+		if err = a.acceptInvitation(ctx, user, invitation); err != nil {
+			return
+		}
+	}
+
+	return user, nil
+}
+
+func oidcStateCacheKey(state string) string {
+	return fmt.Sprintf("oidc-state:%s", general.HashToken(state))
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	organizationCommon "github.com/lactobasilusprotectus/go-template/pkg/organization/common"
+	"log"
+)
+
+// SwitchOrganization scopes the current session to another organization of the user and issues a new token pair.
+// Tokens issued for the previous organization stop working right away.
+func (a *AuthUseCase) SwitchOrganization(ctx context.Context, organizationID int64) (token common.LoginToken,
+	err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = common.ErrAuthUnauthenticated
+		return
+	}
+
+	// api keys are bound to the organization they were created within, and have no session anyway
+	if _, ok = general.GetApiKeyIDFromCtx(ctx); ok {
+		err = common.ErrApiKeyNotAllowed
+		return
+	}
+
+	if err = rejectImpersonation(ctx); err != nil {
+		return
+	}
+
+	sessionID, ok := general.GetSessionIDFromCtx(ctx)
+	if !ok {
+		err = common.ErrAuthUnauthenticated
+		return
+	}
+
+	member, err := a.organizationRepo.IsMember(organizationID, userID)
+	if err != nil {
+		err = fmt.Errorf("find membership err: %+v", err)
+		return
+	}
+
+	if !member {
+		err = organizationCommon.ErrOrganizationNotFound
+		return
+	}
+
+	session, err := a.sessionRepo.FindSessionByID(sessionID)
+	if err != nil {
+		err = common.ErrAuthUnauthenticated
+		return
+	}
+
+	now := a.time.Now()
+
+	// the refresh token issued below can't extend the session either
+	lifetime := refreshLifetime(session, now)
+	if lifetime <= 0 {
+		err = common.ErrAuthUnauthenticated
+		return
+	}
+
+	session.OrganizationID = organizationID
+	session.LastSeenAt = now
+
+	if err = a.sessionRepo.UpdateSession(session); err != nil {
+		err = fmt.Errorf("update session err: %+v", err)
+		return
+	}
+
+	// the refresh token held so far is replaced, presenting it again revokes the session like any reuse
+	refreshTokenID := uuid.New().String()
+
+	err = a.redis.Set(refreshTokenCacheKey(sessionID), refreshTokenID, int(lifetime.Seconds()))
+	if err != nil {
+		err = fmt.Errorf("register refresh token err: %+v", err)
+		return
+	}
+
+	log.Printf("organization switched: user_id=%d, session_id=%s, organization_id=%d", userID, sessionID,
+		organizationID)
+	return a.generateTokenPair(ctx, sessionID, userID, organizationID, refreshTokenID, lifetime)
+}
+
+// the organization a new session of the user is scoped to: the one joined first, 0 when the user has none
+func (a *AuthUseCase) defaultOrganization(userID int64) (organizationID int64, err error) {
+	memberships, err := a.organizationRepo.FindMembershipsByUserID(userID)
+	if err != nil {
+		return 0, fmt.Errorf("find memberships err: %+v", err)
+	}
+
+	if len(memberships) == 0 {
+		return 0, nil
+	}
+
+	return memberships[0].ID, nil
+}
+
+// the organization the session keeps being scoped to on refresh, the default one once the user left it
+func (a *AuthUseCase) refreshOrganization(session domain.Session) (organizationID int64, err error) {
+	if session.OrganizationID == 0 {
+		return a.defaultOrganization(session.UserID)
+	}
+
+	member, err := a.organizationRepo.IsMember(session.OrganizationID, session.UserID)
+	if err != nil {
+		return 0, fmt.Errorf("find membership err: %+v", err)
+	}
+
+	if !member {
+		return a.defaultOrganization(session.UserID)
+	}
+
+	return session.OrganizationID, nil
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	organizationCommon "github.com/lactobasilusprotectus/go-template/pkg/organization/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAuthUseCase_SwitchOrganization(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx := general.SetSessionIDIntoCtx(general.SetUserIDIntoCtx(context.Background(), 1), "session")
+
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		member   bool
+		session  *domain.Session // nil when the session is gone, or not looked up
+		lifetime time.Duration   // of the refresh token issued by the switch
+		err      error
+	}{
+		{name: "switched", ctx: ctx, member: true,
+			session:  &domain.Session{ID: "session", UserID: 1, OrganizationID: 2, CreatedAt: now.Add(-time.Hour)},
+			lifetime: common.RefreshTokenLifetime},
+		{name: "switched until the session lifetime", ctx: ctx, member: true,
+			session: &domain.Session{ID: "session", UserID: 1, OrganizationID: 2,
+				CreatedAt: now.Add(time.Hour - common.SessionLifetime)},
+			lifetime: time.Hour},
+		{name: "session lifetime over", ctx: ctx, member: true,
+			session: &domain.Session{ID: "session", UserID: 1, OrganizationID: 2,
+				CreatedAt: now.Add(-common.SessionLifetime)},
+			err: common.ErrAuthUnauthenticated},
+		{name: "session gone", ctx: ctx, member: true, err: common.ErrAuthUnauthenticated},
+		{name: "not a member", ctx: ctx, err: organizationCommon.ErrOrganizationNotFound},
+		{name: "api key", ctx: general.SetApiKeyIntoCtx(ctx, 1, nil), err: common.ErrApiKeyNotAllowed},
+		{name: "impersonation", ctx: general.SetActorIDIntoCtx(ctx, 9), err: common.ErrImpersonationNotAllowed},
+		{name: "unauthenticated", ctx: context.Background(), err: common.ErrAuthUnauthenticated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			organizationRepo := domain.NewMockOrganizationRepository(ctrl)
+			sessionRepo := domain.NewMockSessionRepository(ctrl)
+			redisMock := redis.NewMockInterface(ctrl)
+			jwtModule := jwt.NewMockJwtInterface(ctrl)
+			timeMock := commonTime.NewMockTimeInterface(ctrl)
+
+			timeMock.EXPECT().Now().Return(now).AnyTimes()
+
+			// membership is only checked for a logged in user in person
+			if tc.member || errors.Is(tc.err, organizationCommon.ErrOrganizationNotFound) {
+				organizationRepo.EXPECT().IsMember(int64(3), int64(1)).Return(tc.member, nil)
+			}
+
+			if tc.member {
+				if tc.session == nil {
+					sessionRepo.EXPECT().FindSessionByID("session").Return(domain.Session{}, common.ErrSessionNotFound)
+				} else {
+					sessionRepo.EXPECT().FindSessionByID("session").Return(*tc.session, nil)
+				}
+			}
+
+			var refreshTokenID string
+			if tc.err == nil {
+				session := *tc.session
+				session.OrganizationID = 3
+				session.LastSeenAt = now
+				sessionRepo.EXPECT().UpdateSession(session)
+
+				// the refresh token held so far is replaced
+				redisMock.EXPECT().Set(refreshTokenCacheKey("session"), gomock.Any(), int(tc.lifetime.Seconds())).
+					DoAndReturn(func(key string, value interface{}, expireSeconds int) error {
+						refreshTokenID = value.(string)
+						return nil
+					})
+
+				jwtModule.EXPECT().GenerateToken(gomock.Any(), gomock.Any()).Times(2).
+					DoAndReturn(func(ctx context.Context, data jwt.JwtData) (string, error) {
+						assert.Equal(t, "session", data.SessionID)
+
+						if data.Type == common.AccessTokenType {
+							assert.Equal(t, int64(3), data.TenantID)
+						} else {
+							assert.Equal(t, refreshTokenID, data.TokenID)
+							assert.Equal(t, tc.lifetime, data.Lifetime)
+						}
+						return data.Type, nil
+					})
+			}
+
+			a := &AuthUseCase{
+				organizationRepo: organizationRepo,
+				sessionRepo:      sessionRepo,
+				jwtModule:        jwtModule,
+				redis:            redisMock,
+				time:             timeMock,
+			}
+
+			token, err := a.SwitchOrganization(tc.ctx, 3)
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, common.AccessTokenType, token.AccessToken)
+			assert.Equal(t, common.RefreshTokenType, token.RefreshToken)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hibiken/asynq"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"gorm.io/gorm"
+	"log"
+	"strconv"
+)
+
+// ForgotPassword emails a single use reset token to the user, at most once per PasswordResetResendInterval.
+// It succeeds whether the email is registered or not, so callers can't probe for accounts: the email is only
+// looked up by the queued task, so requests take as long either way.
+func (a *AuthUseCase) ForgotPassword(ctx context.Context, email string) (err error) {
+	ok, err := a.redis.SetNX(passwordResetThrottleCacheKey(normalizeEmail(email)), "1",
+		int(common.PasswordResetResendInterval.Seconds()))
+	if err != nil {
+		return fmt.Errorf("throttle password reset err: %+v", err)
+	}
+
+	if !ok {
+		return common.ErrTooManyRequests
+	}
+
+	payload, err := json.Marshal(common.PasswordResetPayload{Email: email})
+	if err != nil {
+		return err
+	}
+
+	_, err = a.client.EnqueueTaskContext(ctx, asynq.NewTask(common.TypePasswordResetEmail, payload))
+	if err != nil {
+		return fmt.Errorf("enqueue reset email err: %+v", err)
+	}
+
+	return nil
+}
+
+// ResetPassword consumes the reset token, sets the new password and revokes every session of the user.
+func (a *AuthUseCase) ResetPassword(ctx context.Context, token, newPassword string) (err error) {
+	cacheKey := passwordResetCacheKey(general.HashToken(token))
+
+	// peek first, so a password rejected by the policy doesn't use the token up
+	reply, err := a.redis.Get(cacheKey)
+	if errors.Is(err, redis.ErrNilReturned) {
+		return common.ErrResetTokenInvalid
+	}
+
+	if err != nil {
+		return fmt.Errorf("get reset token err: %+v", err)
+	}
+
+	userID, err := strconv.ParseInt(fmt.Sprint(reply), 10, 64)
+	if err != nil {
+		return common.ErrResetTokenInvalid
+	}
+
+	// only the latest token of the user is valid
+	current, err := a.redis.Get(passwordResetUserCacheKey(userID))
+	if errors.Is(err, redis.ErrNilReturned) || (err == nil && fmt.Sprint(current) != general.HashToken(token)) {
+		return common.ErrResetTokenInvalid
+	}
+
+	if err != nil {
+		return fmt.Errorf("get reset token err: %+v", err)
+	}
+
+	user, err := a.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return common.ErrResetTokenInvalid
+	}
+
+	if err = a.passwordPolicy.Validate(newPassword, user.Username, user.Email); err != nil {
+		return
+	}
+
+	// token can only be consumed once
+	_, err = a.redis.GetDel(cacheKey)
+	if errors.Is(err, redis.ErrNilReturned) {
+		return common.ErrResetTokenInvalid
+	}
+
+	if err != nil {
+		return fmt.Errorf("consume reset token err: %+v", err)
+	}
+
+	if err = a.redis.Del(passwordResetUserCacheKey(userID)); err != nil {
+		return fmt.Errorf("consume reset token err: %+v", err)
+	}
+
+	hashedPassword, err := a.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return fmt.Errorf("something wrong: %w", err)
+	}
+
+	if err = a.userRepo.UpdateUserPassword(ctx, userID, hashedPassword); err != nil {
+		return fmt.Errorf("update password err: %+v", err)
+	}
+
+	// whoever had access to the account loses it
+	if _, err = a.revokeUserSessions(userID); err != nil {
+		return
+	}
+
+	log.Printf("password reset: user_id=%d", userID)
+	a.auditRecorder.Record(ctx, domain.AuditEvent{
+		Type:   auditCommon.EventPasswordChange,
+		UserID: userID,
+		Detail: "password reset, every session revoked",
+	})
+	return nil
+}
+
+// Reauthenticate confirms the current user is at the keyboard before a sensitive action, by the password or, with
+// two-factor authentication, a TOTP or recovery code. Failures count towards the lockout of the account like
+// failed logins.
+func (a *AuthUseCase) Reauthenticate(ctx context.Context, password, code string) (err error) {
+	if err = rejectApiKey(ctx); err != nil {
+		return
+	}
+
+	if err = rejectImpersonation(ctx); err != nil {
+		return
+	}
+
+	user, err := a.currentUser(ctx)
+	if err != nil {
+		return
+	}
+
+	clientIP, _ := general.GetClientIPFromCtx(ctx)
+	if err = a.checkLoginAllowed(user.Email, clientIP); err != nil {
+		return
+	}
+
+	valid := false
+	if password != "" {
+		if valid, err = a.passwordHasher.Verify(password, user.Password); err != nil {
+			return fmt.Errorf("verify password err: %+v", err)
+		}
+	}
+
+	if !valid && code != "" && user.TotpEnabledAt != nil {
+		if valid, err = a.verifySecondFactor(user, code, code); err != nil {
+			return
+		}
+	}
+
+	if !valid {
+		log.Printf("reauthentication failed: user_id=%d", user.ID)
+		a.auditRecorder.Record(ctx, domain.AuditEvent{
+			Type:   auditCommon.EventLoginFailure,
+			Detail: "reauthentication failed",
+		})
+		return a.recordLoginFailure(user.Email, clientIP)
+	}
+
+	return nil
+}
+
+// HandleSendPasswordResetEmail issues a reset token and sends the reset link to the user, unknown emails get
+// nothing. Tokens issued before stop working.
+func (a *AuthUseCase) HandleSendPasswordResetEmail(ctx context.Context, task *asynq.Task) error {
+	var p common.PasswordResetPayload
+	if err := json.Unmarshal(task.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	user, err := a.userRepo.FindUserByEmail(ctx, p.Email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("password reset requested for unknown email")
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("find user err: %+v", err)
+	}
+
+	token, err := a.issuePasswordResetToken(user.ID)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/password/reset?token=%s", a.config.ClientURL, token)
+	body := fmt.Sprintf("We received a request to reset your password.\n\n"+
+		"Open the following link within %s to choose a new one:\n%s\n\n"+
+		"If you didn't request it, you can ignore this email.", common.PasswordResetTokenLifetime, link)
+
+	return a.mailer.Send(user.Email, "Reset your password", body)
+}
+
+// issue a reset token for the user, replacing the previous one as the only token of the user
+func (a *AuthUseCase) issuePasswordResetToken(userID int64) (token string, err error) {
+	token, err = general.GenerateRandomToken(32)
+	if err != nil {
+		return "", fmt.Errorf("generate reset token err: %+v", err)
+	}
+
+	tokenHash := general.HashToken(token)
+	lifetime := int(common.PasswordResetTokenLifetime.Seconds())
+
+	// only the hash is stored, a leaked cache doesn't leak usable tokens
+	if err = a.redis.Set(passwordResetCacheKey(tokenHash), userID, lifetime); err != nil {
+		return "", fmt.Errorf("store reset token err: %+v", err)
+	}
+
+	if err = a.redis.Set(passwordResetUserCacheKey(userID), tokenHash, lifetime); err != nil {
+		return "", fmt.Errorf("store reset token err: %+v", err)
+	}
+
+	return token, nil
+}
+
+func passwordResetCacheKey(tokenHash string) string {
+	return fmt.Sprintf("password-reset:%s", tokenHash)
+}
+
+func passwordResetUserCacheKey(userID int64) string {
+	return fmt.Sprintf("password-reset-user:%d", userID)
+}
+
+func passwordResetThrottleCacheKey(email string) string {
+	return fmt.Sprintf("password-reset-throttle:%s", email)
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/password"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/mail"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/queue"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"strings"
+	"testing"
+)
+
+func TestAuthUseCase_ForgotPassword(t *testing.T) {
+	testCases := []struct {
+		name      string
+		throttled bool
+		err       error
+	}{
+		{name: "queued"},
+		{name: "throttled", throttled: true, err: common.ErrTooManyRequests},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			redisMock := redis.NewMockInterface(ctrl)
+			client := queue.NewMockInterface(ctrl)
+
+			redisMock.EXPECT().SetNX(passwordResetThrottleCacheKey("user@mail.com"), "1",
+				int(common.PasswordResetResendInterval.Seconds())).Return(!tc.throttled, nil)
+
+			// registered or not, the email is only looked up by the task
+			if !tc.throttled {
+				client.EXPECT().EnqueueTaskContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, task *asynq.Task) (*asynq.TaskInfo, error) {
+						assert.Equal(t, common.TypePasswordResetEmail, task.Type())
+						assert.JSONEq(t, `{"email":" User@mail.com"}`, string(task.Payload()))
+						return &asynq.TaskInfo{}, nil
+					})
+			}
+
+			a := &AuthUseCase{redis: redisMock, client: client}
+
+			err := a.ForgotPassword(context.Background(), " User@mail.com")
+			assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+		})
+	}
+}
+
+func TestAuthUseCase_HandleSendPasswordResetEmail(t *testing.T) {
+	payload, _ := json.Marshal(common.PasswordResetPayload{Email: "user@mail.com"})
+	task := asynq.NewTask(common.TypePasswordResetEmail, payload)
+
+	t.Run("unknown email", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		userRepo := domain.NewMockUserRepository(ctrl)
+
+		userRepo.EXPECT().FindUserByEmail(gomock.Any(), "user@mail.com").Return(domain.User{}, gorm.ErrRecordNotFound)
+
+		a := &AuthUseCase{userRepo: userRepo}
+
+		assert.NoError(t, a.HandleSendPasswordResetEmail(context.Background(), task))
+	})
+
+	t.Run("replaces the token of the user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		userRepo := domain.NewMockUserRepository(ctrl)
+		redisMock := redis.NewMockInterface(ctrl)
+		mailer := mail.NewMockInterface(ctrl)
+
+		lifetime := int(common.PasswordResetTokenLifetime.Seconds())
+		var tokenHash string
+
+		userRepo.EXPECT().FindUserByEmail(gomock.Any(), "user@mail.com").
+			Return(domain.User{ID: 1, Email: "user@mail.com"}, nil)
+		redisMock.EXPECT().Set(gomock.Any(), int64(1), lifetime).
+			DoAndReturn(func(key string, value interface{}, expireSeconds int) error {
+				tokenHash = strings.TrimPrefix(key, "password-reset:")
+				return nil
+			})
+		redisMock.EXPECT().Set(passwordResetUserCacheKey(1), gomock.Any(), lifetime).
+			DoAndReturn(func(key string, value interface{}, expireSeconds int) error {
+				assert.Equal(t, tokenHash, value)
+				return nil
+			})
+		mailer.EXPECT().Send("user@mail.com", "Reset your password", gomock.Any()).
+			DoAndReturn(func(to, subject, body string) error {
+				token := body[strings.Index(body, "token=")+len("token=") : strings.Index(body, "\n\nIf")]
+				assert.Equal(t, tokenHash, general.HashToken(token))
+				return nil
+			})
+
+		a := &AuthUseCase{userRepo: userRepo, redis: redisMock, mailer: mailer}
+
+		assert.NoError(t, a.HandleSendPasswordResetEmail(context.Background(), task))
+	})
+}
+
+func TestAuthUseCase_ResetPassword(t *testing.T) {
+	tokenHash := general.HashToken("token")
+
+	testCases := []struct {
+		name         string
+		userID       interface{}
+		currentToken interface{} // hash of the latest token of the user, nil when there's none
+		err          error
+	}{
+		{name: "reset", userID: "1", currentToken: tokenHash},
+		{name: "unknown token", err: common.ErrResetTokenInvalid},
+		{name: "replaced by a later token", userID: "1", currentToken: general.HashToken("later"),
+			err: common.ErrResetTokenInvalid},
+		{name: "already used", userID: "1", err: common.ErrResetTokenInvalid},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			redisMock := redis.NewMockInterface(ctrl)
+			userRepo := domain.NewMockUserRepository(ctrl)
+			sessionRepo := domain.NewMockSessionRepository(ctrl)
+			hasher := password.NewMockInterface(ctrl)
+			policy := password.NewMockValidator(ctrl)
+			auditRecorder := domain.NewMockAuditRecorder(ctrl)
+
+			if tc.userID == nil {
+				redisMock.EXPECT().Get(passwordResetCacheKey(tokenHash)).Return(nil, redis.ErrNilReturned)
+			} else {
+				redisMock.EXPECT().Get(passwordResetCacheKey(tokenHash)).Return(tc.userID, nil)
+
+				if tc.currentToken == nil {
+					redisMock.EXPECT().Get(passwordResetUserCacheKey(1)).Return(nil, redis.ErrNilReturned)
+				} else {
+					redisMock.EXPECT().Get(passwordResetUserCacheKey(1)).Return(tc.currentToken, nil)
+				}
+			}
+
+			if tc.err == nil {
+				userRepo.EXPECT().FindUserByID(gomock.Any(), int64(1)).
+					Return(domain.User{ID: 1, Username: "user", Email: "user@mail.com"}, nil)
+				policy.EXPECT().Validate("new-password", "user", "user@mail.com")
+
+				// the token and the user's pointer to it are both gone
+				redisMock.EXPECT().GetDel(passwordResetCacheKey(tokenHash)).Return("1", nil)
+				redisMock.EXPECT().Del(passwordResetUserCacheKey(1))
+
+				hasher.EXPECT().Hash("new-password").Return("hash", nil)
+				userRepo.EXPECT().UpdateUserPassword(gomock.Any(), int64(1), "hash")
+				sessionRepo.EXPECT().FindSessionsByUserID(int64(1))
+				auditRecorder.EXPECT().Record(gomock.Any(), domain.AuditEvent{Type: auditCommon.EventPasswordChange,
+					UserID: 1, Detail: "password reset, every session revoked"})
+			}
+
+			a := &AuthUseCase{
+				userRepo:       userRepo,
+				sessionRepo:    sessionRepo,
+				passwordHasher: hasher,
+				passwordPolicy: policy,
+				redis:          redisMock,
+				auditRecorder:  auditRecorder,
+			}
+
+			err := a.ResetPassword(context.Background(), "token", "new-password")
+			assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+		})
+	}
+}
@@ -13,6 +13,10 @@ import (
 func (a *AuthUseCase) RegisterQueue(as *queue.AsynqServer) {
 	as.AddHandlerFunc(common.TypeWelcomeEmail, a.HandleSendEmail)
 	as.AddHandlerFunc(common.TypeReminderEmail, a.HandleSendEmail)
+	as.AddHandlerFunc(common.TypePasswordResetEmail, a.HandleSendPasswordResetEmail)
+	as.AddHandlerFunc(common.TypeVerificationEmail, a.HandleSendVerificationEmail)
+	as.AddHandlerFunc(common.TypeMagicLinkEmail, a.HandleSendMagicLinkEmail)
+	as.AddHandlerFunc(common.TypeNewSignInEmail, a.HandleSendNewSignInEmail)
 }
 
 // HandleSendEmail is a handler function that sends an email to the user.
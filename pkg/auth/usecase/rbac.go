@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	rbacCommon "github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	httputil "github.com/lactobasilusprotectus/go-template/pkg/util/http"
+	"strings"
+)
+
+// RequireRole only lets the request through if the user has one of the given roles, must be used after MustLogin.
+// Api keys restricted by scopes only hold the roles whose permissions their scopes cover.
+func (a *AuthUseCase) RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		userID, ok := general.GetUserIDFromCtx(ctx)
+		if !ok {
+			httputil.WriteUnauthenticatedResponse(c)
+			c.Abort()
+			return
+		}
+
+		// api keys may be restricted to a subset of the user permissions, only roles they cover count then
+		scopes, _ := general.GetScopesFromCtx(ctx)
+
+		// prefer roles embedded in the token over a database lookup
+		granted, ok := general.GetRolesFromCtx(ctx)
+		if !ok || len(scopes) > 0 {
+			var err error
+			granted, err = a.userRoleNames(userID, scopes)
+			if err != nil {
+				httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+				c.Abort()
+				return
+			}
+		}
+
+		for _, required := range roles {
+			for _, role := range granted {
+				if role == required {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		a.recordPermissionDenied(c, fmt.Sprintf("role required: %s", strings.Join(roles, ", ")))
+		httputil.WriteForbiddenResponseWithErrMsg(c, rbacCommon.ErrPermissionDenied)
+		c.Abort()
+	}
+}
+
+// RequirePermission only lets the request through if the user holds all the given permissions, must be used after
+// MustLogin
+func (a *AuthUseCase) RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := general.GetUserIDFromCtx(c.Request.Context())
+		if !ok {
+			httputil.WriteUnauthenticatedResponse(c)
+			c.Abort()
+			return
+		}
+
+		granted, err := a.rbacRepo.FindPermissionsByUserID(userID)
+		if err != nil {
+			httputil.WriteServerErrorResponse(c, httputil.ResponseServerError,
+				fmt.Errorf("find permissions err: %+v", err))
+			c.Abort()
+			return
+		}
+
+		// api keys may be restricted to a subset of the user permissions
+		scopes, _ := general.GetScopesFromCtx(c.Request.Context())
+
+		for _, required := range permissions {
+			if !rbacCommon.PermissionGranted(granted, required) ||
+				(len(scopes) > 0 && !rbacCommon.PermissionGranted(scopes, required)) {
+				a.recordPermissionDenied(c, fmt.Sprintf("permission required: %s", required))
+				httputil.WriteForbiddenResponseWithErrMsg(c, rbacCommon.ErrPermissionDenied)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// record the denied request, along with what it lacked
+func (a *AuthUseCase) recordPermissionDenied(c *gin.Context, detail string) {
+	a.auditRecorder.Record(c.Request.Context(), domain.AuditEvent{
+		Type:   auditCommon.EventPermissionDenied,
+		Detail: fmt.Sprintf("%s %s, %s", c.Request.Method, c.FullPath(), detail),
+	})
+}
+
+// list the role names of the user, leaving out roles with permissions the given scopes don't grant when any
+func (a *AuthUseCase) userRoleNames(userID int64, scopes []string) (names []string, err error) {
+	roles, err := a.rbacRepo.FindRolesByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("find roles err: %+v", err)
+	}
+
+	names = make([]string, 0, len(roles))
+	for _, role := range roles {
+		if len(scopes) == 0 || roleCoveredByScopes(role, scopes) {
+			names = append(names, role.Name)
+		}
+	}
+
+	return names, nil
+}
+
+// grant the built-in user role to a new user
+func (a *AuthUseCase) assignDefaultRole(userID int64) (err error) {
+	role, err := a.rbacRepo.FindRoleByName(rbacCommon.RoleUser)
+	if err != nil {
+		return fmt.Errorf("find role %s err: %+v", rbacCommon.RoleUser, err)
+	}
+
+	if err = a.rbacRepo.AssignUserRole(userID, role.ID); err != nil {
+		return fmt.Errorf("assign role %s err: %+v", rbacCommon.RoleUser, err)
+	}
+
+	return nil
+}
+
+func roleCoveredByScopes(role domain.Role, scopes []string) bool {
+	for _, permission := range role.Permissions {
+		if !rbacCommon.PermissionGranted(scopes, permission.Name) {
+			return false
+		}
+	}
+
+	return true
+}
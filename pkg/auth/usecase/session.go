@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"sort"
+)
+
+// ListSessions returns every active session of the current user, most recently seen first.
+func (a *AuthUseCase) ListSessions(ctx context.Context) (sessions []common.SessionInfo, err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = common.ErrAuthUnauthenticated
+		return
+	}
+
+	currentSessionID, _ := general.GetSessionIDFromCtx(ctx)
+
+	userSessions, err := a.sessionRepo.FindSessionsByUserID(userID)
+	if err != nil {
+		err = fmt.Errorf("find sessions err: %+v", err)
+		return
+	}
+
+	sessions = make([]common.SessionInfo, 0, len(userSessions))
+	for _, session := range userSessions {
+		sessions = append(sessions, common.SessionInfo{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			ClientIP:   session.ClientIP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.ID == currentSessionID,
+		})
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return
+}
+
+// RevokeSession revokes one of the sessions owned by the current user.
+func (a *AuthUseCase) RevokeSession(ctx context.Context, sessionID string) (err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		return common.ErrAuthUnauthenticated
+	}
+
+	if err = rejectApiKey(ctx); err != nil {
+		return
+	}
+
+	session, err := a.sessionRepo.FindSessionByID(sessionID)
+	if errors.Is(err, common.ErrSessionNotFound) {
+		return common.ErrSessionNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("find session err: %+v", err)
+	}
+
+	// don't leak the existence of other users' sessions
+	if session.UserID != userID {
+		return common.ErrSessionNotFound
+	}
+
+	if err = a.revokeSession(sessionID); err != nil {
+		return
+	}
+
+	if err = a.sessionRepo.DeleteSession(sessionID); err != nil {
+		return fmt.Errorf("delete session err: %+v", err)
+	}
+
+	a.auditRecorder.Record(ctx, domain.AuditEvent{
+		Type:   auditCommon.EventLogout,
+		Detail: fmt.Sprintf("session revoked: session_id=%s", sessionID),
+	})
+
+	return nil
+}
+
+// revoke and unregister every session of the given user
+func (a *AuthUseCase) revokeUserSessions(userID int64) (count int, err error) {
+	sessions, err := a.sessionRepo.FindSessionsByUserID(userID)
+	if err != nil {
+		err = fmt.Errorf("find sessions err: %+v", err)
+		return
+	}
+
+	for _, session := range sessions {
+		if err = a.revokeSession(session.ID); err != nil {
+			return
+		}
+
+		err = a.sessionRepo.DeleteSession(session.ID)
+		if err != nil && !errors.Is(err, common.ErrSessionNotFound) {
+			err = fmt.Errorf("delete session err: %+v", err)
+			return
+		}
+	}
+
+	return len(sessions), nil
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAuthUseCase_ListSessions(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx := general.SetSessionIDIntoCtx(general.SetUserIDIntoCtx(context.Background(), 1), "current")
+
+	ctrl := gomock.NewController(t)
+	sessionRepo := domain.NewMockSessionRepository(ctrl)
+
+	sessionRepo.EXPECT().FindSessionsByUserID(int64(1)).Return([]domain.Session{
+		{ID: "current", UserID: 1, UserAgent: "browser", LastSeenAt: now.Add(-time.Hour)},
+		{ID: "other", UserID: 1, UserAgent: "phone", LastSeenAt: now},
+	}, nil)
+
+	a := &AuthUseCase{sessionRepo: sessionRepo}
+
+	sessions, err := a.ListSessions(ctx)
+	assert.NoError(t, err)
+
+	// most recently seen first
+	assert.Equal(t, []common.SessionInfo{
+		{ID: "other", UserAgent: "phone", LastSeenAt: now},
+		{ID: "current", UserAgent: "browser", LastSeenAt: now.Add(-time.Hour), Current: true},
+	}, sessions)
+}
+
+func TestAuthUseCase_RevokeSession(t *testing.T) {
+	ctx := general.SetSessionIDIntoCtx(general.SetUserIDIntoCtx(context.Background(), 1), "current")
+
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		session *domain.Session // nil when there is no such session
+		revoked bool
+		err     error
+	}{
+		{name: "revoked", ctx: ctx, session: &domain.Session{ID: "other", UserID: 1}, revoked: true},
+		{name: "session of another user", ctx: ctx, session: &domain.Session{ID: "other", UserID: 2},
+			err: common.ErrSessionNotFound},
+		{name: "unknown session", ctx: ctx, err: common.ErrSessionNotFound},
+		{name: "api key", ctx: general.SetApiKeyIntoCtx(ctx, 1, nil), err: common.ErrApiKeyNotAllowed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			redisMock := redis.NewMockInterface(ctrl)
+			sessionRepo := domain.NewMockSessionRepository(ctrl)
+			auditRecorder := domain.NewMockAuditRecorder(ctrl)
+
+			if tc.session != nil {
+				sessionRepo.EXPECT().FindSessionByID("other").Return(*tc.session, nil)
+			} else if !errors.Is(tc.err, common.ErrApiKeyNotAllowed) {
+				sessionRepo.EXPECT().FindSessionByID("other").Return(domain.Session{}, common.ErrSessionNotFound)
+			}
+
+			if tc.revoked {
+				redisMock.EXPECT().TTL(refreshTokenCacheKey("other")).Return(3600, nil)
+				redisMock.EXPECT().Set(invalidSessionCacheKey("other"), common.SessionInvalidated, 3600)
+				sessionRepo.EXPECT().DeleteSession("other")
+				auditRecorder.EXPECT().Record(gomock.Any(), domain.AuditEvent{Type: auditCommon.EventLogout,
+					Detail: "session revoked: session_id=other"})
+			}
+
+			a := &AuthUseCase{sessionRepo: sessionRepo, redis: redisMock, auditRecorder: auditRecorder}
+
+			err := a.RevokeSession(tc.ctx, "other")
+			assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+		})
+	}
+}
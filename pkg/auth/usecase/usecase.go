@@ -2,44 +2,116 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
 	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
 	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
 	"github.com/lactobasilusprotectus/go-template/pkg/common/password"
 	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
 	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	invitationCommon "github.com/lactobasilusprotectus/go-template/pkg/invitation/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/geoip"
 	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/mail"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/oidc"
 	"github.com/lactobasilusprotectus/go-template/pkg/util/queue"
 	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"gorm.io/gorm"
+	"log"
 	"time"
 )
 
 type AuthUseCase struct {
-	userRepo  domain.UserRepository
-	jwtModule jwt.JwtInterface
-	redis     redis.Interface
-	time      commonTime.TimeInterface
-	config    config.Config
-	client    queue.Interface
+	userRepo         domain.UserRepository
+	sessionRepo      domain.SessionRepository
+	recoveryCodeRepo domain.RecoveryCodeRepository
+	rbacRepo         domain.RbacRepository
+	apiKeyRepo       domain.ApiKeyRepository
+	identityRepo     domain.UserIdentityRepository
+	organizationRepo domain.OrganizationRepository
+	deviceRepo       domain.DeviceRepository
+	invitationRepo   domain.InvitationRepository
+	oidcProviders    map[string]oidc.Interface
+	jwtModule        jwt.JwtInterface
+	passwordHasher   password.Interface
+	passwordPolicy   password.Validator
+	redis            redis.Interface
+	time             commonTime.TimeInterface
+	config           config.Config
+	client           queue.Interface
+	mailer           mail.Interface
+	auditRecorder    domain.AuditRecorder
+	geoIP            geoip.Interface
+
+	// compared against when the email is unknown, hashed like real passwords so it takes as long
+	dummyPasswordHash string
 }
 
-func NewAuthUseCase(userRepo domain.UserRepository,
-	jwtModule jwt.JwtInterface, redis redis.Interface, time commonTime.TimeInterface,
-	config config.Config, client queue.Interface) *AuthUseCase {
+func NewAuthUseCase(userRepo domain.UserRepository, sessionRepo domain.SessionRepository,
+	recoveryCodeRepo domain.RecoveryCodeRepository, rbacRepo domain.RbacRepository, apiKeyRepo domain.ApiKeyRepository,
+	identityRepo domain.UserIdentityRepository, organizationRepo domain.OrganizationRepository,
+	deviceRepo domain.DeviceRepository, invitationRepo domain.InvitationRepository, oidcProviders []oidc.Interface,
+	jwtModule jwt.JwtInterface,
+	passwordHasher password.Interface, passwordPolicy password.Validator, redis redis.Interface,
+	time commonTime.TimeInterface, config config.Config, client queue.Interface, mailer mail.Interface,
+	auditRecorder domain.AuditRecorder, geoIP geoip.Interface) *AuthUseCase {
+	providers := make(map[string]oidc.Interface, len(oidcProviders))
+	for _, provider := range oidcProviders {
+		providers[provider.Name()] = provider
+	}
+
+	dummyPasswordHash, err := passwordHasher.Hash("dummy-password-for-timing")
+	if err != nil {
+		log.Printf("hash dummy password err: %+v", err)
+	}
+
 	return &AuthUseCase{
-		userRepo:  userRepo,
-		jwtModule: jwtModule,
-		redis:     redis,
-		time:      time,
-		config:    config,
-		client:    client,
+		userRepo:         userRepo,
+		sessionRepo:      sessionRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		rbacRepo:         rbacRepo,
+		apiKeyRepo:       apiKeyRepo,
+		identityRepo:     identityRepo,
+		organizationRepo: organizationRepo,
+		deviceRepo:       deviceRepo,
+		invitationRepo:   invitationRepo,
+		oidcProviders:    providers,
+		jwtModule:        jwtModule,
+		passwordHasher:   passwordHasher,
+		passwordPolicy:   passwordPolicy,
+		redis:            redis,
+		time:             time,
+		config:           config,
+		client:           client,
+		mailer:           mailer,
+		auditRecorder:    auditRecorder,
+		geoIP:            geoIP,
+
+		dummyPasswordHash: dummyPasswordHash,
 	}
 }
 
-func (a *AuthUseCase) Register(user domain.User) (err error) {
+// Register creates the user, invitationCode is required in the invite registration mode. Invited users get the
+// role and organization of their invitation, and their email is verified already.
+func (a *AuthUseCase) Register(ctx context.Context, user domain.User, invitationCode string) (err error) {
+	var invitation domain.Invitation
+	if invitationCode != "" {
+		if invitation, err = a.findInvitation(user.Email, invitationCode); err != nil {
+			return
+		}
+	} else if a.config.Registration.Mode == config.RegistrationModeInvite {
+		return invitationCommon.ErrInvitationRequired
+	}
+
+	if err = a.passwordPolicy.Validate(user.Password, user.Username, user.Email); err != nil {
+		return
+	}
+
 	//hash password
-	hashedPassword, err := password.HashPassword(user.Password)
+	hashedPassword, err := a.passwordHasher.Hash(user.Password)
 
 	if err != nil {
 		return fmt.Errorf("something wrong: %w", err)
@@ -48,25 +120,91 @@ func (a *AuthUseCase) Register(user domain.User) (err error) {
 	user.Password = hashedPassword
 
 	//save to database
-	return a.userRepo.InsertUser(user)
+	if invitation.ID != 0 {
+		if err = a.insertInvitedUser(&user, invitation); err != nil {
+			return
+		}
+	} else {
+		if err = a.userRepo.InsertUser(ctx, user); err != nil {
+			return err
+		}
+
+		// InsertUser doesn't return the generated ID
+		if user, err = a.userRepo.FindUserByEmail(ctx, user.Email); err != nil {
+			return fmt.Errorf("find registered user err: %+v", err)
+		}
+	}
+
+	a.auditRecorder.Record(ctx, domain.AuditEvent{Type: auditCommon.EventRegister, Email: user.Email})
+
+	if err = a.assignDefaultRole(user.ID); err != nil {
+		return
+	}
+
+	if invitation.ID != 0 {
+		return a.acceptInvitation(ctx, user, invitation)
+	}
+
+	// the user can ask for another email later, don't fail the registration
+	if err = a.sendVerificationEmail(ctx, user.Email); err != nil {
+		log.Printf("send verification email err: %+v", err)
+	}
+
+	return nil
 }
 
 func (a *AuthUseCase) Login(ctx context.Context, email, pass string) (token common.LoginToken, err error) {
+	clientIP, _ := general.GetClientIPFromCtx(ctx)
+
+	defer func() {
+		if errors.Is(err, common.ErrInvalidCredentials) || errors.Is(err, common.ErrAccountLocked) ||
+			errors.Is(err, common.ErrTooManyRequests) {
+			a.auditRecorder.Record(ctx, domain.AuditEvent{
+				Type:   auditCommon.EventLoginFailure,
+				Email:  normalizeEmail(email),
+				Detail: err.Error(),
+			})
+		}
+	}()
+
+	// reject while the account or the client is locked out or backing off
+	if err = a.checkLoginAllowed(email, clientIP); err != nil {
+		return
+	}
+
 	//get user from database
-	user, err := a.userRepo.FindUserByEmail(email)
+	user, err := a.userRepo.FindUserByEmail(ctx, email)
 
 	if err != nil {
-		return common.LoginToken{}, common.ErrEmailNotFound
+		// compare anyway, so unknown emails take as long as wrong passwords
+		_, _ = a.passwordHasher.Verify(pass, a.dummyPasswordHash)
+		return common.LoginToken{}, a.recordLoginFailure(email, clientIP)
 	}
 
 	//check password
 	if len(user.Email) > 0 {
-		if !password.CheckPasswordHash(pass, user.Password) {
-			err = common.ErrWrongPassword
+		var match bool
+		match, err = a.passwordHasher.Verify(pass, user.Password)
+		if err != nil {
+			err = fmt.Errorf("verify password err: %+v", err)
 			return
 		}
 
-		token, err = a.generateLoginToken(ctx, user.ID)
+		if !match {
+			err = a.recordLoginFailure(email, clientIP)
+			return
+		}
+
+		a.upgradePasswordHash(ctx, user, pass)
+
+		// with a second factor, failures are only forgotten once LoginMfa has checked it as well
+		if user.TotpEnabledAt == nil {
+			if err = a.resetLoginFailures(email); err != nil {
+				return
+			}
+		}
+
+		token, err = a.completeLogin(ctx, user)
 		if err != nil {
 			return
 		}
@@ -78,21 +216,324 @@ func (a *AuthUseCase) Login(ctx context.Context, email, pass string) (token comm
 	return
 }
 
+// rehash the password of an authenticated user when its hash is outdated, login goes on whatever happens
+func (a *AuthUseCase) upgradePasswordHash(ctx context.Context, user domain.User, pass string) {
+	if !a.passwordHasher.NeedsRehash(user.Password) {
+		return
+	}
+
+	hashedPassword, err := a.passwordHasher.Hash(pass)
+	if err != nil {
+		log.Printf("rehash password err: %+v", err)
+		return
+	}
+
+	// only replace the verified hash, the password might have just been changed
+	if err = a.userRepo.UpdateUserPasswordHash(ctx, user.ID, user.Password, hashedPassword); err != nil {
+		log.Printf("update password hash err: %+v", err)
+		return
+	}
+
+	log.Printf("password hash upgraded: user_id=%d", user.ID)
+}
+
+// complete login of an authenticated user, unless the email is still to be verified or a second factor is needed
+func (a *AuthUseCase) completeLogin(ctx context.Context, user domain.User) (token common.LoginToken, err error) {
+	if a.config.EmailVerificationRequired && user.EmailVerifiedAt == nil {
+		err = common.ErrEmailNotVerified
+		return
+	}
+
+	// second factor is checked by LoginMfa
+	if user.TotpEnabledAt != nil {
+		return a.generateMfaPendingToken(ctx, user.ID)
+	}
+
+	return a.generateLoginToken(ctx, user.ID)
+}
+
+// Logout revokes the session of the current request.
+func (a *AuthUseCase) Logout(ctx context.Context) (info common.LogoutInfo, err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = common.ErrAuthUnauthenticated
+		return
+	}
+
+	// requests authenticated with an api key have no session to log out of
+	if err = rejectApiKey(ctx); err != nil {
+		return
+	}
+
+	sessionID, ok := general.GetSessionIDFromCtx(ctx)
+	if !ok {
+		err = common.ErrAuthUnauthenticated
+		return
+	}
+
+	if err = a.revokeSession(sessionID); err != nil {
+		return
+	}
+
+	err = a.sessionRepo.DeleteSession(sessionID)
+	if err != nil && !errors.Is(err, common.ErrSessionNotFound) {
+		err = fmt.Errorf("delete session err: %+v", err)
+		return
+	}
+
+	log.Printf("user logged out: user_id=%d, session_id=%s", userID, sessionID)
+	a.auditRecorder.Record(ctx, domain.AuditEvent{Type: auditCommon.EventLogout})
+	info.Message = "Logout success"
+	err = nil
+	return
+}
+
+// LogoutAll revokes every session of the current user.
+func (a *AuthUseCase) LogoutAll(ctx context.Context) (info common.LogoutInfo, err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = common.ErrAuthUnauthenticated
+		return
+	}
+
+	if err = rejectApiKey(ctx); err != nil {
+		return
+	}
+
+	if err = rejectImpersonation(ctx); err != nil {
+		return
+	}
+
+	count, err := a.revokeUserSessions(userID)
+	if err != nil {
+		return
+	}
+
+	a.auditRecorder.Record(ctx, domain.AuditEvent{
+		Type:   auditCommon.EventLogout,
+		Detail: fmt.Sprintf("every session logged out: count=%d", count),
+	})
+
+	info.Message = fmt.Sprintf("%d sessions logged out", count)
+	return
+}
+
+// Info returns the profile of the current user along with its session information.
 func (a *AuthUseCase) Info(ctx context.Context) (info common.LoginInfo, err error) {
-	//TODO implement me
-	panic("implement me")
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = common.ErrAuthUnauthenticated
+		return
+	}
+
+	// requests authenticated with an api key have no session
+	sessionID, _ := general.GetSessionIDFromCtx(ctx)
+	expiresAt, _ := general.GetTokenExpiryFromCtx(ctx)
+	tenantID, _ := general.GetTenantIDFromCtx(ctx)
+	actorID, impersonated := general.GetActorIDFromCtx(ctx)
+
+	user, err := a.userRepo.FindUserByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = common.ErrUserNotFound
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("find user err: %+v", err)
+		return
+	}
+
+	info = common.LoginInfo{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Age:       user.Age,
+		SessionID: sessionID,
+		ExpiresAt: expiresAt,
+
+		OrganizationID: tenantID,
+
+		Impersonated:   impersonated,
+		ImpersonatorID: actorID,
+	}
+	return
+}
+
+// Refresh exchanges a refresh token for a new token pair within the same session.
+// Every refresh token can only be used once, replaying a rotated one revokes the whole session.
+// The session is extended up to SessionLifetime after the login, the user has to log in again then.
+func (a *AuthUseCase) Refresh(ctx context.Context, refreshToken string) (token common.LoginToken, err error) {
+	valid, user, jwtData, err := a.extractAndValidateToken(ctx, refreshToken, common.RefreshTokenType)
+	if err != nil {
+		return
+	}
+
+	if !valid {
+		err = common.ErrRefreshTokenInvalid
+		return
+	}
+
+	// session must still be registered
+	session, err := a.sessionRepo.FindSessionByID(jwtData.SessionID)
+	if errors.Is(err, common.ErrSessionNotFound) {
+		err = common.ErrRefreshTokenInvalid
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("find session err: %+v", err)
+		return
+	}
+
+	now := a.time.Now()
+
+	lifetime := refreshLifetime(session, now)
+	if lifetime <= 0 {
+		err = common.ErrRefreshTokenInvalid
+		return
+	}
+
+	// rotate the current refresh token of the session
+	refreshTokenID := uuid.New().String()
+
+	currentTokenID, err := a.redis.GetSet(refreshTokenCacheKey(jwtData.SessionID), refreshTokenID,
+		int(lifetime.Seconds()))
+	if err != nil && !errors.Is(err, redis.ErrNilReturned) {
+		err = fmt.Errorf("rotate refresh token err: %+v", err)
+		return
+	}
+
+	// presented token is not the current one, it has been used before
+	if currentTokenID != jwtData.TokenID {
+		if err = a.invalidateSession(jwtData.SessionID, common.RefreshTokenLifetime); err != nil {
+			return
+		}
+
+		if err = a.sessionRepo.DeleteSession(jwtData.SessionID); err != nil {
+			err = fmt.Errorf("delete session err: %+v", err)
+			return
+		}
+
+		log.Printf("refresh token reuse detected, session revoked: user_id=%d, session_id=%s",
+			user.ID, jwtData.SessionID)
+		a.auditRecorder.Record(ctx, domain.AuditEvent{
+			Type:      auditCommon.EventTokenReuse,
+			UserID:    user.ID,
+			SessionID: jwtData.SessionID,
+			Detail:    "session revoked",
+		})
+		err = common.ErrRefreshTokenReused
+		return
+	}
+
+	// the user might have left the organization of the session since
+	if session.OrganizationID, err = a.refreshOrganization(session); err != nil {
+		return
+	}
+
+	// the session lives as long as its newest refresh token
+	session.LastSeenAt = now
+	session.ExpiresAt = now.Add(lifetime)
+
+	if err = a.sessionRepo.UpdateSession(session); err != nil {
+		err = fmt.Errorf("update session err: %+v", err)
+		return
+	}
+
+	a.auditRecorder.Record(ctx, domain.AuditEvent{
+		Type:      auditCommon.EventTokenRefresh,
+		UserID:    user.ID,
+		SessionID: jwtData.SessionID,
+	})
+
+	return a.generateTokenPair(ctx, jwtData.SessionID, user.ID, session.OrganizationID, refreshTokenID, lifetime)
+}
+
+// lifetime of a new refresh token of the session, which can't outlive SessionLifetime after the login
+func refreshLifetime(session domain.Session, now time.Time) time.Duration {
+	lifetime := common.RefreshTokenLifetime
+
+	if remaining := session.CreatedAt.Add(common.SessionLifetime).Sub(now); remaining < lifetime {
+		lifetime = remaining
+	}
+
+	return lifetime
 }
 
 // generate new login token with new session ID
 func (a *AuthUseCase) generateLoginToken(ctx context.Context, userID int64) (token common.LoginToken, err error) {
 	sessionID := uuid.New().String()
+	refreshTokenID := uuid.New().String()
+
+	organizationID, err := a.defaultOrganization(userID)
+	if err != nil {
+		return
+	}
+
+	// register the refresh token as the current one of the session
+	err = a.redis.Set(refreshTokenCacheKey(sessionID), refreshTokenID, int(common.RefreshTokenLifetime.Seconds()))
+	if err != nil {
+		err = fmt.Errorf("register refresh token err: %+v", err)
+		return
+	}
+
+	// register the session, so it can be listed and revoked
+	now := a.time.Now()
+	userAgent, _ := general.GetUserAgentFromCtx(ctx)
+	clientIP, _ := general.GetClientIPFromCtx(ctx)
 
-	at, err := a.generateToken(ctx, sessionID, userID, common.AccessTokenType, common.AccessTokenLifetime)
+	err = a.sessionRepo.InsertSession(domain.Session{
+		ID:             sessionID,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		UserAgent:      userAgent,
+		ClientIP:       clientIP,
+		CreatedAt:      now,
+		LastSeenAt:     now,
+		ExpiresAt:      now.Add(common.RefreshTokenLifetime),
+	})
+	if err != nil {
+		err = fmt.Errorf("register session err: %+v", err)
+		return
+	}
+
+	a.auditRecorder.Record(ctx, domain.AuditEvent{
+		Type:      auditCommon.EventLoginSuccess,
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+
+	a.recordLogin(ctx, userID, sessionID)
+
+	return a.generateTokenPair(ctx, sessionID, userID, organizationID, refreshTokenID, common.RefreshTokenLifetime)
+}
+
+// generate access and refresh token for the given session, the access token is scoped to the given organization
+func (a *AuthUseCase) generateTokenPair(ctx context.Context, sessionID string, userID, organizationID int64,
+	refreshTokenID string, refreshLifetime time.Duration) (token common.LoginToken, err error) {
+	accessData := jwt.JwtData{
+		TokenID:    uuid.New().String(),
+		SessionID:  sessionID,
+		IdentityID: userID,
+		TenantID:   organizationID,
+		Type:       common.AccessTokenType,
+		Lifetime:   common.AccessTokenLifetime,
+	}
+
+	// roles are re-read on every refresh, so changes apply within one access token lifetime
+	if a.config.JwtEmbedRoles {
+		accessData.Roles, err = a.userRoleNames(userID, nil)
+		if err != nil {
+			return
+		}
+	}
+
+	at, err := a.jwtModule.GenerateToken(ctx, accessData)
 	if err != nil {
 		return
 	}
 
-	rt, err := a.generateToken(ctx, sessionID, userID, common.RefreshTokenType, common.RefreshTokenLifetime)
+	rt, err := a.generateToken(ctx, refreshTokenID, sessionID, userID, common.RefreshTokenType, refreshLifetime)
 	if err != nil {
 		return
 	}
@@ -103,9 +544,10 @@ func (a *AuthUseCase) generateLoginToken(ctx context.Context, userID int64) (tok
 }
 
 // generate new token
-func (a *AuthUseCase) generateToken(ctx context.Context, sessionID string, userID int64, tokenType string,
+func (a *AuthUseCase) generateToken(ctx context.Context, tokenID, sessionID string, userID int64, tokenType string,
 	lifeTime time.Duration) (token string, err error) {
 	return a.jwtModule.GenerateToken(ctx, jwt.JwtData{
+		TokenID:    tokenID,
 		SessionID:  sessionID,
 		IdentityID: userID,
 		Type:       tokenType,
@@ -128,12 +570,5 @@ func (a *AuthUseCase) SendEmail(ctx context.Context, request common.LoginRequest
 
 	fmt.Println(info)
 
-	//close connection
-	err = a.client.Close()
-
-	if err != nil {
-		return fmt.Errorf("something wrong: %w", err)
-	}
-
 	return
 }
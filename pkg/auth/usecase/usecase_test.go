@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAuthUseCase_Refresh(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tokenData := jwt.JwtData{TokenID: "rt-1", SessionID: "session", IdentityID: 1, Type: common.RefreshTokenType}
+
+	testCases := []struct {
+		name           string
+		extractErr     error
+		session        *domain.Session // nil when the session is gone
+		currentTokenID string          // registered as the current refresh token of the session
+		lifetime       time.Duration   // of the rotated refresh token
+		err            error
+	}{
+		{
+			name:           "rotated",
+			session:        &domain.Session{ID: "session", UserID: 1, CreatedAt: now.Add(-time.Hour)},
+			currentTokenID: "rt-1",
+			lifetime:       common.RefreshTokenLifetime,
+		},
+		{
+			name: "rotated until the session lifetime",
+			session: &domain.Session{ID: "session", UserID: 1,
+				CreatedAt: now.Add(time.Hour - common.SessionLifetime)},
+			currentTokenID: "rt-1",
+			lifetime:       time.Hour,
+		},
+		{
+			name:    "session lifetime over",
+			session: &domain.Session{ID: "session", UserID: 1, CreatedAt: now.Add(-common.SessionLifetime)},
+			err:     common.ErrRefreshTokenInvalid,
+		},
+		{
+			name:           "reused",
+			session:        &domain.Session{ID: "session", UserID: 1, CreatedAt: now.Add(-time.Hour)},
+			currentTokenID: "rt-2",
+			lifetime:       common.RefreshTokenLifetime,
+			err:            common.ErrRefreshTokenReused,
+		},
+		{name: "expired token", extractErr: jwt.ErrTokenExpired, err: common.ErrRefreshTokenInvalid},
+		{name: "session gone", err: common.ErrRefreshTokenInvalid},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			jwtModule := jwt.NewMockJwtInterface(ctrl)
+			redisMock := redis.NewMockInterface(ctrl)
+			userRepo := domain.NewMockUserRepository(ctrl)
+			sessionRepo := domain.NewMockSessionRepository(ctrl)
+			organizationRepo := domain.NewMockOrganizationRepository(ctrl)
+			auditRecorder := domain.NewMockAuditRecorder(ctrl)
+			timeMock := commonTime.NewMockTimeInterface(ctrl)
+
+			timeMock.EXPECT().Now().Return(now).AnyTimes()
+
+			jwtModule.EXPECT().ExtractToken(gomock.Any(), "refresh", common.RefreshTokenType).
+				Return(tokenData, tc.extractErr)
+
+			if tc.extractErr == nil {
+				redisMock.EXPECT().Get(invalidSessionCacheKey("session")).Return(nil, redis.ErrNilReturned)
+				userRepo.EXPECT().FindUserByID(gomock.Any(), int64(1)).Return(domain.User{ID: 1}, nil)
+
+				if tc.session == nil {
+					sessionRepo.EXPECT().FindSessionByID("session").Return(domain.Session{}, common.ErrSessionNotFound)
+				} else {
+					sessionRepo.EXPECT().FindSessionByID("session").Return(*tc.session, nil)
+				}
+			}
+
+			var rotatedTokenID string
+			if tc.currentTokenID != "" {
+				redisMock.EXPECT().GetSet(refreshTokenCacheKey("session"), gomock.Any(), int(tc.lifetime.Seconds())).
+					DoAndReturn(func(key string, value interface{}, expireSeconds int) (interface{}, error) {
+						rotatedTokenID = value.(string)
+						return tc.currentTokenID, nil
+					})
+			}
+
+			switch {
+			case errors.Is(tc.err, common.ErrRefreshTokenReused):
+				redisMock.EXPECT().Set(invalidSessionCacheKey("session"), common.SessionInvalidated,
+					int(common.RefreshTokenLifetime.Seconds()))
+				sessionRepo.EXPECT().DeleteSession("session")
+				auditRecorder.EXPECT().Record(gomock.Any(), domain.AuditEvent{Type: auditCommon.EventTokenReuse,
+					UserID: 1, SessionID: "session", Detail: "session revoked"})
+			case tc.err == nil:
+				organizationRepo.EXPECT().FindMembershipsByUserID(int64(1))
+
+				// the session lives as long as its new refresh token
+				session := *tc.session
+				session.LastSeenAt = now
+				session.ExpiresAt = now.Add(tc.lifetime)
+				sessionRepo.EXPECT().UpdateSession(session)
+
+				auditRecorder.EXPECT().Record(gomock.Any(), domain.AuditEvent{Type: auditCommon.EventTokenRefresh,
+					UserID: 1, SessionID: "session"})
+
+				jwtModule.EXPECT().GenerateToken(gomock.Any(), gomock.Any()).Times(2).
+					DoAndReturn(func(ctx context.Context, data jwt.JwtData) (string, error) {
+						if data.Type == common.RefreshTokenType {
+							assert.Equal(t, rotatedTokenID, data.TokenID)
+							assert.Equal(t, tc.lifetime, data.Lifetime)
+						}
+						return data.Type, nil
+					})
+			}
+
+			a := &AuthUseCase{
+				userRepo:         userRepo,
+				sessionRepo:      sessionRepo,
+				organizationRepo: organizationRepo,
+				jwtModule:        jwtModule,
+				redis:            redisMock,
+				time:             timeMock,
+				auditRecorder:    auditRecorder,
+			}
+
+			token, err := a.Refresh(context.Background(), "refresh")
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, common.AccessTokenType, token.AccessToken)
+			assert.Equal(t, common.RefreshTokenType, token.RefreshToken)
+			assert.NotEqual(t, "rt-1", rotatedTokenID)
+		})
+	}
+}
+
+func TestAuthUseCase_Logout(t *testing.T) {
+	ctx := general.SetSessionIDIntoCtx(general.SetUserIDIntoCtx(context.Background(), 1), "session")
+
+	testCases := []struct {
+		name       string
+		ctx        context.Context
+		refreshTTL int // seconds the refresh token of the session has left
+		markerTTL  int // seconds the invalidation marker is kept
+		err        error
+	}{
+		{name: "logged out", ctx: ctx, refreshTTL: 3600, markerTTL: 3600},
+		{name: "marker outlives access tokens", ctx: ctx, refreshTTL: 10,
+			markerTTL: int(common.ImpersonationTokenLifetime.Seconds())},
+		{name: "api key", ctx: general.SetApiKeyIntoCtx(ctx, 1, nil), err: common.ErrApiKeyNotAllowed},
+		{name: "unauthenticated", ctx: context.Background(), err: common.ErrAuthUnauthenticated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			redisMock := redis.NewMockInterface(ctrl)
+			sessionRepo := domain.NewMockSessionRepository(ctrl)
+			auditRecorder := domain.NewMockAuditRecorder(ctrl)
+
+			if tc.err == nil {
+				redisMock.EXPECT().TTL(refreshTokenCacheKey("session")).Return(tc.refreshTTL, nil)
+				redisMock.EXPECT().Set(invalidSessionCacheKey("session"), common.SessionInvalidated, tc.markerTTL)
+				sessionRepo.EXPECT().DeleteSession("session")
+				auditRecorder.EXPECT().Record(gomock.Any(), domain.AuditEvent{Type: auditCommon.EventLogout})
+			}
+
+			a := &AuthUseCase{sessionRepo: sessionRepo, redis: redisMock, auditRecorder: auditRecorder}
+
+			_, err := a.Logout(tc.ctx)
+			assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+		})
+	}
+}
+
+func TestAuthUseCase_LogoutAll(t *testing.T) {
+	ctx := general.SetSessionIDIntoCtx(general.SetUserIDIntoCtx(context.Background(), 1), "session")
+
+	ctrl := gomock.NewController(t)
+	redisMock := redis.NewMockInterface(ctrl)
+	sessionRepo := domain.NewMockSessionRepository(ctrl)
+	auditRecorder := domain.NewMockAuditRecorder(ctrl)
+
+	sessionRepo.EXPECT().FindSessionsByUserID(int64(1)).
+		Return([]domain.Session{{ID: "session", UserID: 1}, {ID: "other", UserID: 1}}, nil)
+
+	// a session deleted meanwhile is logged out all the same
+	for _, id := range []string{"session", "other"} {
+		redisMock.EXPECT().TTL(refreshTokenCacheKey(id)).Return(3600, nil)
+		redisMock.EXPECT().Set(invalidSessionCacheKey(id), common.SessionInvalidated, 3600)
+	}
+	sessionRepo.EXPECT().DeleteSession("session")
+	sessionRepo.EXPECT().DeleteSession("other").Return(common.ErrSessionNotFound)
+
+	auditRecorder.EXPECT().Record(gomock.Any(), domain.AuditEvent{Type: auditCommon.EventLogout,
+		Detail: "every session logged out: count=2"})
+
+	a := &AuthUseCase{sessionRepo: sessionRepo, redis: redisMock, auditRecorder: auditRecorder}
+
+	info, err := a.LogoutAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "2 sessions logged out", info.Message)
+
+	// impersonating admins can't end the sessions of the user
+	_, err = a.LogoutAll(general.SetActorIDIntoCtx(ctx, 9))
+	assert.True(t, errors.Is(err, common.ErrImpersonationNotAllowed))
+}
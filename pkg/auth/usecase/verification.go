@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hibiken/asynq"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"log"
+)
+
+// VerifyEmail consumes the verification token and marks the email of its user as verified.
+func (a *AuthUseCase) VerifyEmail(ctx context.Context, token string) (err error) {
+	// token can only be consumed once
+	reply, err := a.redis.GetDel(emailVerificationCacheKey(general.HashToken(token)))
+	if errors.Is(err, redis.ErrNilReturned) {
+		return common.ErrVerifyTokenInvalid
+	}
+
+	if err != nil {
+		return fmt.Errorf("consume verification token err: %+v", err)
+	}
+
+	user, err := a.userRepo.FindUserByEmail(ctx, fmt.Sprint(reply))
+	if err != nil {
+		return common.ErrVerifyTokenInvalid
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	if err = a.userRepo.UpdateUserEmailVerifiedAt(ctx, user.ID, a.time.Now()); err != nil {
+		return fmt.Errorf("update email verified at err: %+v", err)
+	}
+
+	log.Printf("email verified: user_id=%d", user.ID)
+	return nil
+}
+
+// ResendVerificationEmail sends another verification email, at most once per EmailVerificationResendInterval.
+// Like ForgotPassword, it doesn't reveal whether the email is registered.
+func (a *AuthUseCase) ResendVerificationEmail(ctx context.Context, email string) (err error) {
+	ok, err := a.redis.SetNX(emailVerificationThrottleCacheKey(email), "1",
+		int(common.EmailVerificationResendInterval.Seconds()))
+	if err != nil {
+		return fmt.Errorf("throttle verification email err: %+v", err)
+	}
+
+	if !ok {
+		return common.ErrTooManyRequests
+	}
+
+	user, err := a.userRepo.FindUserByEmail(ctx, email)
+	if err != nil || user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	return a.sendVerificationEmail(ctx, user.Email)
+}
+
+// HandleSendVerificationEmail sends the verification link to the user.
+func (a *AuthUseCase) HandleSendVerificationEmail(ctx context.Context, task *asynq.Task) error {
+	var p common.EmailVerificationPayload
+	if err := json.Unmarshal(task.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", a.config.ClientURL, p.Token)
+	body := fmt.Sprintf("Welcome!\n\nPlease confirm your email address by opening the following link within %s:\n%s",
+		common.EmailVerificationTokenLifetime, link)
+
+	return a.mailer.Send(p.Email, "Verify your email", body)
+}
+
+// generate verification token and enqueue the email containing it
+func (a *AuthUseCase) sendVerificationEmail(ctx context.Context, email string) (err error) {
+	token, err := general.GenerateRandomToken(32)
+	if err != nil {
+		return fmt.Errorf("generate verification token err: %+v", err)
+	}
+
+	err = a.redis.Set(emailVerificationCacheKey(general.HashToken(token)), email,
+		int(common.EmailVerificationTokenLifetime.Seconds()))
+	if err != nil {
+		return fmt.Errorf("store verification token err: %+v", err)
+	}
+
+	payload, err := json.Marshal(common.EmailVerificationPayload{
+		Email: email,
+		Token: token,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = a.client.EnqueueTaskContext(ctx, asynq.NewTask(common.TypeVerificationEmail, payload))
+	if err != nil {
+		return fmt.Errorf("enqueue verification email err: %+v", err)
+	}
+
+	return nil
+}
+
+func emailVerificationCacheKey(tokenHash string) string {
+	return fmt.Sprintf("email-verification:%s", tokenHash)
+}
+
+func emailVerificationThrottleCacheKey(email string) string {
+	return fmt.Sprintf("email-verification-throttle:%s", email)
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/queue"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestAuthUseCase_VerifyEmail(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		consumed bool // token used up already
+		user     domain.User
+		verified bool // the email gets verified
+		err      error
+	}{
+		{name: "verified", user: domain.User{ID: 1, Email: "user@mail.com"}, verified: true},
+		{name: "verified before", user: domain.User{ID: 1, Email: "user@mail.com", EmailVerifiedAt: &now}},
+		{name: "token used", consumed: true, err: common.ErrVerifyTokenInvalid},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			redisMock := redis.NewMockInterface(ctrl)
+			userRepo := domain.NewMockUserRepository(ctrl)
+			timeMock := commonTime.NewMockTimeInterface(ctrl)
+
+			timeMock.EXPECT().Now().Return(now).AnyTimes()
+
+			if tc.consumed {
+				redisMock.EXPECT().GetDel(emailVerificationCacheKey(general.HashToken("token"))).
+					Return(nil, redis.ErrNilReturned)
+			} else {
+				redisMock.EXPECT().GetDel(emailVerificationCacheKey(general.HashToken("token"))).
+					Return("user@mail.com", nil)
+				userRepo.EXPECT().FindUserByEmail(gomock.Any(), "user@mail.com").Return(tc.user, nil)
+			}
+
+			if tc.verified {
+				userRepo.EXPECT().UpdateUserEmailVerifiedAt(gomock.Any(), int64(1), now)
+			}
+
+			a := &AuthUseCase{userRepo: userRepo, redis: redisMock, time: timeMock}
+
+			err := a.VerifyEmail(context.Background(), "token")
+			assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+		})
+	}
+}
+
+func TestAuthUseCase_ResendVerificationEmail(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		throttled bool
+		user      domain.User
+		findErr   error
+		sent      bool
+		err       error
+	}{
+		{name: "sent", user: domain.User{ID: 1, Email: "user@mail.com"}, sent: true},
+		{name: "throttled", throttled: true, err: common.ErrTooManyRequests},
+		{name: "unknown email", findErr: gorm.ErrRecordNotFound},
+		{name: "verified before", user: domain.User{ID: 1, Email: "user@mail.com", EmailVerifiedAt: &now}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			redisMock := redis.NewMockInterface(ctrl)
+			userRepo := domain.NewMockUserRepository(ctrl)
+			client := queue.NewMockInterface(ctrl)
+
+			redisMock.EXPECT().SetNX(emailVerificationThrottleCacheKey("user@mail.com"), "1",
+				int(common.EmailVerificationResendInterval.Seconds())).Return(!tc.throttled, nil)
+
+			if !tc.throttled {
+				userRepo.EXPECT().FindUserByEmail(gomock.Any(), "user@mail.com").Return(tc.user, tc.findErr)
+			}
+
+			// only a fresh token is sent, unknown and verified emails are answered all the same
+			if tc.sent {
+				redisMock.EXPECT().Set(gomock.Any(), "user@mail.com",
+					int(common.EmailVerificationTokenLifetime.Seconds()))
+				client.EXPECT().EnqueueTaskContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, task *asynq.Task) (*asynq.TaskInfo, error) {
+						assert.Equal(t, common.TypeVerificationEmail, task.Type())
+						return &asynq.TaskInfo{}, nil
+					})
+			}
+
+			a := &AuthUseCase{userRepo: userRepo, redis: redisMock, client: client}
+
+			err := a.ResendVerificationEmail(context.Background(), "user@mail.com")
+			assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+		})
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"github.com/joeshaw/envdecode"
 	"github.com/joho/godotenv"
 	"log"
+	"os"
+	"strings"
+	"time"
 )
 
 const (
@@ -13,6 +16,16 @@ const (
 	LOC = "local"
 )
 
+const (
+	SessionStoreRedis  = "redis"
+	SessionStoreMemory = "memory"
+)
+
+const (
+	RegistrationModeOpen   = "open"
+	RegistrationModeInvite = "invite"
+)
+
 var (
 	Global GlobalConfig
 )
@@ -22,6 +35,23 @@ type HttpConfig struct {
 	Port    string `env:"HTTP_PORT"`
 	TimeOut int    `env:"HTTP_TIMEOUT"`
 	Env     string `env:"APP_ENV"`
+
+	// comma separated origins allowed to send credentials, any origin is allowed without them when empty
+	CorsAllowedOrigins string `env:"HTTP_CORS_ALLOWED_ORIGINS"`
+
+	// comma separated IPs or CIDRs of the reverse proxies whose X-Forwarded-For is believed, none when empty
+	TrustedProxies string `env:"HTTP_TRUSTED_PROXIES"`
+
+	Cookie CookieConfig
+}
+
+// CookieConfig is the configuration of the browser session mode, where tokens are kept in HttpOnly cookies
+// instead of being handed to the client, and state-changing requests carry a double-submit CSRF token
+type CookieConfig struct {
+	Enabled  bool   `env:"AUTH_COOKIE_ENABLED,default=false"`
+	Domain   string `env:"AUTH_COOKIE_DOMAIN"`
+	Secure   bool   `env:"AUTH_COOKIE_SECURE,default=true"`
+	SameSite string `env:"AUTH_COOKIE_SAME_SITE,default=lax"`
 }
 
 // DatabaseConfig is the configuration for the database
@@ -45,26 +75,130 @@ type RedisConfig struct {
 	DB       int    `env:"REDIS_DB"`
 }
 
+// MailConfig is the configuration for the SMTP server
+type MailConfig struct {
+	Host     string `env:"MAIL_HOST"`
+	Port     string `env:"MAIL_PORT"`
+	Username string `env:"MAIL_USERNAME"`
+	Password string `env:"MAIL_PASSWORD"`
+	From     string `env:"MAIL_FROM"`
+}
+
+// OidcProviderConfig is the configuration of an external OpenID Connect identity provider.
+// Providers are listed in OIDC_PROVIDERS and each one is configured by OIDC_<NAME>_* variables.
+type OidcProviderConfig struct {
+	Name         string
+	DiscoveryURL string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// LoginProtectionConfig is the configuration for brute-force protection of login
+type LoginProtectionConfig struct {
+	MaxAccountAttempts int           `env:"LOGIN_MAX_ACCOUNT_ATTEMPTS,default=5"`
+	MaxIPAttempts      int           `env:"LOGIN_MAX_IP_ATTEMPTS,default=20"`
+	AttemptWindow      time.Duration `env:"LOGIN_ATTEMPT_WINDOW,default=15m"`
+	LockoutDuration    time.Duration `env:"LOGIN_LOCKOUT_DURATION,default=15m"`
+	BackoffBase        time.Duration `env:"LOGIN_BACKOFF_BASE,default=1s"`
+}
+
+// LoginNotificationConfig is the configuration of new device detection and sign-in notifications. Countries are
+// only resolved, and impossible travel flagged, when a MaxMind DB file such as GeoLite2-Country is given.
+type LoginNotificationConfig struct {
+	GeoIPDatabaseFile      string        `env:"LOGIN_GEOIP_DATABASE_FILE"`
+	ImpossibleTravelWindow time.Duration `env:"LOGIN_IMPOSSIBLE_TRAVEL_WINDOW,default=2h"`
+}
+
+// PasswordConfig is the configuration of password hashing and policy,
+// hashes made with other parameters are upgraded on login
+type PasswordConfig struct {
+	Algorithm         string `env:"PASSWORD_HASH_ALGORITHM,default=argon2id"`
+	BcryptCost        int    `env:"PASSWORD_BCRYPT_COST,default=12"`
+	Argon2Memory      uint32 `env:"PASSWORD_ARGON2_MEMORY,default=65536"`
+	Argon2Iterations  uint32 `env:"PASSWORD_ARGON2_ITERATIONS,default=3"`
+	Argon2Parallelism uint8  `env:"PASSWORD_ARGON2_PARALLELISM,default=2"`
+	HashConcurrency   int    `env:"PASSWORD_HASH_CONCURRENCY"` // defaults to the number of CPUs
+
+	MinLength        int    `env:"PASSWORD_MIN_LENGTH,default=8"`
+	MaxLength        int    `env:"PASSWORD_MAX_LENGTH,default=72"`
+	RequireUppercase bool   `env:"PASSWORD_REQUIRE_UPPERCASE,default=false"`
+	RequireLowercase bool   `env:"PASSWORD_REQUIRE_LOWERCASE,default=false"`
+	RequireDigit     bool   `env:"PASSWORD_REQUIRE_DIGIT,default=false"`
+	RequireSymbol    bool   `env:"PASSWORD_REQUIRE_SYMBOL,default=false"`
+	BreachedListFile string `env:"PASSWORD_BREACHED_LIST_FILE"`
+}
+
+// JwtKeyConfig is the configuration of the keys tokens are signed with
+type JwtKeyConfig struct {
+	Algorithm        string        `env:"JWT_SIGNING_ALGORITHM,default=HS256"`
+	PrivateKeyFiles  string        `env:"JWT_PRIVATE_KEY_FILES"`
+	RotationSchedule string        `env:"JWT_KEY_ROTATION_SCHEDULE"`
+	Retention        time.Duration `env:"JWT_KEY_RETENTION,default=720h"`
+}
+
+// AuditConfig is the configuration of the audit log
+type AuditConfig struct {
+	Retention         time.Duration `env:"AUDIT_RETENTION,default=2160h"`
+	RetentionSchedule string        `env:"AUDIT_RETENTION_SCHEDULE,default=@daily"`
+}
+
+// AccountConfig is the configuration of personal data export and account deletion
+type AccountConfig struct {
+	ExportLifetime      time.Duration `env:"ACCOUNT_EXPORT_LIFETIME,default=24h"`
+	DeletionGracePeriod time.Duration `env:"ACCOUNT_DELETION_GRACE_PERIOD,default=720h"`
+	PurgeSchedule       string        `env:"ACCOUNT_PURGE_SCHEDULE,default=@daily"`
+}
+
+// RegistrationConfig is the configuration of sign-up, in the invite mode only invited emails can register
+type RegistrationConfig struct {
+	Mode               string        `env:"REGISTRATION_MODE,default=open"`
+	InvitationLifetime time.Duration `env:"INVITATION_LIFETIME,default=168h"`
+}
+
 // Config is the configuration for the application
 type Config struct {
 	Http     HttpConfig
 	Database DatabaseConfig
 	Redis    RedisConfig
+	Mail     MailConfig
+
+	LoginProtection   LoginProtectionConfig
+	LoginNotification LoginNotificationConfig
+	Password          PasswordConfig
+	Audit             AuditConfig
+	Account           AccountConfig
+	Registration      RegistrationConfig
 
 	JwtSecretAccessToken  string `env:"JWT_SECRET_KEY_AT"`
 	JwtSecretRefreshToken string `env:"JWT_SECRET_KEY_RT"`
+	JwtKeys               JwtKeyConfig
+
+	SessionStore string `env:"SESSION_STORE,default=redis"`
+
+	EmailVerificationRequired bool `env:"EMAIL_VERIFICATION_REQUIRED,default=false"`
+
+	OidcProviderNames string `env:"OIDC_PROVIDERS"`
+	OidcProviders     []OidcProviderConfig
+
+	JwtEmbedRoles bool   `env:"JWT_EMBED_ROLES,default=false"`
+	AdminEmail    string `env:"RBAC_ADMIN_EMAIL"`
 
 	Title       string `env:"APP_TITLE"`
 	Description string `env:"APP_DESCRIPTION"`
 	URL         string `env:"APP_URL"`
+	ClientURL   string `env:"APP_CLIENT_URL"`
 }
 
 // GlobalConfig is the configuration for the application
 type GlobalConfig struct {
 	GlobalTimeout int
+	Env           string
 
 	JwtSecretAccessToken  string
 	JwtSecretRefreshToken string
+	JwtKeys               JwtKeyConfig
 }
 
 // GetFilePath returns the path to the config file
@@ -83,14 +217,45 @@ func Read(filepath string) (cfg Config, err error) {
 		return
 	}
 
+	cfg.OidcProviders = readOidcProviders(cfg.OidcProviderNames)
+
 	// set to global vars
 	Global.GlobalTimeout = cfg.Http.TimeOut
+	Global.Env = cfg.Http.Env
 	Global.JwtSecretAccessToken = cfg.JwtSecretAccessToken
 	Global.JwtSecretRefreshToken = cfg.JwtSecretRefreshToken
+	Global.JwtKeys = cfg.JwtKeys
 
 	return cfg, nil
 }
 
+// readOidcProviders reads configuration of each comma separated provider name from its OIDC_<NAME>_* variables
+func readOidcProviders(names string) (providers []OidcProviderConfig) {
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := fmt.Sprintf("OIDC_%s_", strings.ToUpper(name))
+		scopes := strings.Fields(os.Getenv(prefix + "SCOPES"))
+		if len(scopes) == 0 {
+			scopes = []string{"openid", "email", "profile"}
+		}
+
+		providers = append(providers, OidcProviderConfig{
+			Name:         name,
+			DiscoveryURL: os.Getenv(prefix + "DISCOVERY_URL"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       scopes,
+		})
+	}
+
+	return providers
+}
+
 // ResetGlobalConfig resets global configs to their default values.
 func ResetGlobalConfig() {
 	Global = GlobalConfig{}
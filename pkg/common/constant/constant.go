@@ -5,9 +5,18 @@ const (
 )
 
 const (
-	ContextKeyUser    = "USER"
-	ContextKeyUserID  = "USER_ID"
-	ContextKeySession = "SESSION"
+	ContextKeyUser      = "USER"
+	ContextKeyUserID    = "USER_ID"
+	ContextKeySession   = "SESSION"
+	ContextKeyExpiry    = "EXPIRY"
+	ContextKeyUserAgent = "USER_AGENT"
+	ContextKeyClientIP  = "CLIENT_IP"
+	ContextKeyRoles     = "ROLES"
+	ContextKeyApiKey    = "API_KEY"
+	ContextKeyScopes    = "SCOPES"
+	ContextKeyClientID  = "CLIENT_ID"
+	ContextKeyActorID   = "ACTOR_ID"
+	ContextKeyTenantID  = "TENANT_ID"
 )
 
 const (
@@ -3,6 +3,7 @@ package general
 import (
 	"context"
 	"github.com/lactobasilusprotectus/go-template/pkg/common/constant"
+	"time"
 )
 
 func GetUserIDFromCtx(ctx context.Context) (userID int64, ok bool) {
@@ -17,3 +18,86 @@ func SetUserIDIntoCtx(ctx context.Context, userID int64) context.Context {
 func SetSessionIDIntoCtx(ctx context.Context, sessionID string) context.Context {
 	return context.WithValue(ctx, constant.ContextKeySession, sessionID)
 }
+
+func GetSessionIDFromCtx(ctx context.Context) (sessionID string, ok bool) {
+	sessionID, ok = ctx.Value(constant.ContextKeySession).(string)
+	return
+}
+
+// SetActorIDIntoCtx sets the user acting on behalf of the user of the context, when impersonating
+func SetActorIDIntoCtx(ctx context.Context, actorID int64) context.Context {
+	return context.WithValue(ctx, constant.ContextKeyActorID, actorID)
+}
+
+func GetActorIDFromCtx(ctx context.Context) (actorID int64, ok bool) {
+	actorID, ok = ctx.Value(constant.ContextKeyActorID).(int64)
+	return
+}
+
+// SetTenantIDIntoCtx sets the organization the request is made within
+func SetTenantIDIntoCtx(ctx context.Context, tenantID int64) context.Context {
+	return context.WithValue(ctx, constant.ContextKeyTenantID, tenantID)
+}
+
+func GetTenantIDFromCtx(ctx context.Context) (tenantID int64, ok bool) {
+	tenantID, ok = ctx.Value(constant.ContextKeyTenantID).(int64)
+	return
+}
+
+func SetTokenExpiryIntoCtx(ctx context.Context, expiresAt time.Time) context.Context {
+	return context.WithValue(ctx, constant.ContextKeyExpiry, expiresAt)
+}
+
+func GetTokenExpiryFromCtx(ctx context.Context) (expiresAt time.Time, ok bool) {
+	expiresAt, ok = ctx.Value(constant.ContextKeyExpiry).(time.Time)
+	return
+}
+
+func SetClientInfoIntoCtx(ctx context.Context, userAgent, clientIP string) context.Context {
+	ctx = context.WithValue(ctx, constant.ContextKeyUserAgent, userAgent)
+	return context.WithValue(ctx, constant.ContextKeyClientIP, clientIP)
+}
+
+func GetUserAgentFromCtx(ctx context.Context) (userAgent string, ok bool) {
+	userAgent, ok = ctx.Value(constant.ContextKeyUserAgent).(string)
+	return
+}
+
+func GetClientIPFromCtx(ctx context.Context) (clientIP string, ok bool) {
+	clientIP, ok = ctx.Value(constant.ContextKeyClientIP).(string)
+	return
+}
+
+func SetRolesIntoCtx(ctx context.Context, roles []string) context.Context {
+	return context.WithValue(ctx, constant.ContextKeyRoles, roles)
+}
+
+func GetRolesFromCtx(ctx context.Context) (roles []string, ok bool) {
+	roles, ok = ctx.Value(constant.ContextKeyRoles).([]string)
+	return
+}
+
+func SetApiKeyIntoCtx(ctx context.Context, apiKeyID int64, scopes []string) context.Context {
+	ctx = context.WithValue(ctx, constant.ContextKeyApiKey, apiKeyID)
+	return context.WithValue(ctx, constant.ContextKeyScopes, scopes)
+}
+
+func GetApiKeyIDFromCtx(ctx context.Context) (apiKeyID int64, ok bool) {
+	apiKeyID, ok = ctx.Value(constant.ContextKeyApiKey).(int64)
+	return
+}
+
+func GetScopesFromCtx(ctx context.Context) (scopes []string, ok bool) {
+	scopes, ok = ctx.Value(constant.ContextKeyScopes).([]string)
+	return
+}
+
+func SetOAuthClientIntoCtx(ctx context.Context, clientID string, scopes []string) context.Context {
+	ctx = context.WithValue(ctx, constant.ContextKeyClientID, clientID)
+	return context.WithValue(ctx, constant.ContextKeyScopes, scopes)
+}
+
+func GetOAuthClientIDFromCtx(ctx context.Context) (clientID string, ok bool) {
+	clientID, ok = ctx.Value(constant.ContextKeyClientID).(string)
+	return
+}
@@ -5,14 +5,52 @@ import (
 	"strings"
 )
 
+const (
+	AuthSchemeBearer = "Bearer"
+	AuthSchemeApiKey = "ApiKey"
+)
+
+// cookies of the browser session mode
+const (
+	AccessTokenCookie  = "access_token"
+	RefreshTokenCookie = "refresh_token"
+	CsrfTokenCookie    = "csrf_token"
+	CsrfTokenHeader    = "X-CSRF-Token"
+
+	// the refresh token is only ever sent to the refresh endpoint, requests elsewhere can't leak it
+	RefreshTokenCookiePath = "/refresh"
+)
+
+// cookies binding a login flow to the browser starting it
+const (
+	OidcStateCookie     = "oidc_state"
+	OidcStateCookiePath = "/oidc"
+
+	MagicLinkNonceCookie     = "magic_link_nonce"
+	MagicLinkNonceCookiePath = "/login/magic-link"
+)
+
 func GetTokenFromRequest(g *gin.Context) string {
+	_, token := GetAuthorizationFromRequest(g, false)
+	return token
+}
+
+// GetAuthorizationFromRequest splits the Authorization header into its scheme and credentials. Without the header,
+// the access token cookie is used as a Bearer token if cookieFallback is set, i.e. the browser session mode is on.
+func GetAuthorizationFromRequest(g *gin.Context, cookieFallback bool) (scheme, credentials string) {
 	token := g.Request.Header.Get("Authorization")
 
+	if token == "" && cookieFallback {
+		if cookie, err := g.Cookie(AccessTokenCookie); err == nil && cookie != "" {
+			return AuthSchemeBearer, cookie
+		}
+	}
+
 	// normally Authorization the_token_xxx
 	strArr := strings.Split(token, " ")
 	if len(strArr) == 2 {
-		return strArr[1]
+		return strArr[0], strArr[1]
 	}
 
-	return ""
+	return "", ""
 }
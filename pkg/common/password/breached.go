@@ -0,0 +1,91 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// breached hashes are grouped by the first characters of their SHA-1, as in the k-anonymity range API
+const breachedPrefixLength = 5
+
+// BreachedList is a local corpus of breached passwords, kept as SHA-1 hashes only
+type BreachedList struct {
+	ranges map[string]map[string]struct{}
+	count  int
+}
+
+// LoadBreachedList loads a file of uppercase or lowercase hex SHA-1 hashes, one per line, optionally followed
+// by ":<count>" as in the Pwned Passwords downloads. Empty lines and lines starting with # are skipped.
+func LoadBreachedList(path string) (list *BreachedList, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open breached list err: %+v", err)
+	}
+	defer file.Close()
+
+	list = &BreachedList{ranges: map[string]map[string]struct{}{}}
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(line, ":")
+		hash = strings.ToUpper(hash)
+
+		if _, err = hex.DecodeString(hash); err != nil || len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("breached list %s line %d is not a SHA-1 hash", path, lineNumber)
+		}
+
+		list.add(hash)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read breached list err: %+v", err)
+	}
+
+	return list, nil
+}
+
+// Contains tells whether the password is part of the corpus
+func (b *BreachedList) Contains(password string) bool {
+	prefix, suffix := splitHash(sha1Hex(password))
+
+	_, ok := b.ranges[prefix][suffix]
+	return ok
+}
+
+// Len returns the number of hashes in the corpus
+func (b *BreachedList) Len() int {
+	return b.count
+}
+
+func (b *BreachedList) add(hash string) {
+	prefix, suffix := splitHash(hash)
+
+	suffixes, ok := b.ranges[prefix]
+	if !ok {
+		suffixes = map[string]struct{}{}
+		b.ranges[prefix] = suffixes
+	}
+
+	if _, ok = suffixes[suffix]; !ok {
+		suffixes[suffix] = struct{}{}
+		b.count++
+	}
+}
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func splitHash(hash string) (prefix, suffix string) {
+	return hash[:breachedPrefixLength], hash[breachedPrefixLength:]
+}
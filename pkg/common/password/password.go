@@ -1,24 +1,226 @@
 package password
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
+	"runtime"
+	"strings"
 )
 
-// HashPassword for encrypt password
-func HashPassword(pass string) (hash string, err error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var (
+	ErrAlgorithmUnsupported = errors.New("password hash algorithm unsupported")
+	ErrHashMalformed        = errors.New("password hash malformed")
+)
+
+type Interface interface {
+	// Hash hashes the password with the configured algorithm
+	Hash(password string) (hash string, err error)
+
+	// Verify tells whether the password matches a hash of any supported algorithm
+	Verify(password, hash string) (match bool, err error)
+
+	// NeedsRehash tells whether the hash was made with another algorithm or other parameters than configured
+	NeedsRehash(hash string) bool
+}
+
+// Argon2Params are the cost parameters of argon2id
+type Argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// Hasher hashes new passwords with the configured algorithm, and verifies hashes of every supported one.
+// Hashing is costly by design, so only a bounded number of hashes are computed at once.
+type Hasher struct {
+	algorithm  string
+	argon2     Argon2Params
+	bcryptCost int
+	slots      chan struct{}
+}
+
+// New creates new Hasher
+func New(cfg config.PasswordConfig) (*Hasher, error) {
+	h := &Hasher{
+		algorithm: cfg.Algorithm,
+		argon2: Argon2Params{
+			Memory:      cfg.Argon2Memory,
+			Iterations:  cfg.Argon2Iterations,
+			Parallelism: cfg.Argon2Parallelism,
+		},
+		bcryptCost: cfg.BcryptCost,
+	}
+
+	concurrency := cfg.HashConcurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+	h.slots = make(chan struct{}, concurrency)
+
+	switch h.algorithm {
+	case AlgorithmArgon2id:
+		if h.argon2.Memory == 0 || h.argon2.Iterations == 0 || h.argon2.Parallelism == 0 {
+			return nil, fmt.Errorf("%w: argon2id parameters must be positive", ErrAlgorithmUnsupported)
+		}
+	case AlgorithmBcrypt:
+		if h.bcryptCost < bcrypt.MinCost || h.bcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("%w: bcrypt cost must be between %d and %d", ErrAlgorithmUnsupported,
+				bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrAlgorithmUnsupported, h.algorithm)
+	}
+
+	return h, nil
+}
+
+// Hash hashes the password with the configured algorithm
+func (h *Hasher) Hash(password string) (hash string, err error) {
+	h.acquire()
+	defer h.release()
+
+	if h.algorithm == AlgorithmBcrypt {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("bcrypt hash err: %+v", err)
+		}
+
+		return string(hashed), nil
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err = rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt err: %+v", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.argon2.Iterations, h.argon2.Memory, h.argon2.Parallelism,
+		argon2KeyLength)
+
+	return encodeArgon2(h.argon2, salt, key), nil
+}
+
+// Verify tells whether the password matches a hash of any supported algorithm
+func (h *Hasher) Verify(password, hash string) (match bool, err error) {
+	h.acquire()
+	defer h.release()
+
+	if isBcrypt(hash) {
+		err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+
+		if err != nil {
+			return false, fmt.Errorf("%w: %+v", ErrHashMalformed, err)
+		}
+
+		return true, nil
+	}
+
+	params, salt, key, err := decodeArgon2(hash)
 	if err != nil {
-		err = fmt.Errorf("Error password hash "+pass+", returns err: %+v", err)
-		return
+		return false, err
 	}
 
-	return string(hashedPassword), nil
+	computed := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism,
+		uint32(len(key)))
+
+	return subtle.ConstantTimeCompare(computed, key) == 1, nil
+}
+
+// NeedsRehash tells whether the hash was made with another algorithm or other parameters than configured
+func (h *Hasher) NeedsRehash(hash string) bool {
+	if isBcrypt(hash) {
+		if h.algorithm != AlgorithmBcrypt {
+			return true
+		}
+
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost != h.bcryptCost
+	}
+
+	if h.algorithm != AlgorithmArgon2id {
+		return true
+	}
+
+	params, _, key, err := decodeArgon2(hash)
+	return err != nil || params != h.argon2 || len(key) != argon2KeyLength
+}
+
+// wait for a free hashing slot
+func (h *Hasher) acquire() {
+	h.slots <- struct{}{}
+}
+
+func (h *Hasher) release() {
+	<-h.slots
+}
+
+func isBcrypt(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
 }
 
-// CheckPasswordHash for true or false
-func CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+// encodeArgon2 encodes an argon2id hash in the PHC string format:
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+func encodeArgon2(params Argon2Params, salt, key []byte) string {
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", AlgorithmArgon2id, argon2.Version, params.Memory,
+		params.Iterations, params.Parallelism, base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key))
+}
+
+// decodeArgon2 decodes an argon2id hash in the PHC string format
+func decodeArgon2(hash string) (params Argon2Params, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) < 2 || parts[0] != "" {
+		err = fmt.Errorf("%w: unknown format", ErrHashMalformed)
+		return
+	}
+
+	if parts[1] != AlgorithmArgon2id {
+		err = fmt.Errorf("%w: %s", ErrAlgorithmUnsupported, parts[1])
+		return
+	}
+
+	if len(parts) != 6 {
+		err = fmt.Errorf("%w: unknown format", ErrHashMalformed)
+		return
+	}
+
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		err = fmt.Errorf("%w: unsupported argon2 version %s", ErrHashMalformed, parts[2])
+		return
+	}
+
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	if err != nil || params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		err = fmt.Errorf("%w: parameters %s", ErrHashMalformed, parts[3])
+		return
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		err = fmt.Errorf("%w: salt: %+v", ErrHashMalformed, err)
+		return
+	}
+
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(key) == 0 {
+		err = fmt.Errorf("%w: key", ErrHashMalformed)
+		return
+	}
 
-	return err == nil
+	return params, salt, key, nil
 }
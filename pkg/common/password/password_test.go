@@ -0,0 +1,212 @@
+package password
+
+import (
+	"errors"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	argon2Config = config.PasswordConfig{
+		Algorithm:         AlgorithmArgon2id,
+		Argon2Memory:      1024,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+	}
+	bcryptConfig = config.PasswordConfig{
+		Algorithm:  AlgorithmBcrypt,
+		BcryptCost: bcrypt.MinCost,
+	}
+)
+
+func newHasher(t *testing.T, cfg config.PasswordConfig) *Hasher {
+	hasher, err := New(cfg)
+	require.NoError(t, err)
+
+	return hasher
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name string
+		cfg  config.PasswordConfig
+		err  error
+	}{
+		{name: "argon2id", cfg: argon2Config},
+		{name: "bcrypt", cfg: bcryptConfig},
+		{
+			name: "unknown algorithm",
+			cfg:  config.PasswordConfig{Algorithm: "md5"},
+			err:  ErrAlgorithmUnsupported,
+		},
+		{
+			name: "bcrypt cost out of range",
+			cfg:  config.PasswordConfig{Algorithm: AlgorithmBcrypt, BcryptCost: 40},
+			err:  ErrAlgorithmUnsupported,
+		},
+		{
+			name: "argon2id without parameters",
+			cfg:  config.PasswordConfig{Algorithm: AlgorithmArgon2id},
+			err:  ErrAlgorithmUnsupported,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.cfg)
+
+			assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+		})
+	}
+}
+
+func TestHasher_HashAndVerify(t *testing.T) {
+	for _, cfg := range []config.PasswordConfig{argon2Config, bcryptConfig} {
+		t.Run(cfg.Algorithm, func(t *testing.T) {
+			hasher := newHasher(t, cfg)
+
+			hash, err := hasher.Hash("correct horse")
+			require.NoError(t, err)
+			assert.NotContains(t, hash, "correct horse")
+
+			match, err := hasher.Verify("correct horse", hash)
+			assert.NoError(t, err)
+			assert.True(t, match)
+
+			match, err = hasher.Verify("battery staple", hash)
+			assert.NoError(t, err)
+			assert.False(t, match)
+
+			assert.False(t, hasher.NeedsRehash(hash))
+		})
+	}
+}
+
+func TestHasher_concurrency(t *testing.T) {
+	assert.Equal(t, runtime.NumCPU(), cap(newHasher(t, argon2Config).slots))
+
+	cfg := argon2Config
+	cfg.HashConcurrency = 1
+	hasher := newHasher(t, cfg)
+
+	// hold the only slot, hashing has to wait for it
+	hasher.acquire()
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = hasher.Hash("password")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("hash computed without a free slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	hasher.release()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("hash not computed after the slot was freed")
+	}
+}
+
+func TestHasher_Hash_argon2PHCFormat(t *testing.T) {
+	hash, err := newHasher(t, argon2Config).Hash("password")
+	require.NoError(t, err)
+
+	parts := strings.Split(hash, "$")
+	require.Len(t, parts, 6)
+	assert.Equal(t, "argon2id", parts[1])
+	assert.Equal(t, "v=19", parts[2])
+	assert.Equal(t, "m=1024,t=1,p=1", parts[3])
+
+	// salts are random
+	other, err := newHasher(t, argon2Config).Hash("password")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+}
+
+func TestHasher_Verify_acrossAlgorithms(t *testing.T) {
+	bcryptHash, err := newHasher(t, bcryptConfig).Hash("password")
+	require.NoError(t, err)
+	argon2Hash, err := newHasher(t, argon2Config).Hash("password")
+	require.NoError(t, err)
+
+	// whatever is configured, existing hashes keep verifying
+	for _, hasher := range []*Hasher{newHasher(t, argon2Config), newHasher(t, bcryptConfig)} {
+		for _, hash := range []string{bcryptHash, argon2Hash} {
+			match, err := hasher.Verify("password", hash)
+			assert.NoError(t, err)
+			assert.True(t, match)
+		}
+	}
+}
+
+func TestHasher_Verify_malformed(t *testing.T) {
+	hasher := newHasher(t, argon2Config)
+
+	testCases := []struct {
+		name string
+		hash string
+		err  error
+	}{
+		{name: "empty", hash: "", err: ErrHashMalformed},
+		{name: "unknown algorithm", hash: "$scrypt$ln=16,r=8,p=1$c2FsdA$a2V5", err: ErrAlgorithmUnsupported},
+		{name: "unknown version", hash: "$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$a2V5", err: ErrHashMalformed},
+		{name: "zero parallelism", hash: "$argon2id$v=19$m=1024,t=1,p=0$c2FsdA$a2V5", err: ErrHashMalformed},
+		{name: "invalid salt", hash: "$argon2id$v=19$m=1024,t=1,p=1$!!$a2V5", err: ErrHashMalformed},
+		{name: "truncated bcrypt", hash: "$2a$10$short", err: ErrHashMalformed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			match, err := hasher.Verify("password", tc.hash)
+
+			assert.False(t, match)
+			assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+		})
+	}
+}
+
+func TestHasher_NeedsRehash(t *testing.T) {
+	legacyHash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	argon2Hash, err := newHasher(t, argon2Config).Hash("password")
+	require.NoError(t, err)
+
+	strongerArgon2 := argon2Config
+	strongerArgon2.Argon2Iterations = 2
+
+	strongerBcrypt := bcryptConfig
+	strongerBcrypt.BcryptCost = bcrypt.DefaultCost
+
+	testCases := []struct {
+		name     string
+		cfg      config.PasswordConfig
+		hash     string
+		expected bool
+	}{
+		{name: "bcrypt to argon2id", cfg: argon2Config, hash: string(legacyHash), expected: true},
+		{name: "argon2id to bcrypt", cfg: bcryptConfig, hash: argon2Hash, expected: true},
+		{name: "argon2id parameters raised", cfg: strongerArgon2, hash: argon2Hash, expected: true},
+		{name: "bcrypt cost changed", cfg: bcryptConfig, hash: string(legacyHash), expected: true},
+		{name: "bcrypt cost unchanged", cfg: strongerBcrypt, hash: string(legacyHash), expected: false},
+		{name: "argon2id unchanged", cfg: argon2Config, hash: argon2Hash, expected: false},
+		{name: "malformed", cfg: argon2Config, hash: "garbage", expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, newHasher(t, tc.cfg).NeedsRehash(tc.hash))
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"strings"
+	"unicode"
+)
+
+const (
+	RuleMinLength    = "min_length"
+	RuleMaxLength    = "max_length"
+	RuleUppercase    = "uppercase"
+	RuleLowercase    = "lowercase"
+	RuleDigit        = "digit"
+	RuleSymbol       = "symbol"
+	RulePersonalInfo = "personal_info"
+	RuleBreached     = "breached"
+
+	// personal information shorter than this is too common to be rejected
+	minPersonalInfoLength = 3
+)
+
+var ErrPolicyViolated = errors.New("password policy violated")
+
+type Validator interface {
+	// Validate checks the password against the policy, personal information such as username or email must
+	// not be part of it
+	Validate(password string, personalInfo ...string) (err error)
+}
+
+// Violation is a rule of the policy a password doesn't satisfy
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+// PolicyError lists every violated rule
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", ErrPolicyViolated, strings.Join(messages, "; "))
+}
+
+func (e *PolicyError) Is(target error) bool {
+	return target == ErrPolicyViolated
+}
+
+// Policy is the configurable password policy
+type Policy struct {
+	cfg      config.PasswordConfig
+	breached *BreachedList
+}
+
+// NewPolicy creates new Policy, breached may be nil when no list is configured
+func NewPolicy(cfg config.PasswordConfig, breached *BreachedList) *Policy {
+	return &Policy{
+		cfg:      cfg,
+		breached: breached,
+	}
+}
+
+// Validate checks the password against the policy
+func (p *Policy) Validate(password string, personalInfo ...string) (err error) {
+	var violations []Violation
+
+	if length := len([]rune(password)); length < p.cfg.MinLength {
+		violations = append(violations, Violation{RuleMinLength,
+			fmt.Sprintf("must be at least %d characters long", p.cfg.MinLength)})
+	}
+
+	// bcrypt ignores anything past 72 bytes, multi-byte characters count for more than one
+	if p.cfg.MaxLength > 0 && len(password) > p.cfg.MaxLength {
+		violations = append(violations, Violation{RuleMaxLength,
+			fmt.Sprintf("must be at most %d bytes long", p.cfg.MaxLength)})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.cfg.RequireUppercase && !hasUpper {
+		violations = append(violations, Violation{RuleUppercase, "must contain an uppercase letter"})
+	}
+
+	if p.cfg.RequireLowercase && !hasLower {
+		violations = append(violations, Violation{RuleLowercase, "must contain a lowercase letter"})
+	}
+
+	if p.cfg.RequireDigit && !hasDigit {
+		violations = append(violations, Violation{RuleDigit, "must contain a digit"})
+	}
+
+	if p.cfg.RequireSymbol && !hasSymbol {
+		violations = append(violations, Violation{RuleSymbol, "must contain a symbol"})
+	}
+
+	if containsPersonalInfo(password, personalInfo) {
+		violations = append(violations, Violation{RulePersonalInfo, "must not contain your username or email"})
+	}
+
+	if p.breached != nil && p.breached.Contains(password) {
+		violations = append(violations, Violation{RuleBreached,
+			"has appeared in a data breach, choose another one"})
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+// an email is checked as a whole and by its local part
+func containsPersonalInfo(password string, personalInfo []string) bool {
+	lowered := strings.ToLower(password)
+
+	for _, info := range personalInfo {
+		info = strings.ToLower(strings.TrimSpace(info))
+
+		candidates := []string{info}
+		if at := strings.Index(info, "@"); at > 0 {
+			candidates = append(candidates, info[:at])
+		}
+
+		for _, candidate := range candidates {
+			if len(candidate) >= minPersonalInfoLength && strings.Contains(lowered, candidate) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
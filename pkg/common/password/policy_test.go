@@ -0,0 +1,141 @@
+package password
+
+import (
+	"errors"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeBreachedList(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	// SHA-1 of "password1" with a count, and of "letmein" in lowercase
+	breached, err := LoadBreachedList(writeBreachedList(t, "# corpus\n"+
+		"E38AD214943DAAD1D64C102FAEC29DE4AFE9DA3D:2413945\n"+
+		"b7a875fc1ea228b9061041b7cec4bd3c52ab3ce3\n"))
+	require.NoError(t, err)
+	require.Equal(t, 2, breached.Len())
+
+	strict := config.PasswordConfig{
+		MinLength:        8,
+		MaxLength:        72,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+	}
+
+	testCases := []struct {
+		name         string
+		cfg          config.PasswordConfig
+		password     string
+		personalInfo []string
+		rules        []string
+	}{
+		{
+			name:     "positive",
+			cfg:      strict,
+			password: "Tr0ub4dor&3",
+		},
+		{
+			name:     "too short",
+			cfg:      config.PasswordConfig{MinLength: 8},
+			password: "Sh0rt!",
+			rules:    []string{RuleMinLength},
+		},
+		{
+			name:     "length counts characters",
+			cfg:      config.PasswordConfig{MinLength: 8},
+			password: "пароль12",
+		},
+		{
+			name:     "too many bytes",
+			cfg:      config.PasswordConfig{MinLength: 8, MaxLength: 72},
+			password: strings.Repeat("ü", 40),
+			rules:    []string{RuleMaxLength},
+		},
+		{
+			name:     "missing character classes",
+			cfg:      strict,
+			password: "alllowercase",
+			rules:    []string{RuleUppercase, RuleDigit, RuleSymbol},
+		},
+		{
+			name:         "contains username",
+			cfg:          config.PasswordConfig{MinLength: 8},
+			password:     "my-JohnDoe-password",
+			personalInfo: []string{"johndoe", "someone@example.com"},
+			rules:        []string{RulePersonalInfo},
+		},
+		{
+			name:         "contains email local part",
+			cfg:          config.PasswordConfig{MinLength: 8},
+			password:     "someone2024",
+			personalInfo: []string{"johndoe", "Someone@example.com"},
+			rules:        []string{RulePersonalInfo},
+		},
+		{
+			name:         "short personal info is ignored",
+			cfg:          config.PasswordConfig{MinLength: 8},
+			password:     "jo-correct-horse",
+			personalInfo: []string{"jo"},
+		},
+		{
+			name:     "breached",
+			cfg:      config.PasswordConfig{MinLength: 8},
+			password: "password1",
+			rules:    []string{RuleBreached},
+		},
+		{
+			name:     "breached listed in lowercase",
+			cfg:      config.PasswordConfig{MinLength: 6},
+			password: "letmein",
+			rules:    []string{RuleBreached},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewPolicy(tc.cfg, breached).Validate(tc.password, tc.personalInfo...)
+
+			if len(tc.rules) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, errors.Is(err, ErrPolicyViolated))
+
+			var policyErr *PolicyError
+			require.True(t, errors.As(err, &policyErr))
+
+			var rules []string
+			for _, violation := range policyErr.Violations {
+				rules = append(rules, violation.Rule)
+				assert.NotEmpty(t, violation.Message)
+			}
+			assert.Equal(t, tc.rules, rules)
+		})
+	}
+}
+
+func TestPolicy_Validate_withoutBreachedList(t *testing.T) {
+	assert.NoError(t, NewPolicy(config.PasswordConfig{MinLength: 8}, nil).Validate("password1"))
+}
+
+func TestLoadBreachedList_invalid(t *testing.T) {
+	_, err := LoadBreachedList(writeBreachedList(t, "E38AD214943DAAD1D64C102FAEC29DE4AFE9DA3D\nnot-a-hash\n"))
+	assert.ErrorContains(t, err, "line 2")
+
+	_, err = LoadBreachedList(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
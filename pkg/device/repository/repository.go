@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/db"
+	"gorm.io/gorm"
+	"time"
+)
+
+type DeviceRepository struct {
+	dbClient *db.DatabaseConnection
+	time     commonTime.TimeInterface
+}
+
+func NewDeviceRepository(dbClient *db.DatabaseConnection, time commonTime.TimeInterface) *DeviceRepository {
+	return &DeviceRepository{
+		dbClient: dbClient,
+		time:     time,
+	}
+}
+
+func (r *DeviceRepository) FindKnownDevice(userID int64, fingerprint string) (domain.KnownDevice, error) {
+	var device domain.KnownDevice
+
+	result := r.dbClient.Slave.Where("user_id = ? AND fingerprint = ?", userID, fingerprint).First(&device)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.KnownDevice{}, common.ErrDeviceNotFound
+	}
+
+	if result.Error != nil {
+		return domain.KnownDevice{}, result.Error
+	}
+
+	return device, nil
+}
+
+func (r *DeviceRepository) CountKnownDevices(userID int64) (int64, error) {
+	var count int64
+
+	result := r.dbClient.Slave.Model(&domain.KnownDevice{}).Where("user_id = ?", userID).Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+func (r *DeviceRepository) InsertKnownDevice(device *domain.KnownDevice) (err error) {
+	result := r.dbClient.Master.Create(device)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no row affected")
+	}
+
+	return nil
+}
+
+func (r *DeviceRepository) UpdateKnownDeviceLastSeenAt(id int64, lastSeenAt time.Time) (err error) {
+	result := r.dbClient.Master.Model(&domain.KnownDevice{}).Where("id = ?", id).Update("last_seen_at", lastSeenAt)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+// DeleteKnownDevice deletes the device only if it belongs to the given user
+func (r *DeviceRepository) DeleteKnownDevice(userID, id int64) (err error) {
+	result := r.dbClient.Master.Where("id = ? AND user_id = ?", id, userID).Delete(&domain.KnownDevice{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return common.ErrDeviceNotFound
+	}
+
+	return nil
+}
+
+func (r *DeviceRepository) InsertLoginRecord(record *domain.LoginRecord) (err error) {
+	result := r.dbClient.Master.Create(record)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no row affected")
+	}
+
+	return nil
+}
+
+func (r *DeviceRepository) FindLastLoginRecord(userID int64) (domain.LoginRecord, error) {
+	var record domain.LoginRecord
+
+	result := r.dbClient.Slave.Where("user_id = ?", userID).Order("created_at DESC").Order("id DESC").
+		First(&record)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.LoginRecord{}, common.ErrLoginRecordNotFound
+	}
+
+	if result.Error != nil {
+		return domain.LoginRecord{}, result.Error
+	}
+
+	return record, nil
+}
+
+func (r *DeviceRepository) FindLoginRecordBySessionID(sessionID string) (domain.LoginRecord, error) {
+	var record domain.LoginRecord
+
+	result := r.dbClient.Slave.Where("session_id = ?", sessionID).First(&record)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.LoginRecord{}, common.ErrLoginRecordNotFound
+	}
+
+	if result.Error != nil {
+		return domain.LoginRecord{}, result.Error
+	}
+
+	return record, nil
+}
+
+// FindLoginRecordsByUserID returns the latest logins of the user, most recent first
+func (r *DeviceRepository) FindLoginRecordsByUserID(userID int64, limit int) ([]domain.LoginRecord, error) {
+	var records []domain.LoginRecord
+
+	result := r.dbClient.Slave.Where("user_id = ?", userID).Order("created_at DESC").Order("id DESC").
+		Limit(limit).Find(&records)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return records, nil
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"github.com/lactobasilusprotectus/go-template/pkg/account/common"
+	"time"
+)
+
+// PersonalDataRetainer is implemented by models whose rows outlive their deleted user, e.g. for compliance.
+// The returned columns are cleared instead of deleting the rows.
+type PersonalDataRetainer interface {
+	AnonymizedColumns() map[string]interface{}
+}
+
+//==================================================================================================
+// Use Case
+//==================================================================================================
+
+type AccountUseCase interface {
+	RequestExport(ctx context.Context) (err error)
+	DownloadExport(ctx context.Context, token string) (archive []byte, err error)
+	DeleteAccount(ctx context.Context, password, code string) (deletion common.AccountDeletion, err error)
+}
+
+//==================================================================================================
+// Repository
+//==================================================================================================
+
+type AccountRepository interface {
+	FindPersonalData(userID int64) (data map[string]interface{}, err error)
+	FindUserIDsDeletedBefore(before time.Time) (userIDs []int64, err error)
+	PurgeUser(userID int64) (err error)
+}
@@ -0,0 +1,185 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/account.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	common "github.com/lactobasilusprotectus/go-template/pkg/account/common"
+)
+
+// MockPersonalDataRetainer is a mock of PersonalDataRetainer interface.
+type MockPersonalDataRetainer struct {
+	ctrl     *gomock.Controller
+	recorder *MockPersonalDataRetainerMockRecorder
+}
+
+// MockPersonalDataRetainerMockRecorder is the mock recorder for MockPersonalDataRetainer.
+type MockPersonalDataRetainerMockRecorder struct {
+	mock *MockPersonalDataRetainer
+}
+
+// NewMockPersonalDataRetainer creates a new mock instance.
+func NewMockPersonalDataRetainer(ctrl *gomock.Controller) *MockPersonalDataRetainer {
+	mock := &MockPersonalDataRetainer{ctrl: ctrl}
+	mock.recorder = &MockPersonalDataRetainerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPersonalDataRetainer) EXPECT() *MockPersonalDataRetainerMockRecorder {
+	return m.recorder
+}
+
+// AnonymizedColumns mocks base method.
+func (m *MockPersonalDataRetainer) AnonymizedColumns() map[string]interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizedColumns")
+	ret0, _ := ret[0].(map[string]interface{})
+	return ret0
+}
+
+// AnonymizedColumns indicates an expected call of AnonymizedColumns.
+func (mr *MockPersonalDataRetainerMockRecorder) AnonymizedColumns() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizedColumns", reflect.TypeOf((*MockPersonalDataRetainer)(nil).AnonymizedColumns))
+}
+
+// MockAccountUseCase is a mock of AccountUseCase interface.
+type MockAccountUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountUseCaseMockRecorder
+}
+
+// MockAccountUseCaseMockRecorder is the mock recorder for MockAccountUseCase.
+type MockAccountUseCaseMockRecorder struct {
+	mock *MockAccountUseCase
+}
+
+// NewMockAccountUseCase creates a new mock instance.
+func NewMockAccountUseCase(ctrl *gomock.Controller) *MockAccountUseCase {
+	mock := &MockAccountUseCase{ctrl: ctrl}
+	mock.recorder = &MockAccountUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountUseCase) EXPECT() *MockAccountUseCaseMockRecorder {
+	return m.recorder
+}
+
+// DeleteAccount mocks base method.
+func (m *MockAccountUseCase) DeleteAccount(ctx context.Context, password, code string) (common.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", ctx, password, code)
+	ret0, _ := ret[0].(common.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockAccountUseCaseMockRecorder) DeleteAccount(ctx, password, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAccountUseCase)(nil).DeleteAccount), ctx, password, code)
+}
+
+// DownloadExport mocks base method.
+func (m *MockAccountUseCase) DownloadExport(ctx context.Context, token string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadExport", ctx, token)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadExport indicates an expected call of DownloadExport.
+func (mr *MockAccountUseCaseMockRecorder) DownloadExport(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadExport", reflect.TypeOf((*MockAccountUseCase)(nil).DownloadExport), ctx, token)
+}
+
+// RequestExport mocks base method.
+func (m *MockAccountUseCase) RequestExport(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestExport", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestExport indicates an expected call of RequestExport.
+func (mr *MockAccountUseCaseMockRecorder) RequestExport(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestExport", reflect.TypeOf((*MockAccountUseCase)(nil).RequestExport), ctx)
+}
+
+// MockAccountRepository is a mock of AccountRepository interface.
+type MockAccountRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountRepositoryMockRecorder
+}
+
+// MockAccountRepositoryMockRecorder is the mock recorder for MockAccountRepository.
+type MockAccountRepositoryMockRecorder struct {
+	mock *MockAccountRepository
+}
+
+// NewMockAccountRepository creates a new mock instance.
+func NewMockAccountRepository(ctrl *gomock.Controller) *MockAccountRepository {
+	mock := &MockAccountRepository{ctrl: ctrl}
+	mock.recorder = &MockAccountRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountRepository) EXPECT() *MockAccountRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindPersonalData mocks base method.
+func (m *MockAccountRepository) FindPersonalData(userID int64) (map[string]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPersonalData", userID)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPersonalData indicates an expected call of FindPersonalData.
+func (mr *MockAccountRepositoryMockRecorder) FindPersonalData(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPersonalData", reflect.TypeOf((*MockAccountRepository)(nil).FindPersonalData), userID)
+}
+
+// FindUserIDsDeletedBefore mocks base method.
+func (m *MockAccountRepository) FindUserIDsDeletedBefore(before time.Time) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserIDsDeletedBefore", before)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserIDsDeletedBefore indicates an expected call of FindUserIDsDeletedBefore.
+func (mr *MockAccountRepositoryMockRecorder) FindUserIDsDeletedBefore(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserIDsDeletedBefore", reflect.TypeOf((*MockAccountRepository)(nil).FindUserIDsDeletedBefore), before)
+}
+
+// PurgeUser mocks base method.
+func (m *MockAccountRepository) PurgeUser(userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeUser", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeUser indicates an expected call of PurgeUser.
+func (mr *MockAccountRepositoryMockRecorder) PurgeUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeUser", reflect.TypeOf((*MockAccountRepository)(nil).PurgeUser), userID)
+}
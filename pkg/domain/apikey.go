@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// ApiKey is a long-lived credential of a user meant for machine clients
+type ApiKey struct {
+	ID             int64      `json:"id" gorm:"primaryKey"`
+	UserID         int64      `json:"user_id" gorm:"index;not null"`
+	OrganizationID int64      `json:"organization_id" gorm:"index"` // organization requests are scoped to, 0 for none
+	Name           string     `json:"name" gorm:"size:100;not null"`
+	Prefix         string     `json:"prefix" gorm:"size:32;uniqueIndex;not null"`
+	KeyHash        string     `json:"-" gorm:"not null"`
+	Scopes         string     `json:"scopes"` // space separated permissions, empty means every permission of the user
+	ExpiresAt      *time.Time `json:"expires_at"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+//==================================================================================================
+// Repository
+//==================================================================================================
+
+type ApiKeyRepository interface {
+	InsertApiKey(apiKey *ApiKey) (err error)
+	FindApiKeyByPrefix(prefix string) (apiKey ApiKey, err error)
+	FindApiKeysByUserID(userID int64) (apiKeys []ApiKey, err error)
+	UpdateApiKeyLastUsedAt(id int64, lastUsedAt time.Time) (err error)
+	DeleteApiKey(userID, id int64) (err error)
+}
@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/apikey.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockApiKeyRepository is a mock of ApiKeyRepository interface.
+type MockApiKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiKeyRepositoryMockRecorder
+}
+
+// MockApiKeyRepositoryMockRecorder is the mock recorder for MockApiKeyRepository.
+type MockApiKeyRepositoryMockRecorder struct {
+	mock *MockApiKeyRepository
+}
+
+// NewMockApiKeyRepository creates a new mock instance.
+func NewMockApiKeyRepository(ctrl *gomock.Controller) *MockApiKeyRepository {
+	mock := &MockApiKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockApiKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiKeyRepository) EXPECT() *MockApiKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteApiKey mocks base method.
+func (m *MockApiKeyRepository) DeleteApiKey(userID, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteApiKey", userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteApiKey indicates an expected call of DeleteApiKey.
+func (mr *MockApiKeyRepositoryMockRecorder) DeleteApiKey(userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApiKey", reflect.TypeOf((*MockApiKeyRepository)(nil).DeleteApiKey), userID, id)
+}
+
+// FindApiKeyByPrefix mocks base method.
+func (m *MockApiKeyRepository) FindApiKeyByPrefix(prefix string) (ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindApiKeyByPrefix", prefix)
+	ret0, _ := ret[0].(ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindApiKeyByPrefix indicates an expected call of FindApiKeyByPrefix.
+func (mr *MockApiKeyRepositoryMockRecorder) FindApiKeyByPrefix(prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindApiKeyByPrefix", reflect.TypeOf((*MockApiKeyRepository)(nil).FindApiKeyByPrefix), prefix)
+}
+
+// FindApiKeysByUserID mocks base method.
+func (m *MockApiKeyRepository) FindApiKeysByUserID(userID int64) ([]ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindApiKeysByUserID", userID)
+	ret0, _ := ret[0].([]ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindApiKeysByUserID indicates an expected call of FindApiKeysByUserID.
+func (mr *MockApiKeyRepositoryMockRecorder) FindApiKeysByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindApiKeysByUserID", reflect.TypeOf((*MockApiKeyRepository)(nil).FindApiKeysByUserID), userID)
+}
+
+// InsertApiKey mocks base method.
+func (m *MockApiKeyRepository) InsertApiKey(apiKey *ApiKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertApiKey", apiKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertApiKey indicates an expected call of InsertApiKey.
+func (mr *MockApiKeyRepositoryMockRecorder) InsertApiKey(apiKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertApiKey", reflect.TypeOf((*MockApiKeyRepository)(nil).InsertApiKey), apiKey)
+}
+
+// UpdateApiKeyLastUsedAt mocks base method.
+func (m *MockApiKeyRepository) UpdateApiKeyLastUsedAt(id int64, lastUsedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateApiKeyLastUsedAt", id, lastUsedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateApiKeyLastUsedAt indicates an expected call of UpdateApiKeyLastUsedAt.
+func (mr *MockApiKeyRepositoryMockRecorder) UpdateApiKeyLastUsedAt(id, lastUsedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApiKeyLastUsedAt", reflect.TypeOf((*MockApiKeyRepository)(nil).UpdateApiKeyLastUsedAt), id, lastUsedAt)
+}
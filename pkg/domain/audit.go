@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	"time"
+)
+
+// AuditEvent records who did what, from where and when, for compliance
+type AuditEvent struct {
+	ID             int64     `json:"id" gorm:"primaryKey"`
+	Type           string    `json:"type" gorm:"size:50;index;not null"`
+	UserID         int64     `json:"user_id" gorm:"index"`         // 0 when unknown, e.g. failed login of an unknown email
+	ActorID        int64     `json:"actor_id" gorm:"index"`        // admin acting as the user, 0 unless impersonating
+	OrganizationID int64     `json:"organization_id" gorm:"index"` // organization the event happened in, 0 outside any
+	Email          string    `json:"email" gorm:"size:255"`
+	SessionID      string    `json:"session_id" gorm:"size:36"`
+	ClientIP       string    `json:"client_ip" gorm:"size:45"`
+	UserAgent      string    `json:"user_agent" gorm:"size:512"`
+	Detail         string    `json:"detail"`
+	CreatedAt      time.Time `json:"created_at" gorm:"index"`
+}
+
+// AnonymizedColumns keeps the events of deleted users for compliance, without what identifies them
+func (AuditEvent) AnonymizedColumns() map[string]interface{} {
+	return map[string]interface{}{
+		"email":      "",
+		"client_ip":  "",
+		"user_agent": "",
+	}
+}
+
+// AuditEventFilter selects audit events, zero fields don't filter
+type AuditEventFilter struct {
+	OrganizationID int64
+	UserID         int64
+	ActorID        int64
+	Type           string
+	From           *time.Time
+	To             *time.Time
+	Offset         int
+	Limit          int
+}
+
+// AuditEventPage is one page of audit events, most recent first
+type AuditEventPage struct {
+	Events   []AuditEvent `json:"events"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+	Total    int64        `json:"total"`
+}
+
+//==================================================================================================
+// Use Case
+//==================================================================================================
+
+// AuditRecorder records audit events without blocking the caller, user, session and client information missing
+// from the event are taken from the context
+type AuditRecorder interface {
+	Record(ctx context.Context, event AuditEvent)
+}
+
+type AuditUseCase interface {
+	AuditRecorder
+	ListEvents(ctx context.Context, request common.ListEventsRequest) (page AuditEventPage, err error)
+}
+
+//==================================================================================================
+// Repository
+//==================================================================================================
+
+type AuditRepository interface {
+	InsertAuditEvent(event AuditEvent) (err error)
+	FindAuditEvents(filter AuditEventFilter) (events []AuditEvent, total int64, err error)
+	DeleteAuditEventsBefore(before time.Time) (count int64, err error)
+}
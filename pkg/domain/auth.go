@@ -6,8 +6,40 @@ import (
 )
 
 type AuthUseCase interface {
-	Register(user User) (err error)
+	Register(ctx context.Context, user User, invitationCode string) (err error)
 	Login(ctx context.Context, email, password string) (token common.LoginToken, err error)
+	Refresh(ctx context.Context, refreshToken string) (token common.LoginToken, err error)
+	LoginMfa(ctx context.Context, mfaToken, code, recoveryCode string) (token common.LoginToken, err error)
+	RequestMagicLink(ctx context.Context, email string) (nonce string, err error)
+	MagicLinkCallback(ctx context.Context, token, nonce string) (loginToken common.LoginToken, err error)
+	UnlockAccount(ctx context.Context, email string) (err error)
 	Info(ctx context.Context) (info common.LoginInfo, err error)
+	Logout(ctx context.Context) (info common.LogoutInfo, err error)
+	LogoutAll(ctx context.Context) (info common.LogoutInfo, err error)
+	ListSessions(ctx context.Context) (sessions []common.SessionInfo, err error)
+	RevokeSession(ctx context.Context, sessionID string) (err error)
+	ListLoginHistory(ctx context.Context) (records []LoginRecord, err error)
+	FindReportedLogin(ctx context.Context, token string) (record LoginRecord, err error)
+	ReportLogin(ctx context.Context, token string) (err error)
 	SendEmail(ctx context.Context, request common.LoginRequest) (err error)
+	ForgotPassword(ctx context.Context, email string) (err error)
+	ResetPassword(ctx context.Context, token, password string) (err error)
+	Reauthenticate(ctx context.Context, password, code string) (err error)
+	VerifyEmail(ctx context.Context, token string) (err error)
+	ResendVerificationEmail(ctx context.Context, email string) (err error)
+	EnrollTotp(ctx context.Context) (enrollment common.TotpEnrollment, err error)
+	ConfirmTotp(ctx context.Context, code string) (codes common.RecoveryCodes, err error)
+	DisableTotp(ctx context.Context, code string) (err error)
+	CreateApiKey(ctx context.Context, request common.CreateApiKeyRequest) (key common.CreatedApiKey, err error)
+	ListApiKeys(ctx context.Context) (keys []common.ApiKeyInfo, err error)
+	RevokeApiKey(ctx context.Context, apiKeyID int64) (err error)
+	ListOidcProviders(ctx context.Context) (providers []string)
+	OidcAuthorize(ctx context.Context, provider string) (authorization common.OidcAuthorization, err error)
+	OidcCallback(ctx context.Context, provider, state, stateBinding, code string) (token common.LoginToken,
+		err error)
+	Impersonate(ctx context.Context, userID int64, reason string) (token common.ImpersonationToken, err error)
+	SwitchOrganization(ctx context.Context, organizationID int64) (token common.LoginToken, err error)
+	IntrospectToken(ctx context.Context, token, tokenTypeHint string) (introspection common.TokenIntrospection,
+		err error)
+	RevokeToken(ctx context.Context, token, tokenTypeHint string) (err error)
 }
@@ -0,0 +1,532 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/auth.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	common "github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+)
+
+// MockAuthUseCase is a mock of AuthUseCase interface.
+type MockAuthUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthUseCaseMockRecorder
+}
+
+// MockAuthUseCaseMockRecorder is the mock recorder for MockAuthUseCase.
+type MockAuthUseCaseMockRecorder struct {
+	mock *MockAuthUseCase
+}
+
+// NewMockAuthUseCase creates a new mock instance.
+func NewMockAuthUseCase(ctrl *gomock.Controller) *MockAuthUseCase {
+	mock := &MockAuthUseCase{ctrl: ctrl}
+	mock.recorder = &MockAuthUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthUseCase) EXPECT() *MockAuthUseCaseMockRecorder {
+	return m.recorder
+}
+
+// ConfirmTotp mocks base method.
+func (m *MockAuthUseCase) ConfirmTotp(ctx context.Context, code string) (common.RecoveryCodes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTotp", ctx, code)
+	ret0, _ := ret[0].(common.RecoveryCodes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTotp indicates an expected call of ConfirmTotp.
+func (mr *MockAuthUseCaseMockRecorder) ConfirmTotp(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTotp", reflect.TypeOf((*MockAuthUseCase)(nil).ConfirmTotp), ctx, code)
+}
+
+// CreateApiKey mocks base method.
+func (m *MockAuthUseCase) CreateApiKey(ctx context.Context, request common.CreateApiKeyRequest) (common.CreatedApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApiKey", ctx, request)
+	ret0, _ := ret[0].(common.CreatedApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApiKey indicates an expected call of CreateApiKey.
+func (mr *MockAuthUseCaseMockRecorder) CreateApiKey(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApiKey", reflect.TypeOf((*MockAuthUseCase)(nil).CreateApiKey), ctx, request)
+}
+
+// DisableTotp mocks base method.
+func (m *MockAuthUseCase) DisableTotp(ctx context.Context, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTotp", ctx, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTotp indicates an expected call of DisableTotp.
+func (mr *MockAuthUseCaseMockRecorder) DisableTotp(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTotp", reflect.TypeOf((*MockAuthUseCase)(nil).DisableTotp), ctx, code)
+}
+
+// EnrollTotp mocks base method.
+func (m *MockAuthUseCase) EnrollTotp(ctx context.Context) (common.TotpEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTotp", ctx)
+	ret0, _ := ret[0].(common.TotpEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTotp indicates an expected call of EnrollTotp.
+func (mr *MockAuthUseCaseMockRecorder) EnrollTotp(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTotp", reflect.TypeOf((*MockAuthUseCase)(nil).EnrollTotp), ctx)
+}
+
+// FindReportedLogin mocks base method.
+func (m *MockAuthUseCase) FindReportedLogin(ctx context.Context, token string) (LoginRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReportedLogin", ctx, token)
+	ret0, _ := ret[0].(LoginRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReportedLogin indicates an expected call of FindReportedLogin.
+func (mr *MockAuthUseCaseMockRecorder) FindReportedLogin(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReportedLogin", reflect.TypeOf((*MockAuthUseCase)(nil).FindReportedLogin), ctx, token)
+}
+
+// ForgotPassword mocks base method.
+func (m *MockAuthUseCase) ForgotPassword(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockAuthUseCaseMockRecorder) ForgotPassword(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockAuthUseCase)(nil).ForgotPassword), ctx, email)
+}
+
+// Impersonate mocks base method.
+func (m *MockAuthUseCase) Impersonate(ctx context.Context, userID int64, reason string) (common.ImpersonationToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Impersonate", ctx, userID, reason)
+	ret0, _ := ret[0].(common.ImpersonationToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Impersonate indicates an expected call of Impersonate.
+func (mr *MockAuthUseCaseMockRecorder) Impersonate(ctx, userID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Impersonate", reflect.TypeOf((*MockAuthUseCase)(nil).Impersonate), ctx, userID, reason)
+}
+
+// Info mocks base method.
+func (m *MockAuthUseCase) Info(ctx context.Context) (common.LoginInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Info", ctx)
+	ret0, _ := ret[0].(common.LoginInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockAuthUseCaseMockRecorder) Info(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockAuthUseCase)(nil).Info), ctx)
+}
+
+// IntrospectToken mocks base method.
+func (m *MockAuthUseCase) IntrospectToken(ctx context.Context, token, tokenTypeHint string) (common.TokenIntrospection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IntrospectToken", ctx, token, tokenTypeHint)
+	ret0, _ := ret[0].(common.TokenIntrospection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IntrospectToken indicates an expected call of IntrospectToken.
+func (mr *MockAuthUseCaseMockRecorder) IntrospectToken(ctx, token, tokenTypeHint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IntrospectToken", reflect.TypeOf((*MockAuthUseCase)(nil).IntrospectToken), ctx, token, tokenTypeHint)
+}
+
+// ListApiKeys mocks base method.
+func (m *MockAuthUseCase) ListApiKeys(ctx context.Context) ([]common.ApiKeyInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApiKeys", ctx)
+	ret0, _ := ret[0].([]common.ApiKeyInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApiKeys indicates an expected call of ListApiKeys.
+func (mr *MockAuthUseCaseMockRecorder) ListApiKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApiKeys", reflect.TypeOf((*MockAuthUseCase)(nil).ListApiKeys), ctx)
+}
+
+// ListLoginHistory mocks base method.
+func (m *MockAuthUseCase) ListLoginHistory(ctx context.Context) ([]LoginRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLoginHistory", ctx)
+	ret0, _ := ret[0].([]LoginRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLoginHistory indicates an expected call of ListLoginHistory.
+func (mr *MockAuthUseCaseMockRecorder) ListLoginHistory(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoginHistory", reflect.TypeOf((*MockAuthUseCase)(nil).ListLoginHistory), ctx)
+}
+
+// ListOidcProviders mocks base method.
+func (m *MockAuthUseCase) ListOidcProviders(ctx context.Context) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOidcProviders", ctx)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// ListOidcProviders indicates an expected call of ListOidcProviders.
+func (mr *MockAuthUseCaseMockRecorder) ListOidcProviders(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOidcProviders", reflect.TypeOf((*MockAuthUseCase)(nil).ListOidcProviders), ctx)
+}
+
+// ListSessions mocks base method.
+func (m *MockAuthUseCase) ListSessions(ctx context.Context) ([]common.SessionInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", ctx)
+	ret0, _ := ret[0].([]common.SessionInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockAuthUseCaseMockRecorder) ListSessions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockAuthUseCase)(nil).ListSessions), ctx)
+}
+
+// Login mocks base method.
+func (m *MockAuthUseCase) Login(ctx context.Context, email, password string) (common.LoginToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, email, password)
+	ret0, _ := ret[0].(common.LoginToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockAuthUseCaseMockRecorder) Login(ctx, email, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthUseCase)(nil).Login), ctx, email, password)
+}
+
+// LoginMfa mocks base method.
+func (m *MockAuthUseCase) LoginMfa(ctx context.Context, mfaToken, code, recoveryCode string) (common.LoginToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginMfa", ctx, mfaToken, code, recoveryCode)
+	ret0, _ := ret[0].(common.LoginToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginMfa indicates an expected call of LoginMfa.
+func (mr *MockAuthUseCaseMockRecorder) LoginMfa(ctx, mfaToken, code, recoveryCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginMfa", reflect.TypeOf((*MockAuthUseCase)(nil).LoginMfa), ctx, mfaToken, code, recoveryCode)
+}
+
+// Logout mocks base method.
+func (m *MockAuthUseCase) Logout(ctx context.Context) (common.LogoutInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx)
+	ret0, _ := ret[0].(common.LogoutInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthUseCaseMockRecorder) Logout(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthUseCase)(nil).Logout), ctx)
+}
+
+// LogoutAll mocks base method.
+func (m *MockAuthUseCase) LogoutAll(ctx context.Context) (common.LogoutInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAll", ctx)
+	ret0, _ := ret[0].(common.LogoutInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LogoutAll indicates an expected call of LogoutAll.
+func (mr *MockAuthUseCaseMockRecorder) LogoutAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockAuthUseCase)(nil).LogoutAll), ctx)
+}
+
+// MagicLinkCallback mocks base method.
+func (m *MockAuthUseCase) MagicLinkCallback(ctx context.Context, token, nonce string) (common.LoginToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MagicLinkCallback", ctx, token, nonce)
+	ret0, _ := ret[0].(common.LoginToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MagicLinkCallback indicates an expected call of MagicLinkCallback.
+func (mr *MockAuthUseCaseMockRecorder) MagicLinkCallback(ctx, token, nonce interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MagicLinkCallback", reflect.TypeOf((*MockAuthUseCase)(nil).MagicLinkCallback), ctx, token, nonce)
+}
+
+// OidcAuthorize mocks base method.
+func (m *MockAuthUseCase) OidcAuthorize(ctx context.Context, provider string) (common.OidcAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OidcAuthorize", ctx, provider)
+	ret0, _ := ret[0].(common.OidcAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OidcAuthorize indicates an expected call of OidcAuthorize.
+func (mr *MockAuthUseCaseMockRecorder) OidcAuthorize(ctx, provider interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OidcAuthorize", reflect.TypeOf((*MockAuthUseCase)(nil).OidcAuthorize), ctx, provider)
+}
+
+// OidcCallback mocks base method.
+func (m *MockAuthUseCase) OidcCallback(ctx context.Context, provider, state, stateBinding, code string) (common.LoginToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OidcCallback", ctx, provider, state, stateBinding, code)
+	ret0, _ := ret[0].(common.LoginToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OidcCallback indicates an expected call of OidcCallback.
+func (mr *MockAuthUseCaseMockRecorder) OidcCallback(ctx, provider, state, stateBinding, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OidcCallback", reflect.TypeOf((*MockAuthUseCase)(nil).OidcCallback), ctx, provider, state, stateBinding, code)
+}
+
+// Reauthenticate mocks base method.
+func (m *MockAuthUseCase) Reauthenticate(ctx context.Context, password, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reauthenticate", ctx, password, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reauthenticate indicates an expected call of Reauthenticate.
+func (mr *MockAuthUseCaseMockRecorder) Reauthenticate(ctx, password, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reauthenticate", reflect.TypeOf((*MockAuthUseCase)(nil).Reauthenticate), ctx, password, code)
+}
+
+// Refresh mocks base method.
+func (m *MockAuthUseCase) Refresh(ctx context.Context, refreshToken string) (common.LoginToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, refreshToken)
+	ret0, _ := ret[0].(common.LoginToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockAuthUseCaseMockRecorder) Refresh(ctx, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockAuthUseCase)(nil).Refresh), ctx, refreshToken)
+}
+
+// Register mocks base method.
+func (m *MockAuthUseCase) Register(ctx context.Context, user User, invitationCode string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx, user, invitationCode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockAuthUseCaseMockRecorder) Register(ctx, user, invitationCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthUseCase)(nil).Register), ctx, user, invitationCode)
+}
+
+// ReportLogin mocks base method.
+func (m *MockAuthUseCase) ReportLogin(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportLogin", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReportLogin indicates an expected call of ReportLogin.
+func (mr *MockAuthUseCaseMockRecorder) ReportLogin(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportLogin", reflect.TypeOf((*MockAuthUseCase)(nil).ReportLogin), ctx, token)
+}
+
+// RequestMagicLink mocks base method.
+func (m *MockAuthUseCase) RequestMagicLink(ctx context.Context, email string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestMagicLink", ctx, email)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestMagicLink indicates an expected call of RequestMagicLink.
+func (mr *MockAuthUseCaseMockRecorder) RequestMagicLink(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestMagicLink", reflect.TypeOf((*MockAuthUseCase)(nil).RequestMagicLink), ctx, email)
+}
+
+// ResendVerificationEmail mocks base method.
+func (m *MockAuthUseCase) ResendVerificationEmail(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerificationEmail", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendVerificationEmail indicates an expected call of ResendVerificationEmail.
+func (mr *MockAuthUseCaseMockRecorder) ResendVerificationEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerificationEmail", reflect.TypeOf((*MockAuthUseCase)(nil).ResendVerificationEmail), ctx, email)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAuthUseCase) ResetPassword(ctx context.Context, token, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, token, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAuthUseCaseMockRecorder) ResetPassword(ctx, token, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthUseCase)(nil).ResetPassword), ctx, token, password)
+}
+
+// RevokeApiKey mocks base method.
+func (m *MockAuthUseCase) RevokeApiKey(ctx context.Context, apiKeyID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeApiKey", ctx, apiKeyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeApiKey indicates an expected call of RevokeApiKey.
+func (mr *MockAuthUseCaseMockRecorder) RevokeApiKey(ctx, apiKeyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeApiKey", reflect.TypeOf((*MockAuthUseCase)(nil).RevokeApiKey), ctx, apiKeyID)
+}
+
+// RevokeSession mocks base method.
+func (m *MockAuthUseCase) RevokeSession(ctx context.Context, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockAuthUseCaseMockRecorder) RevokeSession(ctx, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockAuthUseCase)(nil).RevokeSession), ctx, sessionID)
+}
+
+// RevokeToken mocks base method.
+func (m *MockAuthUseCase) RevokeToken(ctx context.Context, token, tokenTypeHint string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, token, tokenTypeHint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockAuthUseCaseMockRecorder) RevokeToken(ctx, token, tokenTypeHint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockAuthUseCase)(nil).RevokeToken), ctx, token, tokenTypeHint)
+}
+
+// SendEmail mocks base method.
+func (m *MockAuthUseCase) SendEmail(ctx context.Context, request common.LoginRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendEmail", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendEmail indicates an expected call of SendEmail.
+func (mr *MockAuthUseCaseMockRecorder) SendEmail(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendEmail", reflect.TypeOf((*MockAuthUseCase)(nil).SendEmail), ctx, request)
+}
+
+// SwitchOrganization mocks base method.
+func (m *MockAuthUseCase) SwitchOrganization(ctx context.Context, organizationID int64) (common.LoginToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SwitchOrganization", ctx, organizationID)
+	ret0, _ := ret[0].(common.LoginToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SwitchOrganization indicates an expected call of SwitchOrganization.
+func (mr *MockAuthUseCaseMockRecorder) SwitchOrganization(ctx, organizationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwitchOrganization", reflect.TypeOf((*MockAuthUseCase)(nil).SwitchOrganization), ctx, organizationID)
+}
+
+// UnlockAccount mocks base method.
+func (m *MockAuthUseCase) UnlockAccount(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockAccount", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockAccount indicates an expected call of UnlockAccount.
+func (mr *MockAuthUseCaseMockRecorder) UnlockAccount(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockAccount", reflect.TypeOf((*MockAuthUseCase)(nil).UnlockAccount), ctx, email)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockAuthUseCase) VerifyEmail(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockAuthUseCaseMockRecorder) VerifyEmail(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockAuthUseCase)(nil).VerifyEmail), ctx, token)
+}
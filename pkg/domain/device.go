@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// KnownDevice is a device a user has logged in from, recognized by the fingerprint of its user agent and client IP
+type KnownDevice struct {
+	ID          int64     `json:"id" gorm:"primaryKey"`
+	UserID      int64     `json:"user_id" gorm:"uniqueIndex:idx_known_devices_user_fingerprint;not null"`
+	Fingerprint string    `json:"-" gorm:"size:64;uniqueIndex:idx_known_devices_user_fingerprint;not null"`
+	UserAgent   string    `json:"user_agent" gorm:"size:512"`
+	ClientIP    string    `json:"client_ip" gorm:"size:45"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+// LoginRecord is an entry of the login history of a user
+type LoginRecord struct {
+	ID               int64     `json:"id" gorm:"primaryKey"`
+	UserID           int64     `json:"user_id" gorm:"index;not null"`
+	SessionID        string    `json:"session_id" gorm:"size:36;index"`
+	DeviceID         int64     `json:"device_id"`
+	UserAgent        string    `json:"user_agent" gorm:"size:512"`
+	ClientIP         string    `json:"client_ip" gorm:"size:45"`
+	Country          string    `json:"country" gorm:"size:2"` // ISO 3166-1 alpha-2 code, empty when unknown
+	NewDevice        bool      `json:"new_device"`
+	ImpossibleTravel bool      `json:"impossible_travel"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+//==================================================================================================
+// Repository
+//==================================================================================================
+
+type DeviceRepository interface {
+	FindKnownDevice(userID int64, fingerprint string) (device KnownDevice, err error)
+	CountKnownDevices(userID int64) (count int64, err error)
+	InsertKnownDevice(device *KnownDevice) (err error)
+	UpdateKnownDeviceLastSeenAt(id int64, lastSeenAt time.Time) (err error)
+	DeleteKnownDevice(userID, id int64) (err error)
+	InsertLoginRecord(record *LoginRecord) (err error)
+	FindLastLoginRecord(userID int64) (record LoginRecord, err error)
+	FindLoginRecordBySessionID(sessionID string) (record LoginRecord, err error)
+	FindLoginRecordsByUserID(userID int64, limit int) (records []LoginRecord, err error)
+}
@@ -0,0 +1,166 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/device.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDeviceRepository is a mock of DeviceRepository interface.
+type MockDeviceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceRepositoryMockRecorder
+}
+
+// MockDeviceRepositoryMockRecorder is the mock recorder for MockDeviceRepository.
+type MockDeviceRepositoryMockRecorder struct {
+	mock *MockDeviceRepository
+}
+
+// NewMockDeviceRepository creates a new mock instance.
+func NewMockDeviceRepository(ctrl *gomock.Controller) *MockDeviceRepository {
+	mock := &MockDeviceRepository{ctrl: ctrl}
+	mock.recorder = &MockDeviceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceRepository) EXPECT() *MockDeviceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountKnownDevices mocks base method.
+func (m *MockDeviceRepository) CountKnownDevices(userID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountKnownDevices", userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountKnownDevices indicates an expected call of CountKnownDevices.
+func (mr *MockDeviceRepositoryMockRecorder) CountKnownDevices(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountKnownDevices", reflect.TypeOf((*MockDeviceRepository)(nil).CountKnownDevices), userID)
+}
+
+// DeleteKnownDevice mocks base method.
+func (m *MockDeviceRepository) DeleteKnownDevice(userID, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteKnownDevice", userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteKnownDevice indicates an expected call of DeleteKnownDevice.
+func (mr *MockDeviceRepositoryMockRecorder) DeleteKnownDevice(userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKnownDevice", reflect.TypeOf((*MockDeviceRepository)(nil).DeleteKnownDevice), userID, id)
+}
+
+// FindKnownDevice mocks base method.
+func (m *MockDeviceRepository) FindKnownDevice(userID int64, fingerprint string) (KnownDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindKnownDevice", userID, fingerprint)
+	ret0, _ := ret[0].(KnownDevice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindKnownDevice indicates an expected call of FindKnownDevice.
+func (mr *MockDeviceRepositoryMockRecorder) FindKnownDevice(userID, fingerprint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindKnownDevice", reflect.TypeOf((*MockDeviceRepository)(nil).FindKnownDevice), userID, fingerprint)
+}
+
+// FindLastLoginRecord mocks base method.
+func (m *MockDeviceRepository) FindLastLoginRecord(userID int64) (LoginRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLastLoginRecord", userID)
+	ret0, _ := ret[0].(LoginRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLastLoginRecord indicates an expected call of FindLastLoginRecord.
+func (mr *MockDeviceRepositoryMockRecorder) FindLastLoginRecord(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLastLoginRecord", reflect.TypeOf((*MockDeviceRepository)(nil).FindLastLoginRecord), userID)
+}
+
+// FindLoginRecordBySessionID mocks base method.
+func (m *MockDeviceRepository) FindLoginRecordBySessionID(sessionID string) (LoginRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLoginRecordBySessionID", sessionID)
+	ret0, _ := ret[0].(LoginRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLoginRecordBySessionID indicates an expected call of FindLoginRecordBySessionID.
+func (mr *MockDeviceRepositoryMockRecorder) FindLoginRecordBySessionID(sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLoginRecordBySessionID", reflect.TypeOf((*MockDeviceRepository)(nil).FindLoginRecordBySessionID), sessionID)
+}
+
+// FindLoginRecordsByUserID mocks base method.
+func (m *MockDeviceRepository) FindLoginRecordsByUserID(userID int64, limit int) ([]LoginRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLoginRecordsByUserID", userID, limit)
+	ret0, _ := ret[0].([]LoginRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLoginRecordsByUserID indicates an expected call of FindLoginRecordsByUserID.
+func (mr *MockDeviceRepositoryMockRecorder) FindLoginRecordsByUserID(userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLoginRecordsByUserID", reflect.TypeOf((*MockDeviceRepository)(nil).FindLoginRecordsByUserID), userID, limit)
+}
+
+// InsertKnownDevice mocks base method.
+func (m *MockDeviceRepository) InsertKnownDevice(device *KnownDevice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertKnownDevice", device)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertKnownDevice indicates an expected call of InsertKnownDevice.
+func (mr *MockDeviceRepositoryMockRecorder) InsertKnownDevice(device interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertKnownDevice", reflect.TypeOf((*MockDeviceRepository)(nil).InsertKnownDevice), device)
+}
+
+// InsertLoginRecord mocks base method.
+func (m *MockDeviceRepository) InsertLoginRecord(record *LoginRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertLoginRecord", record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertLoginRecord indicates an expected call of InsertLoginRecord.
+func (mr *MockDeviceRepositoryMockRecorder) InsertLoginRecord(record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertLoginRecord", reflect.TypeOf((*MockDeviceRepository)(nil).InsertLoginRecord), record)
+}
+
+// UpdateKnownDeviceLastSeenAt mocks base method.
+func (m *MockDeviceRepository) UpdateKnownDeviceLastSeenAt(id int64, lastSeenAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateKnownDeviceLastSeenAt", id, lastSeenAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateKnownDeviceLastSeenAt indicates an expected call of UpdateKnownDeviceLastSeenAt.
+func (mr *MockDeviceRepositoryMockRecorder) UpdateKnownDeviceLastSeenAt(id, lastSeenAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKnownDeviceLastSeenAt", reflect.TypeOf((*MockDeviceRepository)(nil).UpdateKnownDeviceLastSeenAt), id, lastSeenAt)
+}
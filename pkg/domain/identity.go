@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// UserIdentity links a local user to its account at an external identity provider
+type UserIdentity struct {
+	ID        int64     `json:"id" gorm:"primaryKey"`
+	UserID    int64     `json:"user_id" gorm:"index;not null"`
+	Provider  string    `json:"provider" gorm:"size:50;uniqueIndex:idx_user_identities_provider_subject;not null"`
+	Subject   string    `json:"subject" gorm:"size:255;uniqueIndex:idx_user_identities_provider_subject;not null"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+//==================================================================================================
+// Repository
+//==================================================================================================
+
+type UserIdentityRepository interface {
+	InsertUserIdentity(identity UserIdentity) (err error)
+	FindUserIdentity(provider, subject string) (identity UserIdentity, err error)
+}
@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/identity.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUserIdentityRepository is a mock of UserIdentityRepository interface.
+type MockUserIdentityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserIdentityRepositoryMockRecorder
+}
+
+// MockUserIdentityRepositoryMockRecorder is the mock recorder for MockUserIdentityRepository.
+type MockUserIdentityRepositoryMockRecorder struct {
+	mock *MockUserIdentityRepository
+}
+
+// NewMockUserIdentityRepository creates a new mock instance.
+func NewMockUserIdentityRepository(ctrl *gomock.Controller) *MockUserIdentityRepository {
+	mock := &MockUserIdentityRepository{ctrl: ctrl}
+	mock.recorder = &MockUserIdentityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserIdentityRepository) EXPECT() *MockUserIdentityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindUserIdentity mocks base method.
+func (m *MockUserIdentityRepository) FindUserIdentity(provider, subject string) (UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserIdentity", provider, subject)
+	ret0, _ := ret[0].(UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserIdentity indicates an expected call of FindUserIdentity.
+func (mr *MockUserIdentityRepositoryMockRecorder) FindUserIdentity(provider, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserIdentity", reflect.TypeOf((*MockUserIdentityRepository)(nil).FindUserIdentity), provider, subject)
+}
+
+// InsertUserIdentity mocks base method.
+func (m *MockUserIdentityRepository) InsertUserIdentity(identity UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserIdentity", identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertUserIdentity indicates an expected call of InsertUserIdentity.
+func (mr *MockUserIdentityRepositoryMockRecorder) InsertUserIdentity(identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserIdentity", reflect.TypeOf((*MockUserIdentityRepository)(nil).InsertUserIdentity), identity)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"github.com/lactobasilusprotectus/go-template/pkg/invitation/common"
+	"time"
+)
+
+// Invitation lets its email register, even when registration is by invitation only. Only the hash of its code is
+// kept, the code itself is only sent to the email.
+type Invitation struct {
+	ID             int64      `json:"id" gorm:"primaryKey"`
+	Email          string     `json:"email" gorm:"size:255;index;not null"`
+	Role           string     `json:"role" gorm:"size:100"`         // granted on registration, none when empty
+	OrganizationID int64      `json:"organization_id" gorm:"index"` // joined on registration, none when 0
+	CodeHash       string     `json:"-" gorm:"size:64;uniqueIndex;not null"`
+	InvitedBy      int64      `json:"invited_by"`
+	SentAt         time.Time  `json:"sent_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+
+	Status string `json:"status" gorm:"-"` // one of the statuses of the invitation common package
+}
+
+//==================================================================================================
+// Use Case
+//==================================================================================================
+
+type InvitationUseCase interface {
+	CreateInvitation(ctx context.Context, request common.CreateInvitationRequest) (invitation Invitation, err error)
+	ListInvitations(ctx context.Context, organizationID int64) (invitations []Invitation, err error)
+	RevokeInvitation(ctx context.Context, id int64) (err error)
+	ResendInvitation(ctx context.Context, id int64) (err error)
+}
+
+//==================================================================================================
+// Repository
+//==================================================================================================
+
+type InvitationRepository interface {
+	InsertInvitation(invitation *Invitation) (err error)
+	FindInvitationByID(id int64) (invitation Invitation, err error)
+	FindInvitationByCodeHash(codeHash string) (invitation Invitation, err error)
+	FindPendingInvitationByEmail(email string, now time.Time) (invitation Invitation, err error)
+	FindInvitations(organizationID int64) (invitations []Invitation, err error)
+	UpdateInvitationCode(id int64, codeHash string, sentAt, expiresAt time.Time) (err error)
+	InsertInvitedUser(id int64, acceptedAt time.Time, user *User) (err error)
+	DeleteInvitation(id int64) (err error)
+}
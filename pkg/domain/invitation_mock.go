@@ -0,0 +1,234 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/invitation.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	common "github.com/lactobasilusprotectus/go-template/pkg/invitation/common"
+)
+
+// MockInvitationUseCase is a mock of InvitationUseCase interface.
+type MockInvitationUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvitationUseCaseMockRecorder
+}
+
+// MockInvitationUseCaseMockRecorder is the mock recorder for MockInvitationUseCase.
+type MockInvitationUseCaseMockRecorder struct {
+	mock *MockInvitationUseCase
+}
+
+// NewMockInvitationUseCase creates a new mock instance.
+func NewMockInvitationUseCase(ctrl *gomock.Controller) *MockInvitationUseCase {
+	mock := &MockInvitationUseCase{ctrl: ctrl}
+	mock.recorder = &MockInvitationUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvitationUseCase) EXPECT() *MockInvitationUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CreateInvitation mocks base method.
+func (m *MockInvitationUseCase) CreateInvitation(ctx context.Context, request common.CreateInvitationRequest) (Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvitation", ctx, request)
+	ret0, _ := ret[0].(Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInvitation indicates an expected call of CreateInvitation.
+func (mr *MockInvitationUseCaseMockRecorder) CreateInvitation(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvitation", reflect.TypeOf((*MockInvitationUseCase)(nil).CreateInvitation), ctx, request)
+}
+
+// ListInvitations mocks base method.
+func (m *MockInvitationUseCase) ListInvitations(ctx context.Context, organizationID int64) ([]Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInvitations", ctx, organizationID)
+	ret0, _ := ret[0].([]Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInvitations indicates an expected call of ListInvitations.
+func (mr *MockInvitationUseCaseMockRecorder) ListInvitations(ctx, organizationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvitations", reflect.TypeOf((*MockInvitationUseCase)(nil).ListInvitations), ctx, organizationID)
+}
+
+// ResendInvitation mocks base method.
+func (m *MockInvitationUseCase) ResendInvitation(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendInvitation", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendInvitation indicates an expected call of ResendInvitation.
+func (mr *MockInvitationUseCaseMockRecorder) ResendInvitation(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendInvitation", reflect.TypeOf((*MockInvitationUseCase)(nil).ResendInvitation), ctx, id)
+}
+
+// RevokeInvitation mocks base method.
+func (m *MockInvitationUseCase) RevokeInvitation(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeInvitation", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeInvitation indicates an expected call of RevokeInvitation.
+func (mr *MockInvitationUseCaseMockRecorder) RevokeInvitation(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeInvitation", reflect.TypeOf((*MockInvitationUseCase)(nil).RevokeInvitation), ctx, id)
+}
+
+// MockInvitationRepository is a mock of InvitationRepository interface.
+type MockInvitationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvitationRepositoryMockRecorder
+}
+
+// MockInvitationRepositoryMockRecorder is the mock recorder for MockInvitationRepository.
+type MockInvitationRepositoryMockRecorder struct {
+	mock *MockInvitationRepository
+}
+
+// NewMockInvitationRepository creates a new mock instance.
+func NewMockInvitationRepository(ctrl *gomock.Controller) *MockInvitationRepository {
+	mock := &MockInvitationRepository{ctrl: ctrl}
+	mock.recorder = &MockInvitationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvitationRepository) EXPECT() *MockInvitationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteInvitation mocks base method.
+func (m *MockInvitationRepository) DeleteInvitation(id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInvitation", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInvitation indicates an expected call of DeleteInvitation.
+func (mr *MockInvitationRepositoryMockRecorder) DeleteInvitation(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInvitation", reflect.TypeOf((*MockInvitationRepository)(nil).DeleteInvitation), id)
+}
+
+// FindInvitationByCodeHash mocks base method.
+func (m *MockInvitationRepository) FindInvitationByCodeHash(codeHash string) (Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInvitationByCodeHash", codeHash)
+	ret0, _ := ret[0].(Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindInvitationByCodeHash indicates an expected call of FindInvitationByCodeHash.
+func (mr *MockInvitationRepositoryMockRecorder) FindInvitationByCodeHash(codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInvitationByCodeHash", reflect.TypeOf((*MockInvitationRepository)(nil).FindInvitationByCodeHash), codeHash)
+}
+
+// FindInvitationByID mocks base method.
+func (m *MockInvitationRepository) FindInvitationByID(id int64) (Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInvitationByID", id)
+	ret0, _ := ret[0].(Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindInvitationByID indicates an expected call of FindInvitationByID.
+func (mr *MockInvitationRepositoryMockRecorder) FindInvitationByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInvitationByID", reflect.TypeOf((*MockInvitationRepository)(nil).FindInvitationByID), id)
+}
+
+// FindInvitations mocks base method.
+func (m *MockInvitationRepository) FindInvitations(organizationID int64) ([]Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInvitations", organizationID)
+	ret0, _ := ret[0].([]Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindInvitations indicates an expected call of FindInvitations.
+func (mr *MockInvitationRepositoryMockRecorder) FindInvitations(organizationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInvitations", reflect.TypeOf((*MockInvitationRepository)(nil).FindInvitations), organizationID)
+}
+
+// FindPendingInvitationByEmail mocks base method.
+func (m *MockInvitationRepository) FindPendingInvitationByEmail(email string, now time.Time) (Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingInvitationByEmail", email, now)
+	ret0, _ := ret[0].(Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingInvitationByEmail indicates an expected call of FindPendingInvitationByEmail.
+func (mr *MockInvitationRepositoryMockRecorder) FindPendingInvitationByEmail(email, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingInvitationByEmail", reflect.TypeOf((*MockInvitationRepository)(nil).FindPendingInvitationByEmail), email, now)
+}
+
+// InsertInvitation mocks base method.
+func (m *MockInvitationRepository) InsertInvitation(invitation *Invitation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertInvitation", invitation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertInvitation indicates an expected call of InsertInvitation.
+func (mr *MockInvitationRepositoryMockRecorder) InsertInvitation(invitation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertInvitation", reflect.TypeOf((*MockInvitationRepository)(nil).InsertInvitation), invitation)
+}
+
+// InsertInvitedUser mocks base method.
+func (m *MockInvitationRepository) InsertInvitedUser(id int64, acceptedAt time.Time, user *User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertInvitedUser", id, acceptedAt, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertInvitedUser indicates an expected call of InsertInvitedUser.
+func (mr *MockInvitationRepositoryMockRecorder) InsertInvitedUser(id, acceptedAt, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertInvitedUser", reflect.TypeOf((*MockInvitationRepository)(nil).InsertInvitedUser), id, acceptedAt, user)
+}
+
+// UpdateInvitationCode mocks base method.
+func (m *MockInvitationRepository) UpdateInvitationCode(id int64, codeHash string, sentAt, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInvitationCode", id, codeHash, sentAt, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateInvitationCode indicates an expected call of UpdateInvitationCode.
+func (mr *MockInvitationRepositoryMockRecorder) UpdateInvitationCode(id, codeHash, sentAt, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInvitationCode", reflect.TypeOf((*MockInvitationRepository)(nil).UpdateInvitationCode), id, codeHash, sentAt, expiresAt)
+}
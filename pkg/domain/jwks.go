@@ -0,0 +1,11 @@
+package domain
+
+import (
+	"context"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+)
+
+type JwksUseCase interface {
+	GetJwks(ctx context.Context) jwt.JSONWebKeySet
+	RotateKeys(ctx context.Context) error
+}
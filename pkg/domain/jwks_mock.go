@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/jwks.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+)
+
+// MockJwksUseCase is a mock of JwksUseCase interface.
+type MockJwksUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockJwksUseCaseMockRecorder
+}
+
+// MockJwksUseCaseMockRecorder is the mock recorder for MockJwksUseCase.
+type MockJwksUseCaseMockRecorder struct {
+	mock *MockJwksUseCase
+}
+
+// NewMockJwksUseCase creates a new mock instance.
+func NewMockJwksUseCase(ctrl *gomock.Controller) *MockJwksUseCase {
+	mock := &MockJwksUseCase{ctrl: ctrl}
+	mock.recorder = &MockJwksUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJwksUseCase) EXPECT() *MockJwksUseCaseMockRecorder {
+	return m.recorder
+}
+
+// GetJwks mocks base method.
+func (m *MockJwksUseCase) GetJwks(ctx context.Context) jwt.JSONWebKeySet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJwks", ctx)
+	ret0, _ := ret[0].(jwt.JSONWebKeySet)
+	return ret0
+}
+
+// GetJwks indicates an expected call of GetJwks.
+func (mr *MockJwksUseCaseMockRecorder) GetJwks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJwks", reflect.TypeOf((*MockJwksUseCase)(nil).GetJwks), ctx)
+}
+
+// RotateKeys mocks base method.
+func (m *MockJwksUseCase) RotateKeys(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateKeys", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateKeys indicates an expected call of RotateKeys.
+func (mr *MockJwksUseCaseMockRecorder) RotateKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateKeys", reflect.TypeOf((*MockJwksUseCase)(nil).RotateKeys), ctx)
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// RecoveryCode is a one time code replacing the second factor when the authenticator is lost
+type RecoveryCode struct {
+	ID        int64      `json:"id" gorm:"primaryKey"`
+	UserID    int64      `json:"user_id" gorm:"index;not null"`
+	CodeHash  string     `json:"-" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+//==================================================================================================
+// Repository
+//==================================================================================================
+
+type RecoveryCodeRepository interface {
+	InsertRecoveryCodes(codes []RecoveryCode) (err error)
+	UseRecoveryCode(userID int64, codeHash string, usedAt time.Time) (used bool, err error)
+	DeleteRecoveryCodesByUserID(userID int64) (err error)
+}
@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/mfa.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRecoveryCodeRepository is a mock of RecoveryCodeRepository interface.
+type MockRecoveryCodeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecoveryCodeRepositoryMockRecorder
+}
+
+// MockRecoveryCodeRepositoryMockRecorder is the mock recorder for MockRecoveryCodeRepository.
+type MockRecoveryCodeRepositoryMockRecorder struct {
+	mock *MockRecoveryCodeRepository
+}
+
+// NewMockRecoveryCodeRepository creates a new mock instance.
+func NewMockRecoveryCodeRepository(ctrl *gomock.Controller) *MockRecoveryCodeRepository {
+	mock := &MockRecoveryCodeRepository{ctrl: ctrl}
+	mock.recorder = &MockRecoveryCodeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecoveryCodeRepository) EXPECT() *MockRecoveryCodeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteRecoveryCodesByUserID mocks base method.
+func (m *MockRecoveryCodeRepository) DeleteRecoveryCodesByUserID(userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodesByUserID", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodesByUserID indicates an expected call of DeleteRecoveryCodesByUserID.
+func (mr *MockRecoveryCodeRepositoryMockRecorder) DeleteRecoveryCodesByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodesByUserID", reflect.TypeOf((*MockRecoveryCodeRepository)(nil).DeleteRecoveryCodesByUserID), userID)
+}
+
+// InsertRecoveryCodes mocks base method.
+func (m *MockRecoveryCodeRepository) InsertRecoveryCodes(codes []RecoveryCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertRecoveryCodes", codes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertRecoveryCodes indicates an expected call of InsertRecoveryCodes.
+func (mr *MockRecoveryCodeRepositoryMockRecorder) InsertRecoveryCodes(codes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertRecoveryCodes", reflect.TypeOf((*MockRecoveryCodeRepository)(nil).InsertRecoveryCodes), codes)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockRecoveryCodeRepository) UseRecoveryCode(userID int64, codeHash string, usedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", userID, codeHash, usedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockRecoveryCodeRepositoryMockRecorder) UseRecoveryCode(userID, codeHash, usedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockRecoveryCodeRepository)(nil).UseRecoveryCode), userID, codeHash, usedAt)
+}
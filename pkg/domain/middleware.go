@@ -9,4 +9,13 @@ type GinAuthentication interface {
 	// JWT authentication
 	MustLogin() gin.HandlerFunc
 	GetUserIDFromCtx(ctx context.Context) (userID int64, ok bool)
+
+	// Authorization, must be chained after MustLogin
+	RequireRole(roles ...string) gin.HandlerFunc
+	RequirePermission(permissions ...string) gin.HandlerFunc
+}
+
+type OAuthAuthentication interface {
+	// OAuth access token authentication, the token must hold every given scope
+	RequireScope(scopes ...string) gin.HandlerFunc
 }
@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/middleware.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockGinAuthentication is a mock of GinAuthentication interface.
+type MockGinAuthentication struct {
+	ctrl     *gomock.Controller
+	recorder *MockGinAuthenticationMockRecorder
+}
+
+// MockGinAuthenticationMockRecorder is the mock recorder for MockGinAuthentication.
+type MockGinAuthenticationMockRecorder struct {
+	mock *MockGinAuthentication
+}
+
+// NewMockGinAuthentication creates a new mock instance.
+func NewMockGinAuthentication(ctrl *gomock.Controller) *MockGinAuthentication {
+	mock := &MockGinAuthentication{ctrl: ctrl}
+	mock.recorder = &MockGinAuthenticationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGinAuthentication) EXPECT() *MockGinAuthenticationMockRecorder {
+	return m.recorder
+}
+
+// GetUserIDFromCtx mocks base method.
+func (m *MockGinAuthentication) GetUserIDFromCtx(ctx context.Context) (int64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIDFromCtx", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetUserIDFromCtx indicates an expected call of GetUserIDFromCtx.
+func (mr *MockGinAuthenticationMockRecorder) GetUserIDFromCtx(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIDFromCtx", reflect.TypeOf((*MockGinAuthentication)(nil).GetUserIDFromCtx), ctx)
+}
+
+// MustLogin mocks base method.
+func (m *MockGinAuthentication) MustLogin() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MustLogin")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// MustLogin indicates an expected call of MustLogin.
+func (mr *MockGinAuthenticationMockRecorder) MustLogin() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MustLogin", reflect.TypeOf((*MockGinAuthentication)(nil).MustLogin))
+}
+
+// RequirePermission mocks base method.
+func (m *MockGinAuthentication) RequirePermission(permissions ...string) gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range permissions {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RequirePermission", varargs...)
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// RequirePermission indicates an expected call of RequirePermission.
+func (mr *MockGinAuthenticationMockRecorder) RequirePermission(permissions ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequirePermission", reflect.TypeOf((*MockGinAuthentication)(nil).RequirePermission), permissions...)
+}
+
+// RequireRole mocks base method.
+func (m *MockGinAuthentication) RequireRole(roles ...string) gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range roles {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RequireRole", varargs...)
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// RequireRole indicates an expected call of RequireRole.
+func (mr *MockGinAuthenticationMockRecorder) RequireRole(roles ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequireRole", reflect.TypeOf((*MockGinAuthentication)(nil).RequireRole), roles...)
+}
+
+// MockOAuthAuthentication is a mock of OAuthAuthentication interface.
+type MockOAuthAuthentication struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthAuthenticationMockRecorder
+}
+
+// MockOAuthAuthenticationMockRecorder is the mock recorder for MockOAuthAuthentication.
+type MockOAuthAuthenticationMockRecorder struct {
+	mock *MockOAuthAuthentication
+}
+
+// NewMockOAuthAuthentication creates a new mock instance.
+func NewMockOAuthAuthentication(ctrl *gomock.Controller) *MockOAuthAuthentication {
+	mock := &MockOAuthAuthentication{ctrl: ctrl}
+	mock.recorder = &MockOAuthAuthenticationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthAuthentication) EXPECT() *MockOAuthAuthenticationMockRecorder {
+	return m.recorder
+}
+
+// RequireScope mocks base method.
+func (m *MockOAuthAuthentication) RequireScope(scopes ...string) gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range scopes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RequireScope", varargs...)
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// RequireScope indicates an expected call of RequireScope.
+func (mr *MockOAuthAuthenticationMockRecorder) RequireScope(scopes ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequireScope", reflect.TypeOf((*MockOAuthAuthentication)(nil).RequireScope), scopes...)
+}
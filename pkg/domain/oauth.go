@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"github.com/lactobasilusprotectus/go-template/pkg/oauth/common"
+	"time"
+)
+
+// OAuthClient is an application registered to obtain tokens from the oauth server
+type OAuthClient struct {
+	ID            string    `json:"id" gorm:"primaryKey;size:64"`
+	Name          string    `json:"name" gorm:"size:100;not null"`
+	SecretHash    string    `json:"-"`             // empty for public clients
+	RedirectURIs  string    `json:"redirect_uris"` // space separated
+	Scopes        string    `json:"scopes"`        // space separated scopes the client may request
+	GrantTypes    string    `json:"grant_types"`   // space separated grant types the client may use
+	Confidential  bool      `json:"confidential"`  // public clients can't keep a secret and must use PKCE
+	Introspection bool      `json:"introspection"` // may inspect and revoke any token, first-party ones too
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// OAuthConsent remembers the scopes a user already granted to a client
+type OAuthConsent struct {
+	UserID    int64     `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	ClientID  string    `json:"client_id" gorm:"primaryKey;size:64"`
+	Scopes    string    `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+//==================================================================================================
+// Use Case
+//==================================================================================================
+
+type OAuthUseCase interface {
+	CreateClient(ctx context.Context, request common.CreateClientRequest) (client common.CreatedClient, err error)
+	ListClients(ctx context.Context) (clients []common.ClientInfo, err error)
+	DeleteClient(ctx context.Context, clientID string) (err error)
+	Authorize(ctx context.Context, request common.AuthorizeRequest) (info common.AuthorizeInfo, err error)
+	Consent(ctx context.Context, request common.ConsentRequest) (info common.AuthorizeInfo, err error)
+	Token(ctx context.Context, request common.TokenRequest) (token common.TokenResponse, err error)
+	Introspect(ctx context.Context, request common.IntrospectRequest) (response common.IntrospectResponse, err error)
+	Revoke(ctx context.Context, request common.IntrospectRequest) (err error)
+	UserInfo(ctx context.Context) (info common.UserInfo, err error)
+}
+
+//==================================================================================================
+// Repository
+//==================================================================================================
+
+type OAuthRepository interface {
+	InsertClient(client OAuthClient) (err error)
+	FindClientByID(id string) (client OAuthClient, err error)
+	FindClients() (clients []OAuthClient, err error)
+	DeleteClient(id string) (err error)
+	FindConsent(userID int64, clientID string) (consent OAuthConsent, err error)
+	SaveConsent(consent OAuthConsent) (err error)
+}
@@ -0,0 +1,279 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/oauth.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	common "github.com/lactobasilusprotectus/go-template/pkg/oauth/common"
+)
+
+// MockOAuthUseCase is a mock of OAuthUseCase interface.
+type MockOAuthUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthUseCaseMockRecorder
+}
+
+// MockOAuthUseCaseMockRecorder is the mock recorder for MockOAuthUseCase.
+type MockOAuthUseCaseMockRecorder struct {
+	mock *MockOAuthUseCase
+}
+
+// NewMockOAuthUseCase creates a new mock instance.
+func NewMockOAuthUseCase(ctrl *gomock.Controller) *MockOAuthUseCase {
+	mock := &MockOAuthUseCase{ctrl: ctrl}
+	mock.recorder = &MockOAuthUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthUseCase) EXPECT() *MockOAuthUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Authorize mocks base method.
+func (m *MockOAuthUseCase) Authorize(ctx context.Context, request common.AuthorizeRequest) (common.AuthorizeInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, request)
+	ret0, _ := ret[0].(common.AuthorizeInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockOAuthUseCaseMockRecorder) Authorize(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockOAuthUseCase)(nil).Authorize), ctx, request)
+}
+
+// Consent mocks base method.
+func (m *MockOAuthUseCase) Consent(ctx context.Context, request common.ConsentRequest) (common.AuthorizeInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consent", ctx, request)
+	ret0, _ := ret[0].(common.AuthorizeInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consent indicates an expected call of Consent.
+func (mr *MockOAuthUseCaseMockRecorder) Consent(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consent", reflect.TypeOf((*MockOAuthUseCase)(nil).Consent), ctx, request)
+}
+
+// CreateClient mocks base method.
+func (m *MockOAuthUseCase) CreateClient(ctx context.Context, request common.CreateClientRequest) (common.CreatedClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClient", ctx, request)
+	ret0, _ := ret[0].(common.CreatedClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateClient indicates an expected call of CreateClient.
+func (mr *MockOAuthUseCaseMockRecorder) CreateClient(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClient", reflect.TypeOf((*MockOAuthUseCase)(nil).CreateClient), ctx, request)
+}
+
+// DeleteClient mocks base method.
+func (m *MockOAuthUseCase) DeleteClient(ctx context.Context, clientID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClient", ctx, clientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteClient indicates an expected call of DeleteClient.
+func (mr *MockOAuthUseCaseMockRecorder) DeleteClient(ctx, clientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClient", reflect.TypeOf((*MockOAuthUseCase)(nil).DeleteClient), ctx, clientID)
+}
+
+// Introspect mocks base method.
+func (m *MockOAuthUseCase) Introspect(ctx context.Context, request common.IntrospectRequest) (common.IntrospectResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Introspect", ctx, request)
+	ret0, _ := ret[0].(common.IntrospectResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Introspect indicates an expected call of Introspect.
+func (mr *MockOAuthUseCaseMockRecorder) Introspect(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Introspect", reflect.TypeOf((*MockOAuthUseCase)(nil).Introspect), ctx, request)
+}
+
+// ListClients mocks base method.
+func (m *MockOAuthUseCase) ListClients(ctx context.Context) ([]common.ClientInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClients", ctx)
+	ret0, _ := ret[0].([]common.ClientInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClients indicates an expected call of ListClients.
+func (mr *MockOAuthUseCaseMockRecorder) ListClients(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClients", reflect.TypeOf((*MockOAuthUseCase)(nil).ListClients), ctx)
+}
+
+// Revoke mocks base method.
+func (m *MockOAuthUseCase) Revoke(ctx context.Context, request common.IntrospectRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockOAuthUseCaseMockRecorder) Revoke(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockOAuthUseCase)(nil).Revoke), ctx, request)
+}
+
+// Token mocks base method.
+func (m *MockOAuthUseCase) Token(ctx context.Context, request common.TokenRequest) (common.TokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token", ctx, request)
+	ret0, _ := ret[0].(common.TokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockOAuthUseCaseMockRecorder) Token(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockOAuthUseCase)(nil).Token), ctx, request)
+}
+
+// UserInfo mocks base method.
+func (m *MockOAuthUseCase) UserInfo(ctx context.Context) (common.UserInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserInfo", ctx)
+	ret0, _ := ret[0].(common.UserInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserInfo indicates an expected call of UserInfo.
+func (mr *MockOAuthUseCaseMockRecorder) UserInfo(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserInfo", reflect.TypeOf((*MockOAuthUseCase)(nil).UserInfo), ctx)
+}
+
+// MockOAuthRepository is a mock of OAuthRepository interface.
+type MockOAuthRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthRepositoryMockRecorder
+}
+
+// MockOAuthRepositoryMockRecorder is the mock recorder for MockOAuthRepository.
+type MockOAuthRepositoryMockRecorder struct {
+	mock *MockOAuthRepository
+}
+
+// NewMockOAuthRepository creates a new mock instance.
+func NewMockOAuthRepository(ctrl *gomock.Controller) *MockOAuthRepository {
+	mock := &MockOAuthRepository{ctrl: ctrl}
+	mock.recorder = &MockOAuthRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthRepository) EXPECT() *MockOAuthRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteClient mocks base method.
+func (m *MockOAuthRepository) DeleteClient(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClient", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteClient indicates an expected call of DeleteClient.
+func (mr *MockOAuthRepositoryMockRecorder) DeleteClient(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClient", reflect.TypeOf((*MockOAuthRepository)(nil).DeleteClient), id)
+}
+
+// FindClientByID mocks base method.
+func (m *MockOAuthRepository) FindClientByID(id string) (OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindClientByID", id)
+	ret0, _ := ret[0].(OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindClientByID indicates an expected call of FindClientByID.
+func (mr *MockOAuthRepositoryMockRecorder) FindClientByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindClientByID", reflect.TypeOf((*MockOAuthRepository)(nil).FindClientByID), id)
+}
+
+// FindClients mocks base method.
+func (m *MockOAuthRepository) FindClients() ([]OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindClients")
+	ret0, _ := ret[0].([]OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindClients indicates an expected call of FindClients.
+func (mr *MockOAuthRepositoryMockRecorder) FindClients() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindClients", reflect.TypeOf((*MockOAuthRepository)(nil).FindClients))
+}
+
+// FindConsent mocks base method.
+func (m *MockOAuthRepository) FindConsent(userID int64, clientID string) (OAuthConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindConsent", userID, clientID)
+	ret0, _ := ret[0].(OAuthConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindConsent indicates an expected call of FindConsent.
+func (mr *MockOAuthRepositoryMockRecorder) FindConsent(userID, clientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindConsent", reflect.TypeOf((*MockOAuthRepository)(nil).FindConsent), userID, clientID)
+}
+
+// InsertClient mocks base method.
+func (m *MockOAuthRepository) InsertClient(client OAuthClient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertClient", client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertClient indicates an expected call of InsertClient.
+func (mr *MockOAuthRepositoryMockRecorder) InsertClient(client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertClient", reflect.TypeOf((*MockOAuthRepository)(nil).InsertClient), client)
+}
+
+// SaveConsent mocks base method.
+func (m *MockOAuthRepository) SaveConsent(consent OAuthConsent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveConsent", consent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveConsent indicates an expected call of SaveConsent.
+func (mr *MockOAuthRepositoryMockRecorder) SaveConsent(consent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveConsent", reflect.TypeOf((*MockOAuthRepository)(nil).SaveConsent), consent)
+}
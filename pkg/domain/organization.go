@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"github.com/lactobasilusprotectus/go-template/pkg/organization/common"
+	"time"
+)
+
+// Organization is a tenant, the data of one organization is never reachable from another
+type Organization struct {
+	ID        int64     `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"size:100;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrganizationMember is the membership of a user in an organization, a user can be a member of several
+type OrganizationMember struct {
+	OrganizationID int64     `json:"organization_id" gorm:"primaryKey;autoIncrement:false"`
+	UserID         int64     `json:"user_id" gorm:"primaryKey;autoIncrement:false;index"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Membership is an organization along with when the user joined it
+type Membership struct {
+	Organization
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+//==================================================================================================
+// Use Case
+//==================================================================================================
+
+type OrganizationUseCase interface {
+	CreateOrganization(ctx context.Context, request common.CreateOrganizationRequest) (organization Organization,
+		err error)
+	ListOrganizations(ctx context.Context) (organizations []common.OrganizationInfo, err error)
+	AddMember(ctx context.Context, organizationID, userID int64) (err error)
+	RemoveMember(ctx context.Context, organizationID, userID int64) (err error)
+}
+
+//==================================================================================================
+// Repository
+//==================================================================================================
+
+type OrganizationRepository interface {
+	InsertOrganization(organization *Organization, ownerID int64) (err error)
+	FindOrganizationByID(id int64) (organization Organization, err error)
+	FindMembershipsByUserID(userID int64) (memberships []Membership, err error)
+	IsMember(organizationID, userID int64) (member bool, err error)
+	InsertMember(organizationID, userID int64) (err error)
+	DeleteMember(organizationID, userID int64) (err error)
+}
@@ -0,0 +1,204 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/organization.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	common "github.com/lactobasilusprotectus/go-template/pkg/organization/common"
+)
+
+// MockOrganizationUseCase is a mock of OrganizationUseCase interface.
+type MockOrganizationUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrganizationUseCaseMockRecorder
+}
+
+// MockOrganizationUseCaseMockRecorder is the mock recorder for MockOrganizationUseCase.
+type MockOrganizationUseCaseMockRecorder struct {
+	mock *MockOrganizationUseCase
+}
+
+// NewMockOrganizationUseCase creates a new mock instance.
+func NewMockOrganizationUseCase(ctrl *gomock.Controller) *MockOrganizationUseCase {
+	mock := &MockOrganizationUseCase{ctrl: ctrl}
+	mock.recorder = &MockOrganizationUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrganizationUseCase) EXPECT() *MockOrganizationUseCaseMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockOrganizationUseCase) AddMember(ctx context.Context, organizationID, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, organizationID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockOrganizationUseCaseMockRecorder) AddMember(ctx, organizationID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockOrganizationUseCase)(nil).AddMember), ctx, organizationID, userID)
+}
+
+// CreateOrganization mocks base method.
+func (m *MockOrganizationUseCase) CreateOrganization(ctx context.Context, request common.CreateOrganizationRequest) (Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrganization", ctx, request)
+	ret0, _ := ret[0].(Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockOrganizationUseCaseMockRecorder) CreateOrganization(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockOrganizationUseCase)(nil).CreateOrganization), ctx, request)
+}
+
+// ListOrganizations mocks base method.
+func (m *MockOrganizationUseCase) ListOrganizations(ctx context.Context) ([]common.OrganizationInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrganizations", ctx)
+	ret0, _ := ret[0].([]common.OrganizationInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrganizations indicates an expected call of ListOrganizations.
+func (mr *MockOrganizationUseCaseMockRecorder) ListOrganizations(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrganizations", reflect.TypeOf((*MockOrganizationUseCase)(nil).ListOrganizations), ctx)
+}
+
+// RemoveMember mocks base method.
+func (m *MockOrganizationUseCase) RemoveMember(ctx context.Context, organizationID, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, organizationID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockOrganizationUseCaseMockRecorder) RemoveMember(ctx, organizationID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockOrganizationUseCase)(nil).RemoveMember), ctx, organizationID, userID)
+}
+
+// MockOrganizationRepository is a mock of OrganizationRepository interface.
+type MockOrganizationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrganizationRepositoryMockRecorder
+}
+
+// MockOrganizationRepositoryMockRecorder is the mock recorder for MockOrganizationRepository.
+type MockOrganizationRepositoryMockRecorder struct {
+	mock *MockOrganizationRepository
+}
+
+// NewMockOrganizationRepository creates a new mock instance.
+func NewMockOrganizationRepository(ctrl *gomock.Controller) *MockOrganizationRepository {
+	mock := &MockOrganizationRepository{ctrl: ctrl}
+	mock.recorder = &MockOrganizationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrganizationRepository) EXPECT() *MockOrganizationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteMember mocks base method.
+func (m *MockOrganizationRepository) DeleteMember(organizationID, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMember", organizationID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMember indicates an expected call of DeleteMember.
+func (mr *MockOrganizationRepositoryMockRecorder) DeleteMember(organizationID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMember", reflect.TypeOf((*MockOrganizationRepository)(nil).DeleteMember), organizationID, userID)
+}
+
+// FindMembershipsByUserID mocks base method.
+func (m *MockOrganizationRepository) FindMembershipsByUserID(userID int64) ([]Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMembershipsByUserID", userID)
+	ret0, _ := ret[0].([]Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMembershipsByUserID indicates an expected call of FindMembershipsByUserID.
+func (mr *MockOrganizationRepositoryMockRecorder) FindMembershipsByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMembershipsByUserID", reflect.TypeOf((*MockOrganizationRepository)(nil).FindMembershipsByUserID), userID)
+}
+
+// FindOrganizationByID mocks base method.
+func (m *MockOrganizationRepository) FindOrganizationByID(id int64) (Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrganizationByID", id)
+	ret0, _ := ret[0].(Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrganizationByID indicates an expected call of FindOrganizationByID.
+func (mr *MockOrganizationRepositoryMockRecorder) FindOrganizationByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrganizationByID", reflect.TypeOf((*MockOrganizationRepository)(nil).FindOrganizationByID), id)
+}
+
+// InsertMember mocks base method.
+func (m *MockOrganizationRepository) InsertMember(organizationID, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertMember", organizationID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertMember indicates an expected call of InsertMember.
+func (mr *MockOrganizationRepositoryMockRecorder) InsertMember(organizationID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMember", reflect.TypeOf((*MockOrganizationRepository)(nil).InsertMember), organizationID, userID)
+}
+
+// InsertOrganization mocks base method.
+func (m *MockOrganizationRepository) InsertOrganization(organization *Organization, ownerID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOrganization", organization, ownerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertOrganization indicates an expected call of InsertOrganization.
+func (mr *MockOrganizationRepositoryMockRecorder) InsertOrganization(organization, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOrganization", reflect.TypeOf((*MockOrganizationRepository)(nil).InsertOrganization), organization, ownerID)
+}
+
+// IsMember mocks base method.
+func (m *MockOrganizationRepository) IsMember(organizationID, userID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsMember", organizationID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsMember indicates an expected call of IsMember.
+func (mr *MockOrganizationRepositoryMockRecorder) IsMember(organizationID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMember", reflect.TypeOf((*MockOrganizationRepository)(nil).IsMember), organizationID, userID)
+}
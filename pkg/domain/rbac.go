@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Role is a set of permissions granted platform-wide, roles aren't scoped to organizations. They are only assigned
+// outside any organization, so an admin within one can't grant access to the others.
+type Role struct {
+	ID          int64        `json:"id" gorm:"primaryKey"`
+	Name        string       `json:"name" gorm:"uniqueIndex;not null"`
+	Description string       `json:"description"`
+	Permissions []Permission `json:"permissions" gorm:"many2many:role_permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type Permission struct {
+	ID          int64  `json:"id" gorm:"primaryKey"`
+	Name        string `json:"name" gorm:"uniqueIndex;not null"`
+	Description string `json:"description"`
+}
+
+type UserRole struct {
+	UserID    int64     `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	RoleID    int64     `json:"role_id" gorm:"primaryKey;autoIncrement:false"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+//==================================================================================================
+// Use Case
+//==================================================================================================
+
+type RbacUseCase interface {
+	ListRoles(ctx context.Context) (roles []Role, err error)
+	ListUserRoles(ctx context.Context, userID int64) (roles []Role, err error)
+	AssignRole(ctx context.Context, userID int64, roleName string) (err error)
+	RevokeRole(ctx context.Context, userID int64, roleName string) (err error)
+}
+
+//==================================================================================================
+// Repository
+//==================================================================================================
+
+type RbacRepository interface {
+	EnsureRole(name, description string, permissions []string) (Role, error)
+	FindRoles() ([]Role, error)
+	FindRoleByName(name string) (Role, error)
+	FindRolesByUserID(userID int64) ([]Role, error)
+	FindPermissionsByUserID(userID int64) ([]string, error)
+	AssignUserRole(userID, roleID int64) (err error)
+	RevokeUserRole(userID, roleID int64) (err error)
+}
@@ -0,0 +1,219 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/domain/rbac.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRbacUseCase is a mock of RbacUseCase interface.
+type MockRbacUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockRbacUseCaseMockRecorder
+}
+
+// MockRbacUseCaseMockRecorder is the mock recorder for MockRbacUseCase.
+type MockRbacUseCaseMockRecorder struct {
+	mock *MockRbacUseCase
+}
+
+// NewMockRbacUseCase creates a new mock instance.
+func NewMockRbacUseCase(ctrl *gomock.Controller) *MockRbacUseCase {
+	mock := &MockRbacUseCase{ctrl: ctrl}
+	mock.recorder = &MockRbacUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRbacUseCase) EXPECT() *MockRbacUseCaseMockRecorder {
+	return m.recorder
+}
+
+// AssignRole mocks base method.
+func (m *MockRbacUseCase) AssignRole(ctx context.Context, userID int64, roleName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRole", ctx, userID, roleName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignRole indicates an expected call of AssignRole.
+func (mr *MockRbacUseCaseMockRecorder) AssignRole(ctx, userID, roleName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRole", reflect.TypeOf((*MockRbacUseCase)(nil).AssignRole), ctx, userID, roleName)
+}
+
+// ListRoles mocks base method.
+func (m *MockRbacUseCase) ListRoles(ctx context.Context) ([]Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoles", ctx)
+	ret0, _ := ret[0].([]Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoles indicates an expected call of ListRoles.
+func (mr *MockRbacUseCaseMockRecorder) ListRoles(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoles", reflect.TypeOf((*MockRbacUseCase)(nil).ListRoles), ctx)
+}
+
+// ListUserRoles mocks base method.
+func (m *MockRbacUseCase) ListUserRoles(ctx context.Context, userID int64) ([]Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserRoles", ctx, userID)
+	ret0, _ := ret[0].([]Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserRoles indicates an expected call of ListUserRoles.
+func (mr *MockRbacUseCaseMockRecorder) ListUserRoles(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserRoles", reflect.TypeOf((*MockRbacUseCase)(nil).ListUserRoles), ctx, userID)
+}
+
+// RevokeRole mocks base method.
+func (m *MockRbacUseCase) RevokeRole(ctx context.Context, userID int64, roleName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRole", ctx, userID, roleName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRole indicates an expected call of RevokeRole.
+func (mr *MockRbacUseCaseMockRecorder) RevokeRole(ctx, userID, roleName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRole", reflect.TypeOf((*MockRbacUseCase)(nil).RevokeRole), ctx, userID, roleName)
+}
+
+// MockRbacRepository is a mock of RbacRepository interface.
+type MockRbacRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRbacRepositoryMockRecorder
+}
+
+// MockRbacRepositoryMockRecorder is the mock recorder for MockRbacRepository.
+type MockRbacRepositoryMockRecorder struct {
+	mock *MockRbacRepository
+}
+
+// NewMockRbacRepository creates a new mock instance.
+func NewMockRbacRepository(ctrl *gomock.Controller) *MockRbacRepository {
+	mock := &MockRbacRepository{ctrl: ctrl}
+	mock.recorder = &MockRbacRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRbacRepository) EXPECT() *MockRbacRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AssignUserRole mocks base method.
+func (m *MockRbacRepository) AssignUserRole(userID, roleID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignUserRole", userID, roleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignUserRole indicates an expected call of AssignUserRole.
+func (mr *MockRbacRepositoryMockRecorder) AssignUserRole(userID, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignUserRole", reflect.TypeOf((*MockRbacRepository)(nil).AssignUserRole), userID, roleID)
+}
+
+// EnsureRole mocks base method.
+func (m *MockRbacRepository) EnsureRole(name, description string, permissions []string) (Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureRole", name, description, permissions)
+	ret0, _ := ret[0].(Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureRole indicates an expected call of EnsureRole.
+func (mr *MockRbacRepositoryMockRecorder) EnsureRole(name, description, permissions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureRole", reflect.TypeOf((*MockRbacRepository)(nil).EnsureRole), name, description, permissions)
+}
+
+// FindPermissionsByUserID mocks base method.
+func (m *MockRbacRepository) FindPermissionsByUserID(userID int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPermissionsByUserID", userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPermissionsByUserID indicates an expected call of FindPermissionsByUserID.
+func (mr *MockRbacRepositoryMockRecorder) FindPermissionsByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPermissionsByUserID", reflect.TypeOf((*MockRbacRepository)(nil).FindPermissionsByUserID), userID)
+}
+
+// FindRoleByName mocks base method.
+func (m *MockRbacRepository) FindRoleByName(name string) (Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRoleByName", name)
+	ret0, _ := ret[0].(Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRoleByName indicates an expected call of FindRoleByName.
+func (mr *MockRbacRepositoryMockRecorder) FindRoleByName(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRoleByName", reflect.TypeOf((*MockRbacRepository)(nil).FindRoleByName), name)
+}
+
+// FindRoles mocks base method.
+func (m *MockRbacRepository) FindRoles() ([]Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRoles")
+	ret0, _ := ret[0].([]Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRoles indicates an expected call of FindRoles.
+func (mr *MockRbacRepositoryMockRecorder) FindRoles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRoles", reflect.TypeOf((*MockRbacRepository)(nil).FindRoles))
+}
+
+// FindRolesByUserID mocks base method.
+func (m *MockRbacRepository) FindRolesByUserID(userID int64) ([]Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRolesByUserID", userID)
+	ret0, _ := ret[0].([]Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRolesByUserID indicates an expected call of FindRolesByUserID.
+func (mr *MockRbacRepositoryMockRecorder) FindRolesByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRolesByUserID", reflect.TypeOf((*MockRbacRepository)(nil).FindRolesByUserID), userID)
+}
+
+// RevokeUserRole mocks base method.
+func (m *MockRbacRepository) RevokeUserRole(userID, roleID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserRole", userID, roleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserRole indicates an expected call of RevokeUserRole.
+func (mr *MockRbacRepositoryMockRecorder) RevokeUserRole(userID, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserRole", reflect.TypeOf((*MockRbacRepository)(nil).RevokeUserRole), userID, roleID)
+}
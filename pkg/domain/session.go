@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+type Session struct {
+	ID             string    `json:"id"`
+	UserID         int64     `json:"user_id"`
+	OrganizationID int64     `json:"organization_id,omitempty"` // organization tokens are scoped to, 0 for none
+	UserAgent      string    `json:"user_agent"`
+	ClientIP       string    `json:"client_ip"`
+	CreatedAt      time.Time `json:"created_at"`
+	LastSeenAt     time.Time `json:"last_seen_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+//==================================================================================================
+// Repository
+//==================================================================================================
+
+type SessionRepository interface {
+	InsertSession(session Session) (err error)
+	UpdateSession(session Session) (err error)
+	TouchSession(id string, lastSeenAt time.Time) (err error)
+	FindSessionByID(id string) (Session, error)
+	FindSessionsByUserID(userID int64) ([]Session, error)
+	DeleteSession(id string) (err error)
+}
@@ -1,13 +1,25 @@
 package domain
 
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
 type User struct {
-	ID        int64  `json:"id" gorm:"primaryKey"`
-	Username  string `json:"username" gorm:"uniqueIndex;not null"`
-	Email     string `json:"email" gorm:"uniqueIndex;not null,email"`
-	Password  string `json:"password" gorm:"not null"`
-	Age       int    `json:"age" gorm:"not null"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID              int64      `json:"id" gorm:"primaryKey"`
+	Username        string     `json:"username" gorm:"uniqueIndex;not null"`
+	Email           string     `json:"email" gorm:"uniqueIndex;not null,email"`
+	Password        string     `json:"-" gorm:"not null"`
+	Age             int        `json:"age" gorm:"not null"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	TotpSecret      string     `json:"-"`
+	TotpEnabledAt   *time.Time `json:"totp_enabled_at"`
+	CreatedAt       string     `json:"created_at"`
+	UpdatedAt       string     `json:"updated_at"`
+
+	// deleted accounts are kept for a grace period before they are purged
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
 //==================================================================================================
@@ -15,7 +27,12 @@ type User struct {
 //==================================================================================================
 
 type UserRepository interface {
-	InsertUser(user User) (err error)
-	FindUserByEmail(email string) (User, error)
-	FindUserByID(id int64) (User, error)
+	InsertUser(ctx context.Context, user User) (err error)
+	FindUserByEmail(ctx context.Context, email string) (User, error)
+	FindUserByID(ctx context.Context, id int64) (User, error)
+	UpdateUserPassword(ctx context.Context, id int64, password string) (err error)
+	UpdateUserPasswordHash(ctx context.Context, id int64, currentHash, newHash string) (err error)
+	UpdateUserEmailVerifiedAt(ctx context.Context, id int64, verifiedAt time.Time) (err error)
+	UpdateUserTotp(ctx context.Context, id int64, secret string, enabledAt *time.Time) (err error)
+	DeleteUser(ctx context.Context, id int64) (err error)
 }
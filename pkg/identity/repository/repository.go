@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/db"
+	"gorm.io/gorm"
+)
+
+type UserIdentityRepository struct {
+	dbClient *db.DatabaseConnection
+	time     commonTime.TimeInterface
+}
+
+func NewUserIdentityRepository(dbClient *db.DatabaseConnection, time commonTime.TimeInterface) *UserIdentityRepository {
+	return &UserIdentityRepository{
+		dbClient: dbClient,
+		time:     time,
+	}
+}
+
+func (r *UserIdentityRepository) InsertUserIdentity(identity domain.UserIdentity) (err error) {
+	result := r.dbClient.Master.Create(&identity)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no row affected")
+	}
+
+	return nil
+}
+
+func (r *UserIdentityRepository) FindUserIdentity(provider, subject string) (domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+
+	result := r.dbClient.Slave.Where("provider = ? AND subject = ?", provider, subject).First(&identity)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.UserIdentity{}, common.ErrIdentityNotFound
+	}
+
+	if result.Error != nil {
+		return domain.UserIdentity{}, result.Error
+	}
+
+	return identity, nil
+}
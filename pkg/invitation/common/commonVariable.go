@@ -0,0 +1,70 @@
+package common
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvitationNotFound  = fmt.Errorf("invitation not found")
+	ErrInvitationAccepted  = fmt.Errorf("invitation already accepted")
+	ErrInvitationInvalid   = fmt.Errorf("invitation code invalid or expired")
+	ErrInvitationRequired  = fmt.Errorf("registration is by invitation only")
+	ErrUserAlreadyExists   = fmt.Errorf("a user with this email already exists")
+	ErrResendTooFrequent   = fmt.Errorf("the invitation was sent recently, try again later")
+	ErrExpiryInvalid       = fmt.Errorf("expiry must be in the future")
+	ErrOrganizationMissing = fmt.Errorf("organization_id is required without the invitations permission")
+	ErrTooManyInvitations  = fmt.Errorf("too many invitations were created recently, try again later")
+)
+
+// Invitation statuses
+const (
+	StatusPending  = "pending"
+	StatusAccepted = "accepted"
+	StatusExpired  = "expired"
+)
+
+const (
+	InvitationResendInterval = time.Minute // 1 minute
+
+	// members without the invitations permission can create at most InvitationCreateLimit invitations per window
+	InvitationCreateLimit  = 20
+	InvitationCreateWindow = time.Hour // 1 hour
+)
+
+// A list of task types.
+const (
+	TypeInvitationEmail = "email:invitation"
+)
+
+type CreateInvitationRequest struct {
+	Email          string     `json:"email" validate:"required,email"`
+	Role           string     `json:"role"`                                      // granted on registration
+	OrganizationID int64      `json:"organization_id" validate:"omitempty,gt=0"` // joined on registration
+	ExpiresAt      *time.Time `json:"expires_at"`                                // defaults to the invitation lifetime
+}
+
+type ListInvitationsRequest struct {
+	OrganizationID int64 `form:"organization_id" validate:"omitempty,gt=0"`
+}
+
+// InvitationPayload is the payload of TypeInvitationEmail task
+type InvitationPayload struct {
+	Email            string    `json:"email"`
+	Code             string    `json:"code"`
+	OrganizationName string    `json:"organization_name"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// Status tells whether an invitation, accepted at acceptedAt or nil when it wasn't, can still be accepted at now
+func Status(acceptedAt *time.Time, expiresAt, now time.Time) string {
+	if acceptedAt != nil {
+		return StatusAccepted
+	}
+
+	if !now.Before(expiresAt) {
+		return StatusExpired
+	}
+
+	return StatusPending
+}
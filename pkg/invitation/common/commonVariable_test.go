@@ -0,0 +1,32 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestStatus(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	acceptedAt := now.Add(-time.Hour)
+
+	testCases := []struct {
+		name       string
+		acceptedAt *time.Time
+		expiresAt  time.Time
+		expected   string
+	}{
+		{name: "pending", expiresAt: now.Add(time.Hour), expected: StatusPending},
+		{name: "expired", expiresAt: now.Add(-time.Hour), expected: StatusExpired},
+		{name: "expiring_now", expiresAt: now, expected: StatusExpired},
+		{name: "accepted", acceptedAt: &acceptedAt, expiresAt: now.Add(time.Hour), expected: StatusAccepted},
+		{name: "accepted_then_expired", acceptedAt: &acceptedAt, expiresAt: now.Add(-time.Minute),
+			expected: StatusAccepted},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Status(tc.acceptedAt, tc.expiresAt, now))
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package delivery
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/invitation/common"
+	organizationCommon "github.com/lactobasilusprotectus/go-template/pkg/organization/common"
+	rbacCommon "github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	httputil "github.com/lactobasilusprotectus/go-template/pkg/util/http"
+	"net/http"
+	"strconv"
+)
+
+type InvitationHttpHandler struct {
+	authMiddleware    domain.GinAuthentication
+	invitationUseCase domain.InvitationUseCase
+}
+
+func NewInvitationHttpHandler(authMiddleware domain.GinAuthentication,
+	invitationUseCase domain.InvitationUseCase) *InvitationHttpHandler {
+	return &InvitationHttpHandler{
+		authMiddleware:    authMiddleware,
+		invitationUseCase: invitationUseCase,
+	}
+}
+
+func (i *InvitationHttpHandler) Register(g *gin.Engine) {
+	invitations := g.Group("invitations", i.authMiddleware.MustLogin())
+
+	invitations.POST("", i.CreateInvitation)
+	invitations.GET("", i.ListInvitations)
+	invitations.DELETE(":id", i.RevokeInvitation)
+	invitations.POST(":id/resend", i.ResendInvitation)
+}
+
+// CreateInvitation		godoc
+//
+//	@Summary		Invite an email to register.
+//	@Description	Invite the email to register and email it the invitation code. Users with the invitations:manage
+//	@Description	permission can invite anyone, and grant a role on registration if they hold roles:assign and
+//	@Description	every permission of the role. Other users can only invite to an organization they are a member
+//	@Description	of, a limited number of times per hour. Within an organization, invitations are made to that
+//	@Description	organization and can't grant a role, roles being platform-wide.
+//	@Produce		application/json
+//	@Tags			invitation
+//	@Security		JWT
+//	@Param			body	body		common.CreateInvitationRequest	true	"Create Invitation Request"
+//	@Success		200		{object}	http.BaseResponse{data=domain.Invitation}
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		404		{object}	http.BaseResponse
+//	@Failure		409		{object}	http.BaseResponse
+//	@Failure		429		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/invitations [post]
+func (i *InvitationHttpHandler) CreateInvitation(c *gin.Context) {
+	// init request body
+	var createRequest common.CreateInvitationRequest
+
+	//bind request body
+	if err := c.ShouldBindJSON(&createRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&createRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	invitation, err := i.invitationUseCase.CreateInvitation(c.Request.Context(), createRequest)
+
+	// handle error
+	if errors.Is(err, common.ErrExpiryInvalid) || errors.Is(err, common.ErrOrganizationMissing) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	if errors.Is(err, rbacCommon.ErrPermissionDenied) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, organizationCommon.ErrOrganizationNotFound) || errors.Is(err, rbacCommon.ErrRoleNotFound) {
+		httputil.WriteNotOkResponseWithErrMsg(c, http.StatusNotFound, httputil.ResponseNotFoundError, err.Error())
+		return
+	}
+
+	if errors.Is(err, common.ErrUserAlreadyExists) {
+		httputil.WriteConflictResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrTooManyInvitations) {
+		httputil.WriteTooManyRequestsResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, invitation)
+	return
+}
+
+// ListInvitations		godoc
+//
+//	@Summary		List invitations.
+//	@Description	List the invitations to the organization, most recent first, along with their status. Users
+//	@Description	with the invitations:manage permission can list every invitation by leaving the organization
+//	@Description	out, outside any organization.
+//	@Produce		application/json
+//	@Tags			invitation
+//	@Security		JWT
+//	@Param			organization_id	query		int	false	"Organization ID, the current one by default"
+//	@Success		200				{object}	http.BaseResponse{data=[]domain.Invitation}
+//	@Failure		400				{object}	http.BaseResponse
+//	@Failure		401				{object}	http.BaseResponse
+//	@Failure		403				{object}	http.BaseResponse
+//	@Failure		404				{object}	http.BaseResponse
+//	@Failure		500				{object}	http.BaseResponse
+//	@Router			/invitations [get]
+func (i *InvitationHttpHandler) ListInvitations(c *gin.Context) {
+	// init request query
+	var listRequest common.ListInvitationsRequest
+
+	//bind request query
+	if err := c.ShouldBindQuery(&listRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request
+	if err := validator.New().Struct(&listRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	invitations, err := i.invitationUseCase.ListInvitations(c.Request.Context(), listRequest.OrganizationID)
+
+	// handle error
+	if errors.Is(err, common.ErrOrganizationMissing) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	if errors.Is(err, rbacCommon.ErrPermissionDenied) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, organizationCommon.ErrOrganizationNotFound) {
+		httputil.WriteNotOkResponseWithErrMsg(c, http.StatusNotFound, httputil.ResponseNotFoundError, err.Error())
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, invitations)
+	return
+}
+
+// RevokeInvitation		godoc
+//
+//	@Summary		Revoke an invitation.
+//	@Description	Delete a pending invitation, its code stops working.
+//	@Produce		application/json
+//	@Tags			invitation
+//	@Security		JWT
+//	@Param			id	path		int	true	"Invitation ID"
+//	@Success		200	{object}	http.BaseResponse
+//	@Failure		400	{object}	http.BaseResponse
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		404	{object}	http.BaseResponse
+//	@Failure		409	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/invitations/{id} [delete]
+func (i *InvitationHttpHandler) RevokeInvitation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	err = i.invitationUseCase.RevokeInvitation(c.Request.Context(), id)
+
+	// handle error
+	if errors.Is(err, common.ErrInvitationNotFound) {
+		httputil.WriteNotOkResponseWithErrMsg(c, http.StatusNotFound, httputil.ResponseNotFoundError, err.Error())
+		return
+	}
+
+	if errors.Is(err, common.ErrInvitationAccepted) {
+		httputil.WriteConflictResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "Invitation revoked")
+	return
+}
+
+// ResendInvitation		godoc
+//
+//	@Summary		Resend an invitation.
+//	@Description	Email a pending invitation again with a new code, valid as long as the invitation was when first
+//	@Description	sent. The previous code stops working.
+//	@Produce		application/json
+//	@Tags			invitation
+//	@Security		JWT
+//	@Param			id	path		int	true	"Invitation ID"
+//	@Success		200	{object}	http.BaseResponse
+//	@Failure		400	{object}	http.BaseResponse
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		404	{object}	http.BaseResponse
+//	@Failure		409	{object}	http.BaseResponse
+//	@Failure		429	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/invitations/{id}/resend [post]
+func (i *InvitationHttpHandler) ResendInvitation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	err = i.invitationUseCase.ResendInvitation(c.Request.Context(), id)
+
+	// handle error
+	if errors.Is(err, common.ErrInvitationNotFound) {
+		httputil.WriteNotOkResponseWithErrMsg(c, http.StatusNotFound, httputil.ResponseNotFoundError, err.Error())
+		return
+	}
+
+	if errors.Is(err, common.ErrInvitationAccepted) {
+		httputil.WriteConflictResponseWithErrMsg(c, err)
+		return
+	}
+
+	if errors.Is(err, common.ErrResendTooFrequent) {
+		httputil.WriteTooManyRequestsResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "Invitation resent")
+	return
+}
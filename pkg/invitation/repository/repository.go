@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/invitation/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/db"
+	"gorm.io/gorm"
+	"time"
+)
+
+type InvitationRepository struct {
+	dbClient *db.DatabaseConnection
+	time     commonTime.TimeInterface
+}
+
+func NewInvitationRepository(dbClient *db.DatabaseConnection, time commonTime.TimeInterface) *InvitationRepository {
+	return &InvitationRepository{
+		dbClient: dbClient,
+		time:     time,
+	}
+}
+
+func (r *InvitationRepository) InsertInvitation(invitation *domain.Invitation) (err error) {
+	result := r.dbClient.Master.Create(invitation)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no row affected")
+	}
+
+	return nil
+}
+
+func (r *InvitationRepository) FindInvitationByID(id int64) (domain.Invitation, error) {
+	var invitation domain.Invitation
+
+	result := r.dbClient.Slave.Where("id = ?", id).First(&invitation)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.Invitation{}, common.ErrInvitationNotFound
+	}
+
+	if result.Error != nil {
+		return domain.Invitation{}, result.Error
+	}
+
+	return invitation, nil
+}
+
+func (r *InvitationRepository) FindInvitationByCodeHash(codeHash string) (domain.Invitation, error) {
+	var invitation domain.Invitation
+
+	result := r.dbClient.Slave.Where("code_hash = ?", codeHash).First(&invitation)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.Invitation{}, common.ErrInvitationNotFound
+	}
+
+	if result.Error != nil {
+		return domain.Invitation{}, result.Error
+	}
+
+	return invitation, nil
+}
+
+// FindPendingInvitationByEmail returns the latest invitation of the email which can still be accepted at now
+func (r *InvitationRepository) FindPendingInvitationByEmail(email string, now time.Time) (domain.Invitation, error) {
+	var invitation domain.Invitation
+
+	result := r.dbClient.Slave.Where("email = ? AND accepted_at IS NULL AND expires_at > ?", email, now).
+		Order("created_at DESC").Order("id DESC").First(&invitation)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.Invitation{}, common.ErrInvitationNotFound
+	}
+
+	if result.Error != nil {
+		return domain.Invitation{}, result.Error
+	}
+
+	return invitation, nil
+}
+
+// FindInvitations returns the invitations to the organization, every invitation when organizationID is 0, most
+// recent first
+func (r *InvitationRepository) FindInvitations(organizationID int64) ([]domain.Invitation, error) {
+	var invitations []domain.Invitation
+
+	query := r.dbClient.Slave.Order("created_at DESC").Order("id DESC")
+	if organizationID != 0 {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+
+	result := query.Find(&invitations)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return invitations, nil
+}
+
+// UpdateInvitationCode replaces the code of a pending invitation, the previous one stops working
+func (r *InvitationRepository) UpdateInvitationCode(id int64, codeHash string, sentAt, expiresAt time.Time) (
+	err error) {
+	result := r.dbClient.Master.Model(&domain.Invitation{}).Where("id = ? AND accepted_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"code_hash":  codeHash,
+			"sent_at":    sentAt,
+			"expires_at": expiresAt,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return common.ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+// InsertInvitedUser marks the invitation as accepted and inserts its user in one transaction, an invitation is
+// accepted only once so that an invitation revoked or accepted meanwhile creates no user
+func (r *InvitationRepository) InsertInvitedUser(id int64, acceptedAt time.Time, user *domain.User) (err error) {
+	return r.dbClient.Master.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Invitation{}).Where("id = ? AND accepted_at IS NULL", id).
+			Update("accepted_at", acceptedAt)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return common.ErrInvitationNotFound
+		}
+
+		result = tx.Create(user)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("no row affected")
+		}
+
+		return nil
+	})
+}
+
+func (r *InvitationRepository) DeleteInvitation(id int64) (err error) {
+	result := r.dbClient.Master.Where("id = ?", id).Delete(&domain.Invitation{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return common.ErrInvitationNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hibiken/asynq"
+	"github.com/lactobasilusprotectus/go-template/pkg/invitation/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/queue"
+	"time"
+)
+
+func (i *InvitationUseCase) RegisterQueue(as *queue.AsynqServer) {
+	as.AddHandlerFunc(common.TypeInvitationEmail, i.HandleSendInvitationEmail)
+}
+
+// HandleSendInvitationEmail sends the invitee a link to register with the invitation code.
+func (i *InvitationUseCase) HandleSendInvitationEmail(ctx context.Context, task *asynq.Task) error {
+	var p common.InvitationPayload
+	if err := json.Unmarshal(task.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	target := "an account"
+	if p.OrganizationName != "" {
+		target = fmt.Sprintf("an account in %s", p.OrganizationName)
+	}
+
+	link := fmt.Sprintf("%s/register?invitation=%s", i.config.ClientURL, p.Code)
+	body := fmt.Sprintf("You have been invited to create %s. Register from the following link before %s:\n%s\n\n"+
+		"If you weren't expecting this invitation, you can ignore this email.", target,
+		p.ExpiresAt.UTC().Format(time.RFC1123), link)
+
+	return i.mailer.Send(p.Email, "You have been invited", body)
+}
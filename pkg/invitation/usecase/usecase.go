@@ -0,0 +1,425 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hibiken/asynq"
+	auditCommon "github.com/lactobasilusprotectus/go-template/pkg/audit/common"
+	authCommon "github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/invitation/common"
+	organizationCommon "github.com/lactobasilusprotectus/go-template/pkg/organization/common"
+	rbacCommon "github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/mail"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/queue"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"log"
+	"strings"
+)
+
+type InvitationUseCase struct {
+	invitationRepo   domain.InvitationRepository
+	userRepo         domain.UserRepository
+	rbacRepo         domain.RbacRepository
+	organizationRepo domain.OrganizationRepository
+	auditRecorder    domain.AuditRecorder
+	redis            redis.Interface
+	time             commonTime.TimeInterface
+	config           config.Config
+	client           queue.Interface
+	mailer           mail.Interface
+}
+
+func NewInvitationUseCase(invitationRepo domain.InvitationRepository, userRepo domain.UserRepository,
+	rbacRepo domain.RbacRepository, organizationRepo domain.OrganizationRepository, auditRecorder domain.AuditRecorder,
+	redis redis.Interface, time commonTime.TimeInterface, config config.Config, client queue.Interface,
+	mailer mail.Interface) *InvitationUseCase {
+	return &InvitationUseCase{
+		invitationRepo:   invitationRepo,
+		userRepo:         userRepo,
+		rbacRepo:         rbacRepo,
+		organizationRepo: organizationRepo,
+		auditRecorder:    auditRecorder,
+		redis:            redis,
+		time:             time,
+		config:           config,
+		client:           client,
+		mailer:           mailer,
+	}
+}
+
+// CreateInvitation invites the email to register and emails it the invitation code. Users with the invitations
+// permission can invite anyone, and grant a role if they may assign roles and already hold its permissions. Other
+// users can only invite to an organization they are a member of, at most InvitationCreateLimit times per
+// InvitationCreateWindow. Within an organization, invitations are made to that organization and grant no role, roles
+// being platform-wide.
+func (i *InvitationUseCase) CreateInvitation(ctx context.Context, request common.CreateInvitationRequest) (
+	invitation domain.Invitation, err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = authCommon.ErrAuthUnauthenticated
+		return
+	}
+
+	now := i.time.Now()
+
+	expiresAt := now.Add(i.config.Registration.InvitationLifetime)
+	if request.ExpiresAt != nil {
+		if !request.ExpiresAt.After(now) {
+			err = common.ErrExpiryInvalid
+			return
+		}
+
+		expiresAt = *request.ExpiresAt
+	}
+
+	if request.OrganizationID == 0 {
+		request.OrganizationID, _ = general.GetTenantIDFromCtx(ctx)
+	}
+
+	manager, err := i.authorize(ctx, request.OrganizationID)
+	if err != nil {
+		return
+	}
+
+	if request.Role != "" {
+		if !manager {
+			err = rbacCommon.ErrPermissionDenied
+			return
+		}
+
+		if tenantID, _ := general.GetTenantIDFromCtx(ctx); tenantID != 0 {
+			err = rbacCommon.ErrRolesPlatformWide
+			return
+		}
+
+		var role domain.Role
+		role, err = i.rbacRepo.FindRoleByName(request.Role)
+		if errors.Is(err, rbacCommon.ErrRoleNotFound) {
+			return
+		}
+
+		if err != nil {
+			err = fmt.Errorf("find role err: %+v", err)
+			return
+		}
+
+		if err = i.authorizeRoleGrant(ctx, userID, role); err != nil {
+			return
+		}
+	}
+
+	if !manager {
+		if err = i.throttleCreation(userID); err != nil {
+			return
+		}
+	}
+
+	email := strings.ToLower(strings.TrimSpace(request.Email))
+
+	if _, err = i.userRepo.FindUserByEmail(ctx, email); err == nil {
+		err = common.ErrUserAlreadyExists
+		return
+	}
+
+	code, err := general.GenerateRandomToken(32)
+	if err != nil {
+		return
+	}
+
+	invitation = domain.Invitation{
+		Email:          email,
+		Role:           request.Role,
+		OrganizationID: request.OrganizationID,
+		CodeHash:       general.HashToken(code),
+		InvitedBy:      userID,
+		SentAt:         now,
+		ExpiresAt:      expiresAt,
+		CreatedAt:      now,
+	}
+
+	if err = i.invitationRepo.InsertInvitation(&invitation); err != nil {
+		err = fmt.Errorf("insert invitation err: %+v", err)
+		return
+	}
+
+	if err = i.sendInvitationEmail(ctx, invitation, code); err != nil {
+		return
+	}
+
+	log.Printf("invitation created: invitation_id=%d, organization_id=%d, invited_by=%d", invitation.ID,
+		invitation.OrganizationID, userID)
+	i.auditRecorder.Record(ctx, domain.AuditEvent{
+		Type:  auditCommon.EventInvitationCreated,
+		Email: invitation.Email,
+		Detail: fmt.Sprintf("invitation %d, role %q, organization %d", invitation.ID, invitation.Role,
+			invitation.OrganizationID),
+	})
+
+	invitation.Status = common.Status(invitation.AcceptedAt, invitation.ExpiresAt, now)
+	return
+}
+
+// ListInvitations lists the invitations to the organization, most recent first. Users with the invitations
+// permission can list every invitation by leaving organizationID to 0, outside any organization.
+func (i *InvitationUseCase) ListInvitations(ctx context.Context, organizationID int64) (
+	invitations []domain.Invitation, err error) {
+	if organizationID == 0 {
+		organizationID, _ = general.GetTenantIDFromCtx(ctx)
+	}
+
+	if _, err = i.authorize(ctx, organizationID); err != nil {
+		return
+	}
+
+	invitations, err = i.invitationRepo.FindInvitations(organizationID)
+	if err != nil {
+		err = fmt.Errorf("find invitations err: %+v", err)
+		return
+	}
+
+	now := i.time.Now()
+	for idx := range invitations {
+		invitations[idx].Status = common.Status(invitations[idx].AcceptedAt, invitations[idx].ExpiresAt, now)
+	}
+
+	return
+}
+
+// RevokeInvitation deletes a pending invitation, its code stops working.
+func (i *InvitationUseCase) RevokeInvitation(ctx context.Context, id int64) (err error) {
+	invitation, err := i.findInvitation(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if invitation.AcceptedAt != nil {
+		return common.ErrInvitationAccepted
+	}
+
+	err = i.invitationRepo.DeleteInvitation(id)
+	if errors.Is(err, common.ErrInvitationNotFound) {
+		return
+	}
+
+	if err != nil {
+		return fmt.Errorf("delete invitation err: %+v", err)
+	}
+
+	log.Printf("invitation revoked: invitation_id=%d", id)
+	i.auditRecorder.Record(ctx, domain.AuditEvent{
+		Type:   auditCommon.EventInvitationRevoked,
+		Email:  invitation.Email,
+		Detail: fmt.Sprintf("invitation %d", id),
+	})
+
+	return nil
+}
+
+// ResendInvitation emails a pending invitation again, at most once per InvitationResendInterval. The invitation
+// gets a new code, valid as long as the invitation was when sent the first time, the previous code stops working.
+func (i *InvitationUseCase) ResendInvitation(ctx context.Context, id int64) (err error) {
+	invitation, err := i.findInvitation(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if invitation.AcceptedAt != nil {
+		return common.ErrInvitationAccepted
+	}
+
+	ok, err := i.redis.SetNX(invitationResendCacheKey(id), "1", int(common.InvitationResendInterval.Seconds()))
+	if err != nil {
+		return fmt.Errorf("throttle invitation err: %+v", err)
+	}
+
+	if !ok {
+		return common.ErrResendTooFrequent
+	}
+
+	code, err := general.GenerateRandomToken(32)
+	if err != nil {
+		return
+	}
+
+	now := i.time.Now()
+	invitation.ExpiresAt = now.Add(invitation.ExpiresAt.Sub(invitation.SentAt))
+
+	err = i.invitationRepo.UpdateInvitationCode(id, general.HashToken(code), now, invitation.ExpiresAt)
+	if errors.Is(err, common.ErrInvitationNotFound) {
+		// accepted or revoked meanwhile
+		return
+	}
+
+	if err != nil {
+		return fmt.Errorf("update invitation code err: %+v", err)
+	}
+
+	if err = i.sendInvitationEmail(ctx, invitation, code); err != nil {
+		return
+	}
+
+	log.Printf("invitation resent: invitation_id=%d", id)
+	return nil
+}
+
+// find the invitation, as not found when the current user can't manage it
+func (i *InvitationUseCase) findInvitation(ctx context.Context, id int64) (invitation domain.Invitation,
+	err error) {
+	invitation, err = i.invitationRepo.FindInvitationByID(id)
+	if errors.Is(err, common.ErrInvitationNotFound) {
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("find invitation err: %+v", err)
+		return
+	}
+
+	_, err = i.authorize(ctx, invitation.OrganizationID)
+	if errors.Is(err, organizationCommon.ErrOrganizationNotFound) || errors.Is(err, rbacCommon.ErrPermissionDenied) ||
+		errors.Is(err, common.ErrOrganizationMissing) {
+		err = common.ErrInvitationNotFound
+	}
+
+	return
+}
+
+// authorize the current user to manage the invitations to the organization, 0 being no organization. manager
+// reports whether the user holds the invitations permission, any invitation can be managed then. Requests made
+// within an organization never reach another one.
+func (i *InvitationUseCase) authorize(ctx context.Context, organizationID int64) (manager bool, err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		return false, authCommon.ErrAuthUnauthenticated
+	}
+
+	if tenantID, ok := general.GetTenantIDFromCtx(ctx); ok && tenantID != 0 && tenantID != organizationID {
+		return false, organizationCommon.ErrOrganizationNotFound
+	}
+
+	if organizationID != 0 {
+		_, err = i.organizationRepo.FindOrganizationByID(organizationID)
+		if errors.Is(err, organizationCommon.ErrOrganizationNotFound) {
+			return
+		}
+
+		if err != nil {
+			return false, fmt.Errorf("find organization err: %+v", err)
+		}
+	}
+
+	granted, err := i.rbacRepo.FindPermissionsByUserID(userID)
+	if err != nil {
+		return false, fmt.Errorf("find permissions err: %+v", err)
+	}
+
+	// api keys may be restricted to a subset of the user permissions
+	scopes, _ := general.GetScopesFromCtx(ctx)
+
+	if rbacCommon.PermissionGranted(granted, rbacCommon.PermissionInvitationsManage) &&
+		(len(scopes) == 0 || rbacCommon.PermissionGranted(scopes, rbacCommon.PermissionInvitationsManage)) {
+		return true, nil
+	}
+
+	if organizationID == 0 {
+		return false, common.ErrOrganizationMissing
+	}
+
+	member, err := i.organizationRepo.IsMember(organizationID, userID)
+	if err != nil {
+		return false, fmt.Errorf("find membership err: %+v", err)
+	}
+
+	if !member {
+		return false, rbacCommon.ErrPermissionDenied
+	}
+
+	return false, nil
+}
+
+// authorize the current user to grant the role through an invitation, like assigning it: the user must be allowed to
+// assign roles and already hold every permission of the role, so that an invitation never escalates privileges
+func (i *InvitationUseCase) authorizeRoleGrant(ctx context.Context, userID int64, role domain.Role) error {
+	granted, err := i.rbacRepo.FindPermissionsByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("find permissions err: %+v", err)
+	}
+
+	// api keys may be restricted to a subset of the user permissions
+	if scopes, _ := general.GetScopesFromCtx(ctx); len(scopes) != 0 {
+		if !rbacCommon.PermissionGranted(scopes, rbacCommon.PermissionRolesAssign) {
+			return rbacCommon.ErrPermissionDenied
+		}
+	}
+
+	required := make([]string, 0, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		required = append(required, permission.Name)
+	}
+
+	if !rbacCommon.PermissionGranted(granted, rbacCommon.PermissionRolesAssign) ||
+		!rbacCommon.PermissionsCovered(granted, required) {
+		return rbacCommon.ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// count the invitations created by the user, failing once InvitationCreateLimit is reached within the window
+func (i *InvitationUseCase) throttleCreation(userID int64) error {
+	created, err := i.redis.Incr(invitationCreateCacheKey(userID), int(common.InvitationCreateWindow.Seconds()))
+	if err != nil {
+		return fmt.Errorf("throttle invitation err: %+v", err)
+	}
+
+	if created > common.InvitationCreateLimit {
+		return common.ErrTooManyInvitations
+	}
+
+	return nil
+}
+
+// enqueue the invitation email carrying the code, which isn't kept anywhere else
+func (i *InvitationUseCase) sendInvitationEmail(ctx context.Context, invitation domain.Invitation,
+	code string) (err error) {
+	payload := common.InvitationPayload{
+		Email:     invitation.Email,
+		Code:      code,
+		ExpiresAt: invitation.ExpiresAt,
+	}
+
+	if invitation.OrganizationID != 0 {
+		var organization domain.Organization
+		if organization, err = i.organizationRepo.FindOrganizationByID(invitation.OrganizationID); err != nil {
+			return fmt.Errorf("find organization err: %+v", err)
+		}
+
+		payload.OrganizationName = organization.Name
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = i.client.EnqueueTaskContext(ctx, asynq.NewTask(common.TypeInvitationEmail, data))
+	if err != nil {
+		return fmt.Errorf("enqueue invitation email err: %+v", err)
+	}
+
+	return nil
+}
+
+func invitationResendCacheKey(id int64) string {
+	return fmt.Sprintf("invitation-resend:%d", id)
+}
+
+func invitationCreateCacheKey(userID int64) string {
+	return fmt.Sprintf("invitation-create:%d", userID)
+}
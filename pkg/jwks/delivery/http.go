@@ -0,0 +1,36 @@
+package delivery
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"net/http"
+)
+
+type JwksHttpHandler struct {
+	jwksUseCase domain.JwksUseCase
+}
+
+func NewJwksHttpHandler(jwksUseCase domain.JwksUseCase) *JwksHttpHandler {
+	return &JwksHttpHandler{
+		jwksUseCase: jwksUseCase,
+	}
+}
+
+func (j *JwksHttpHandler) Register(g *gin.Engine) {
+	g.GET(".well-known/jwks.json", j.GetJwks)
+}
+
+// GetJwks			godoc
+//
+//	@Summary		Get token verification keys.
+//	@Description	Public keys access tokens may be verified with, as a JSON Web Key Set (RFC 7517). Empty when tokens are signed with a shared secret.
+//	@Produce		application/json
+//	@Tags			auth
+//	@Success		200	{object}	jwt.JSONWebKeySet
+//	@Router			/.well-known/jwks.json [get]
+func (j *JwksHttpHandler) GetJwks(c *gin.Context) {
+	// the key set is served as is, verifiers expect the RFC 7517 document rather than our envelope
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, j.jwksUseCase.GetJwks(c.Request.Context()))
+	return
+}
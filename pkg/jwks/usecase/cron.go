@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"github.com/lactobasilusprotectus/go-template/pkg/util/cronjob"
+)
+
+func (j *JwksUseCase) RegisterCron(c *cronjob.Cron) {
+	// rotation is opt-in, every replica runs it and converges on the same key files
+	if j.config.JwtKeys.RotationSchedule == "" {
+		return
+	}
+
+	c.AddFunc("jwt-key-rotation", j.config.JwtKeys.RotationSchedule, j.RotateKeys)
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"log"
+)
+
+type JwksUseCase struct {
+	jwtModule jwt.JwtInterface
+	config    config.Config
+}
+
+func NewJwksUseCase(jwtModule jwt.JwtInterface, config config.Config) *JwksUseCase {
+	return &JwksUseCase{
+		jwtModule: jwtModule,
+		config:    config,
+	}
+}
+
+// GetJwks returns the public keys downstream services verify our tokens with
+func (j *JwksUseCase) GetJwks(ctx context.Context) jwt.JSONWebKeySet {
+	return j.jwtModule.JWKS(ctx)
+}
+
+// RotateKeys switches to a new signing key, previous keys keep verifying until their retention passes
+func (j *JwksUseCase) RotateKeys(ctx context.Context) (err error) {
+	if err = j.jwtModule.RotateKeys(ctx); err != nil {
+		err = fmt.Errorf("rotate keys err: %+v", err)
+		return
+	}
+
+	log.Printf("jwt signing key rotated: keys=%d", len(j.jwtModule.JWKS(ctx).Keys))
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"fmt"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/db"
+	"time"
+)
+
+type RecoveryCodeRepository struct {
+	dbClient *db.DatabaseConnection
+	time     commonTime.TimeInterface
+}
+
+func NewRecoveryCodeRepository(dbClient *db.DatabaseConnection, time commonTime.TimeInterface) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{
+		dbClient: dbClient,
+		time:     time,
+	}
+}
+
+func (r *RecoveryCodeRepository) InsertRecoveryCodes(codes []domain.RecoveryCode) (err error) {
+	result := r.dbClient.Master.Create(&codes)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no row affected")
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks the matching unused code as used, used reports whether such code existed.
+// The update is conditional, so a code can't be used twice even by concurrent requests.
+func (r *RecoveryCodeRepository) UseRecoveryCode(userID int64, codeHash string, usedAt time.Time) (used bool, err error) {
+	result := r.dbClient.Master.Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", usedAt)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *RecoveryCodeRepository) DeleteRecoveryCodesByUserID(userID int64) (err error) {
+	result := r.dbClient.Master.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package common
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Error is an error response as defined by RFC 6749 section 5.2
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	Status      int    `json:"-"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+// WithDescription returns copy of the error with the given description, errors.Is still matches the original
+func (e *Error) WithDescription(description string) *Error {
+	return &Error{Code: e.Code, Description: description, Status: e.Status}
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+var (
+	ErrInvalidRequest          = &Error{Code: "invalid_request", Status: http.StatusBadRequest}
+	ErrInvalidClient           = &Error{Code: "invalid_client", Status: http.StatusUnauthorized}
+	ErrInvalidGrant            = &Error{Code: "invalid_grant", Status: http.StatusBadRequest}
+	ErrUnauthorizedClient      = &Error{Code: "unauthorized_client", Status: http.StatusBadRequest}
+	ErrUnsupportedGrantType    = &Error{Code: "unsupported_grant_type", Status: http.StatusBadRequest}
+	ErrUnsupportedResponseType = &Error{Code: "unsupported_response_type", Status: http.StatusBadRequest}
+	ErrInvalidScope            = &Error{Code: "invalid_scope", Status: http.StatusBadRequest}
+	ErrAccessDenied            = &Error{Code: "access_denied", Status: http.StatusForbidden}
+	ErrInvalidToken            = &Error{Code: "invalid_token", Status: http.StatusUnauthorized}
+	ErrInsufficientScope       = &Error{Code: "insufficient_scope", Status: http.StatusForbidden}
+	ErrServerError             = &Error{Code: "server_error", Status: http.StatusInternalServerError}
+
+	ErrClientNotFound  = fmt.Errorf("oauth client not found")
+	ErrClientInvalid   = fmt.Errorf("oauth client invalid")
+	ErrConsentNotFound = fmt.Errorf("oauth consent not found")
+)
+
+const (
+	GrantTypeClientCredentials = "client_credentials"
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+
+	ResponseTypeCode = "code"
+
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+
+	CodeChallengeMethodS256 = "S256"
+
+	AccessTokenType  = "oauth_access_token"
+	RefreshTokenType = "oauth_refresh_token"
+
+	TokenTypeHintAccessToken  = "access_token"
+	TokenTypeHintRefreshToken = "refresh_token"
+
+	AccessTokenLifetime       = time.Minute * 15    // 15 mins
+	RefreshTokenLifetime      = time.Hour * 24 * 30 // 30 days
+	AuthorizationCodeLifetime = time.Minute * 5     // 5 mins
+)
+
+// TokenRequest is the form posted to the token endpoint, fields are used depending on grant_type
+type TokenRequest struct {
+	GrantType    string `form:"grant_type" validate:"required"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	Scope        string `form:"scope"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+}
+
+// TokenResponse is the successful response of the token endpoint as defined by RFC 6749 section 5.1
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// IntrospectRequest is the form posted to the introspection endpoint (RFC 7662) and, with the same fields,
+// to the revocation endpoint (RFC 7009)
+type IntrospectRequest struct {
+	Token         string `form:"token" validate:"required"`
+	TokenTypeHint string `form:"token_type_hint"`
+	ClientID      string `form:"client_id"`
+	ClientSecret  string `form:"client_secret"`
+}
+
+// IntrospectResponse is the response of the introspection endpoint as defined by RFC 7662 section 2.2,
+// an inactive token carries nothing but Active
+type IntrospectResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Jti       string `json:"jti,omitempty"`
+	SessionID string `json:"sid,omitempty"`
+}
+
+type AuthorizeRequest struct {
+	ResponseType        string `form:"response_type" json:"response_type" validate:"required"`
+	ClientID            string `form:"client_id" json:"client_id" validate:"required"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri"`
+	Scope               string `form:"scope" json:"scope"`
+	State               string `form:"state" json:"state"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method"`
+}
+
+type ConsentRequest struct {
+	AuthorizeRequest
+	Approve bool `json:"approve"`
+}
+
+// AuthorizeInfo tells the client application what to show the user, or where to send the user when consent is
+// already given
+type AuthorizeInfo struct {
+	ClientID        string   `json:"client_id"`
+	ClientName      string   `json:"client_name"`
+	Scopes          []string `json:"scopes"`
+	ConsentRequired bool     `json:"consent_required"`
+	RedirectTo      string   `json:"redirect_to,omitempty"`
+}
+
+type CreateClientRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	RedirectURIs  []string `json:"redirect_uris" validate:"omitempty,dive,url"`
+	Scopes        []string `json:"scopes" validate:"omitempty,dive,required"`
+	GrantTypes    []string `json:"grant_types" validate:"required,min=1,dive,oneof=client_credentials authorization_code refresh_token"`
+	Confidential  bool     `json:"confidential"`
+	Introspection bool     `json:"introspection"` // confidential clients only
+}
+
+type ClientInfo struct {
+	ID            string    `json:"client_id"`
+	Name          string    `json:"name"`
+	RedirectURIs  []string  `json:"redirect_uris"`
+	Scopes        []string  `json:"scopes"`
+	GrantTypes    []string  `json:"grant_types"`
+	Confidential  bool      `json:"confidential"`
+	Introspection bool      `json:"introspection"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CreatedClient is returned once on registration, the secret can't be retrieved later
+type CreatedClient struct {
+	ClientInfo
+	Secret string `json:"client_secret,omitempty"`
+}
+
+// UserInfo holds the claims of OpenID Connect core section 5.1 about the user a token was granted by
+type UserInfo struct {
+	Subject       string `json:"sub"`
+	Username      string `json:"preferred_username"`
+	Email         string `json:"email,omitempty"`          // only with the email scope
+	EmailVerified *bool  `json:"email_verified,omitempty"` // only with the email scope
+}
+
+// ParseScope splits space delimited scope parameter
+func ParseScope(scope string) []string {
+	return strings.Fields(scope)
+}
+
+// ScopesAllowed reports whether every requested scope is in allowed
+func ScopesAllowed(allowed, requested []string) bool {
+	for _, scope := range requested {
+		if !contains(allowed, scope) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// VerifyCodeChallenge checks PKCE verifier against the S256 challenge, see RFC 7636 section 4.6
+func VerifyCodeChallenge(verifier, challenge string) bool {
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,46 @@
+package common
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVerifyCodeChallenge(t *testing.T) {
+	// RFC 7636 appendix B
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	assert.True(t, VerifyCodeChallenge(verifier, challenge))
+	assert.False(t, VerifyCodeChallenge(verifier+"x", challenge))
+	assert.False(t, VerifyCodeChallenge(verifier, ""))
+}
+
+func TestScopesAllowed(t *testing.T) {
+	testCases := []struct {
+		name      string
+		allowed   []string
+		requested []string
+		expected  bool
+	}{
+		{name: "nothing requested", allowed: []string{"read"}, requested: nil, expected: true},
+		{name: "subset", allowed: []string{"read", "write"}, requested: []string{"write"}, expected: true},
+		{name: "not allowed", allowed: []string{"read"}, requested: []string{"read", "write"}, expected: false},
+		{name: "nothing allowed", allowed: nil, requested: []string{"read"}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ScopesAllowed(tc.allowed, tc.requested))
+		})
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	err := ErrInvalidGrant.WithDescription("code expired")
+
+	assert.True(t, errors.Is(err, ErrInvalidGrant))
+	assert.False(t, errors.Is(err, ErrInvalidClient))
+	assert.Equal(t, "code expired", err.Description)
+	assert.Empty(t, ErrInvalidGrant.Description)
+}
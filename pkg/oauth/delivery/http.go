@@ -0,0 +1,505 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	authCommon "github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/oauth/common"
+	rbacCommon "github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	httputil "github.com/lactobasilusprotectus/go-template/pkg/util/http"
+	"log"
+	"net/http"
+	"net/url"
+)
+
+type OAuthHttpHandler struct {
+	authMiddleware  domain.GinAuthentication
+	oauthMiddleware domain.OAuthAuthentication
+	oauthUseCase    domain.OAuthUseCase
+}
+
+func NewOAuthHttpHandler(authMiddleware domain.GinAuthentication, oauthMiddleware domain.OAuthAuthentication,
+	oauthUseCase domain.OAuthUseCase) *OAuthHttpHandler {
+	return &OAuthHttpHandler{
+		authMiddleware:  authMiddleware,
+		oauthMiddleware: oauthMiddleware,
+		oauthUseCase:    oauthUseCase,
+	}
+}
+
+func (o *OAuthHttpHandler) Register(g *gin.Engine) {
+	g.POST("oauth/token", o.Token)
+	g.POST("oauth/introspect", o.Introspect)
+	g.POST("oauth/revoke", o.Revoke)
+	g.GET("oauth/authorize", o.authMiddleware.MustLogin(), o.Authorize)
+	g.POST("oauth/authorize", o.authMiddleware.MustLogin(), o.Consent)
+	g.GET("oauth/userinfo", o.oauthMiddleware.RequireScope(common.ScopeProfile), o.UserInfo)
+
+	admin := g.Group("admin/oauth", o.authMiddleware.MustLogin(),
+		o.authMiddleware.RequirePermission(rbacCommon.PermissionOAuthClientsManage))
+	admin.POST("clients", o.CreateClient)
+	admin.GET("clients", o.ListClients)
+	admin.DELETE("clients/:id", o.DeleteClient)
+}
+
+// Token				godoc
+//
+//	@Summary		Issue oauth tokens.
+//	@Description	Token endpoint of RFC 6749 supporting client_credentials, authorization_code with PKCE and
+//	@Description	refresh_token grants. Clients authenticate with HTTP Basic or client_id and client_secret fields.
+//	@Description	Responses follow RFC 6749 instead of the usual response wrapper.
+//	@Accept			application/x-www-form-urlencoded
+//	@Produce		application/json
+//	@Tags			oauth
+//	@Param			grant_type		formData	string	true	"Grant Type"
+//	@Param			client_id		formData	string	false	"Client ID"
+//	@Param			client_secret	formData	string	false	"Client Secret"
+//	@Param			scope			formData	string	false	"Space delimited scopes"
+//	@Param			code			formData	string	false	"Authorization Code"
+//	@Param			redirect_uri	formData	string	false	"Redirect URI sent to the authorize endpoint"
+//	@Param			code_verifier	formData	string	false	"PKCE Code Verifier"
+//	@Param			refresh_token	formData	string	false	"Refresh Token"
+//	@Success		200				{object}	common.TokenResponse
+//	@Failure		400				{object}	common.Error
+//	@Failure		401				{object}	common.Error
+//	@Failure		500				{object}	common.Error
+//	@Router			/oauth/token [post]
+func (o *OAuthHttpHandler) Token(c *gin.Context) {
+	// init request body
+	var tokenRequest common.TokenRequest
+
+	//bind request body
+	if err := c.ShouldBind(&tokenRequest); err != nil {
+		writeOAuthError(c, common.ErrInvalidRequest.WithDescription(err.Error()))
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&tokenRequest); err != nil {
+		writeOAuthError(c, common.ErrInvalidRequest.WithDescription(err.Error()))
+		return
+	}
+
+	bindClientCredentials(c, &tokenRequest.ClientID, &tokenRequest.ClientSecret)
+
+	// call use case
+	token, err := o.oauthUseCase.Token(c.Request.Context(), tokenRequest)
+
+	// handle error
+	var oauthErr *common.Error
+	if errors.As(err, &oauthErr) {
+		writeOAuthError(c, oauthErr)
+		return
+	}
+
+	if err != nil {
+		log.Println(fmt.Sprintf("[ERROR] [%s] %s", httputil.ResponseServerError, err.Error()))
+		writeOAuthError(c, common.ErrServerError)
+		return
+	}
+
+	// write response
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.JSON(http.StatusOK, token)
+	return
+}
+
+// Introspect			godoc
+//
+//	@Summary		Introspect a token.
+//	@Description	Introspection endpoint of RFC 7662 telling whether an access or refresh token is active, for
+//	@Description	first-party sessions and oauth clients alike. Only confidential clients may introspect, they
+//	@Description	authenticate with HTTP Basic or client_id and client_secret fields. Tokens of other clients and
+//	@Description	first-party sessions are inactive unless the client has the introspection privilege.
+//	@Accept			application/x-www-form-urlencoded
+//	@Produce		application/json
+//	@Tags			oauth
+//	@Param			token			formData	string	true	"Token"
+//	@Param			token_type_hint	formData	string	false	"access_token or refresh_token"
+//	@Param			client_id		formData	string	false	"Client ID"
+//	@Param			client_secret	formData	string	false	"Client Secret"
+//	@Success		200				{object}	common.IntrospectResponse
+//	@Failure		400				{object}	common.Error
+//	@Failure		401				{object}	common.Error
+//	@Failure		500				{object}	common.Error
+//	@Router			/oauth/introspect [post]
+func (o *OAuthHttpHandler) Introspect(c *gin.Context) {
+	// init request body
+	var request common.IntrospectRequest
+
+	//bind request body
+	if err := c.ShouldBind(&request); err != nil {
+		writeOAuthError(c, common.ErrInvalidRequest.WithDescription(err.Error()))
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&request); err != nil {
+		writeOAuthError(c, common.ErrInvalidRequest.WithDescription(err.Error()))
+		return
+	}
+
+	bindClientCredentials(c, &request.ClientID, &request.ClientSecret)
+
+	// call use case
+	response, err := o.oauthUseCase.Introspect(c.Request.Context(), request)
+
+	// handle error
+	var oauthErr *common.Error
+	if errors.As(err, &oauthErr) {
+		writeOAuthError(c, oauthErr)
+		return
+	}
+
+	if err != nil {
+		log.Println(fmt.Sprintf("[ERROR] [%s] %s", httputil.ResponseServerError, err.Error()))
+		writeOAuthError(c, common.ErrServerError)
+		return
+	}
+
+	// write response
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.JSON(http.StatusOK, response)
+	return
+}
+
+// Revoke				godoc
+//
+//	@Summary		Revoke a token.
+//	@Description	Revocation endpoint of RFC 7009. Clients may revoke the tokens issued to them, clients with the
+//	@Description	introspection privilege may also end first-party sessions. Unknown tokens are accepted without
+//	@Description	effect.
+//	@Accept			application/x-www-form-urlencoded
+//	@Produce		application/json
+//	@Tags			oauth
+//	@Param			token			formData	string	true	"Token"
+//	@Param			token_type_hint	formData	string	false	"access_token or refresh_token"
+//	@Param			client_id		formData	string	false	"Client ID"
+//	@Param			client_secret	formData	string	false	"Client Secret"
+//	@Success		200
+//	@Failure		400				{object}	common.Error
+//	@Failure		401				{object}	common.Error
+//	@Failure		500				{object}	common.Error
+//	@Router			/oauth/revoke [post]
+func (o *OAuthHttpHandler) Revoke(c *gin.Context) {
+	// init request body
+	var request common.IntrospectRequest
+
+	//bind request body
+	if err := c.ShouldBind(&request); err != nil {
+		writeOAuthError(c, common.ErrInvalidRequest.WithDescription(err.Error()))
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&request); err != nil {
+		writeOAuthError(c, common.ErrInvalidRequest.WithDescription(err.Error()))
+		return
+	}
+
+	bindClientCredentials(c, &request.ClientID, &request.ClientSecret)
+
+	// call use case
+	err := o.oauthUseCase.Revoke(c.Request.Context(), request)
+
+	// handle error
+	var oauthErr *common.Error
+	if errors.As(err, &oauthErr) {
+		writeOAuthError(c, oauthErr)
+		return
+	}
+
+	if err != nil {
+		log.Println(fmt.Sprintf("[ERROR] [%s] %s", httputil.ResponseServerError, err.Error()))
+		writeOAuthError(c, common.ErrServerError)
+		return
+	}
+
+	// write response
+	c.Status(http.StatusOK)
+	return
+}
+
+// Authorize			godoc
+//
+//	@Summary		Start oauth authorization.
+//	@Description	Validate an authorization request of the current user. A redirect carrying the code is returned
+//	@Description	when the user already consented, otherwise consent_required tells to ask the user and submit the
+//	@Description	decision to POST /oauth/authorize. Only S256 PKCE is supported.
+//	@Produce		application/json
+//	@Tags			oauth
+//	@Security		JWT
+//	@Param			response_type			query		string	true	"Must be code"
+//	@Param			client_id				query		string	true	"Client ID"
+//	@Param			redirect_uri			query		string	false	"Redirect URI"
+//	@Param			scope					query		string	false	"Space delimited scopes"
+//	@Param			state					query		string	false	"State"
+//	@Param			code_challenge			query		string	true	"PKCE Code Challenge"
+//	@Param			code_challenge_method	query		string	true	"Must be S256"
+//	@Success		200						{object}	http.BaseResponse{data=common.AuthorizeInfo}
+//	@Failure		400						{object}	http.BaseResponse
+//	@Failure		401						{object}	http.BaseResponse
+//	@Failure		403						{object}	http.BaseResponse
+//	@Failure		500						{object}	http.BaseResponse
+//	@Router			/oauth/authorize [get]
+func (o *OAuthHttpHandler) Authorize(c *gin.Context) {
+	// init request
+	var authorizeRequest common.AuthorizeRequest
+
+	//bind request query
+	if err := c.ShouldBindQuery(&authorizeRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request
+	if err := validator.New().Struct(&authorizeRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	info, err := o.oauthUseCase.Authorize(c.Request.Context(), authorizeRequest)
+
+	// handle error
+	o.writeAuthorizeResponse(c, info, err)
+	return
+}
+
+// Consent				godoc
+//
+//	@Summary		Submit oauth consent.
+//	@Description	Approve or deny an authorization request of the current user, the returned redirect carries
+//	@Description	either the code or the error.
+//	@Produce		application/json
+//	@Tags			oauth
+//	@Security		JWT
+//	@Param			body	body		common.ConsentRequest	true	"Consent Request"
+//	@Success		200		{object}	http.BaseResponse{data=common.AuthorizeInfo}
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/oauth/authorize [post]
+func (o *OAuthHttpHandler) Consent(c *gin.Context) {
+	// init request body
+	var consentRequest common.ConsentRequest
+
+	//bind request body
+	if err := c.ShouldBindJSON(&consentRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&consentRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	info, err := o.oauthUseCase.Consent(c.Request.Context(), consentRequest)
+
+	// handle error
+	o.writeAuthorizeResponse(c, info, err)
+	return
+}
+
+// UserInfo				godoc
+//
+//	@Summary		Get user info of an oauth token.
+//	@Description	UserInfo endpoint of OpenID Connect core section 5.3 for access tokens holding the profile
+//	@Description	scope, email claims are only returned with the email scope. Tokens of the client_credentials
+//	@Description	grant have no user.
+//	@Produce		application/json
+//	@Tags			oauth
+//	@Security		JWT
+//	@Success		200	{object}	common.UserInfo
+//	@Failure		401	{object}	common.Error
+//	@Failure		403	{object}	common.Error
+//	@Failure		500	{object}	common.Error
+//	@Router			/oauth/userinfo [get]
+func (o *OAuthHttpHandler) UserInfo(c *gin.Context) {
+	// call use case
+	info, err := o.oauthUseCase.UserInfo(c.Request.Context())
+
+	// handle error
+	if errors.Is(err, authCommon.ErrAuthUnauthenticated) {
+		c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error="%s"`, common.ErrInvalidToken.Code))
+		writeOAuthError(c, common.ErrInvalidToken)
+		return
+	}
+
+	if err != nil {
+		log.Println(fmt.Sprintf("[ERROR] [%s] %s", httputil.ResponseServerError, err.Error()))
+		writeOAuthError(c, common.ErrServerError)
+		return
+	}
+
+	// write response
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, info)
+	return
+}
+
+func (o *OAuthHttpHandler) writeAuthorizeResponse(c *gin.Context, info common.AuthorizeInfo, err error) {
+	if errors.Is(err, authCommon.ErrAuthUnauthenticated) {
+		httputil.WriteUnauthenticatedResponse(c)
+		return
+	}
+
+	if errors.Is(err, authCommon.ErrApiKeyNotAllowed) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	// errors that can't be sent to the redirect uri
+	var oauthErr *common.Error
+	if errors.As(err, &oauthErr) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, oauthErr)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, info)
+}
+
+// CreateClient			godoc
+//
+//	@Summary		Register oauth client.
+//	@Description	Register oauth client, the secret of confidential clients is only shown in this response.
+//	@Produce		application/json
+//	@Tags			admin
+//	@Security		JWT
+//	@Param			body	body		common.CreateClientRequest	true	"Create Client Request"
+//	@Success		200		{object}	http.BaseResponse{data=common.CreatedClient}
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/admin/oauth/clients [post]
+func (o *OAuthHttpHandler) CreateClient(c *gin.Context) {
+	// init request body
+	var createRequest common.CreateClientRequest
+
+	//bind request body
+	if err := c.ShouldBindJSON(&createRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&createRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	client, err := o.oauthUseCase.CreateClient(c.Request.Context(), createRequest)
+
+	// handle error
+	if errors.Is(err, common.ErrClientInvalid) {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, client)
+	return
+}
+
+// ListClients			godoc
+//
+//	@Summary		List oauth clients.
+//	@Description	List registered oauth clients.
+//	@Produce		application/json
+//	@Tags			admin
+//	@Security		JWT
+//	@Success		200	{object}	http.BaseResponse{data=[]common.ClientInfo}
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		403	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/admin/oauth/clients [get]
+func (o *OAuthHttpHandler) ListClients(c *gin.Context) {
+	// call use case
+	clients, err := o.oauthUseCase.ListClients(c.Request.Context())
+
+	// handle error
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, clients)
+	return
+}
+
+// DeleteClient			godoc
+//
+//	@Summary		Delete oauth client.
+//	@Description	Delete oauth client along with consents given to it. Tokens already issued stay valid until
+//	@Description	they expire.
+//	@Produce		application/json
+//	@Tags			admin
+//	@Security		JWT
+//	@Param			id	path		string	true	"Client ID"
+//	@Success		200	{object}	http.BaseResponse
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		403	{object}	http.BaseResponse
+//	@Failure		404	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/admin/oauth/clients/{id} [delete]
+func (o *OAuthHttpHandler) DeleteClient(c *gin.Context) {
+	// call use case
+	err := o.oauthUseCase.DeleteClient(c.Request.Context(), c.Param("id"))
+
+	// handle error
+	if errors.Is(err, common.ErrClientNotFound) {
+		httputil.WriteNotFoundResponse(c, httputil.ResponseNotFoundError)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "Client deleted")
+	return
+}
+
+// write error response as defined by RFC 6749 section 5.2
+// HTTP Basic client authentication takes precedence, credentials are form encoded (RFC 6749 section 2.3.1)
+func bindClientCredentials(c *gin.Context, clientID, clientSecret *string) {
+	if basicClientID, basicClientSecret, ok := c.Request.BasicAuth(); ok {
+		*clientID, _ = url.QueryUnescape(basicClientID)
+		*clientSecret, _ = url.QueryUnescape(basicClientSecret)
+	}
+}
+
+func writeOAuthError(c *gin.Context, oauthErr *common.Error) {
+	if errors.Is(oauthErr, common.ErrInvalidClient) {
+		c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.JSON(oauthErr.Status, oauthErr)
+}
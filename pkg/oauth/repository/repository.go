@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/oauth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OAuthRepository struct {
+	dbClient *db.DatabaseConnection
+	time     commonTime.TimeInterface
+}
+
+func NewOAuthRepository(dbClient *db.DatabaseConnection, time commonTime.TimeInterface) *OAuthRepository {
+	return &OAuthRepository{
+		dbClient: dbClient,
+		time:     time,
+	}
+}
+
+func (r *OAuthRepository) InsertClient(client domain.OAuthClient) (err error) {
+	result := r.dbClient.Master.Create(&client)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no row affected")
+	}
+
+	return nil
+}
+
+func (r *OAuthRepository) FindClientByID(id string) (domain.OAuthClient, error) {
+	var client domain.OAuthClient
+
+	result := r.dbClient.Slave.Where("id = ?", id).First(&client)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.OAuthClient{}, common.ErrClientNotFound
+	}
+
+	if result.Error != nil {
+		return domain.OAuthClient{}, result.Error
+	}
+
+	return client, nil
+}
+
+func (r *OAuthRepository) FindClients() ([]domain.OAuthClient, error) {
+	var clients []domain.OAuthClient
+
+	result := r.dbClient.Slave.Order("created_at DESC").Find(&clients)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return clients, nil
+}
+
+// DeleteClient deletes the client along with consents given to it
+func (r *OAuthRepository) DeleteClient(id string) (err error) {
+	return r.dbClient.Master.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&domain.OAuthClient{})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return common.ErrClientNotFound
+		}
+
+		return tx.Where("client_id = ?", id).Delete(&domain.OAuthConsent{}).Error
+	})
+}
+
+func (r *OAuthRepository) FindConsent(userID int64, clientID string) (domain.OAuthConsent, error) {
+	var consent domain.OAuthConsent
+
+	result := r.dbClient.Slave.Where("user_id = ? AND client_id = ?", userID, clientID).First(&consent)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.OAuthConsent{}, common.ErrConsentNotFound
+	}
+
+	if result.Error != nil {
+		return domain.OAuthConsent{}, result.Error
+	}
+
+	return consent, nil
+}
+
+// SaveConsent creates or replaces the consent of the user to the client
+func (r *OAuthRepository) SaveConsent(consent domain.OAuthConsent) (err error) {
+	result := r.dbClient.Master.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "client_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"scopes", "updated_at"}),
+	}).Create(&consent)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	authCommon "github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/oauth/common"
+	"log"
+	"net/url"
+	"strings"
+)
+
+// authorizationGrant is what an authorization code or a refresh token stands for
+type authorizationGrant struct {
+	ClientID      string   `json:"client_id"`
+	UserID        int64    `json:"user_id"`
+	Scopes        []string `json:"scopes"`
+	RedirectURI   string   `json:"redirect_uri,omitempty"`   // as sent to the authorize endpoint, may be empty
+	CodeChallenge string   `json:"code_challenge,omitempty"` // PKCE S256 challenge
+}
+
+// Authorize validates an authorization request of the current user. A code is issued right away when the user
+// already consented to the requested scopes, otherwise the caller has to ask the user and call Consent.
+func (o *OAuthUseCase) Authorize(ctx context.Context, request common.AuthorizeRequest) (info common.AuthorizeInfo,
+	err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = authCommon.ErrAuthUnauthenticated
+		return
+	}
+
+	// only the user in person can authorize a client, not an api key acting on its behalf
+	if _, ok = general.GetApiKeyIDFromCtx(ctx); ok {
+		err = authCommon.ErrApiKeyNotAllowed
+		return
+	}
+
+	client, redirectURI, scopes, err := o.validateAuthorizeRequest(request)
+	if err != nil {
+		return o.authorizeError(request, redirectURI, err)
+	}
+
+	info = common.AuthorizeInfo{
+		ClientID:   client.ID,
+		ClientName: client.Name,
+		Scopes:     scopes,
+	}
+
+	consent, err := o.oauthRepo.FindConsent(userID, client.ID)
+	if errors.Is(err, common.ErrConsentNotFound) {
+		info.ConsentRequired = true
+		return info, nil
+	}
+
+	if err != nil {
+		err = fmt.Errorf("find consent err: %+v", err)
+		return
+	}
+
+	if !common.ScopesAllowed(strings.Fields(consent.Scopes), scopes) {
+		info.ConsentRequired = true
+		return info, nil
+	}
+
+	info.RedirectTo, err = o.issueAuthorizationCode(userID, client.ID, redirectURI, scopes, request)
+	return
+}
+
+// Consent records the decision of the current user on an authorization request, and issues a code if approved.
+func (o *OAuthUseCase) Consent(ctx context.Context, request common.ConsentRequest) (info common.AuthorizeInfo,
+	err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = authCommon.ErrAuthUnauthenticated
+		return
+	}
+
+	// only the user in person can authorize a client, not an api key acting on its behalf
+	if _, ok = general.GetApiKeyIDFromCtx(ctx); ok {
+		err = authCommon.ErrApiKeyNotAllowed
+		return
+	}
+
+	client, redirectURI, scopes, err := o.validateAuthorizeRequest(request.AuthorizeRequest)
+	if err != nil {
+		return o.authorizeError(request.AuthorizeRequest, redirectURI, err)
+	}
+
+	if !request.Approve {
+		log.Printf("oauth consent denied: user_id=%d, client_id=%s", userID, client.ID)
+		return o.authorizeError(request.AuthorizeRequest, redirectURI, common.ErrAccessDenied)
+	}
+
+	// keep scopes granted earlier
+	granted := scopes
+	consent, err := o.oauthRepo.FindConsent(userID, client.ID)
+	if err == nil {
+		granted = uniqueValues(append(strings.Fields(consent.Scopes), scopes...))
+	} else if !errors.Is(err, common.ErrConsentNotFound) {
+		err = fmt.Errorf("find consent err: %+v", err)
+		return
+	}
+
+	now := o.time.Now()
+	err = o.oauthRepo.SaveConsent(domain.OAuthConsent{
+		UserID:    userID,
+		ClientID:  client.ID,
+		Scopes:    strings.Join(granted, " "),
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		err = fmt.Errorf("save consent err: %+v", err)
+		return
+	}
+
+	log.Printf("oauth consent granted: user_id=%d, client_id=%s, scopes=%v", userID, client.ID, scopes)
+
+	info = common.AuthorizeInfo{
+		ClientID:   client.ID,
+		ClientName: client.Name,
+		Scopes:     scopes,
+	}
+
+	info.RedirectTo, err = o.issueAuthorizationCode(userID, client.ID, redirectURI, scopes, request.AuthorizeRequest)
+	return
+}
+
+// validate authorization request, redirectURI is only returned once it is known to belong to the client
+func (o *OAuthUseCase) validateAuthorizeRequest(request common.AuthorizeRequest) (client domain.OAuthClient,
+	redirectURI string, scopes []string, err error) {
+	client, err = o.oauthRepo.FindClientByID(request.ClientID)
+	if errors.Is(err, common.ErrClientNotFound) {
+		err = common.ErrInvalidClient.WithDescription("unknown client")
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("find client err: %+v", err)
+		return
+	}
+
+	registered := strings.Fields(client.RedirectURIs)
+	switch {
+	case request.RedirectURI == "" && len(registered) == 1:
+		redirectURI = registered[0]
+	case request.RedirectURI != "" && containsValue(registered, request.RedirectURI):
+		redirectURI = request.RedirectURI
+	default:
+		err = common.ErrInvalidRequest.WithDescription("redirect_uri not registered for the client")
+		return
+	}
+
+	// from here on errors are reported to the client through the redirect uri
+	if request.ResponseType != common.ResponseTypeCode {
+		err = common.ErrUnsupportedResponseType
+		return
+	}
+
+	if !containsValue(strings.Fields(client.GrantTypes), common.GrantTypeAuthorizationCode) {
+		err = common.ErrUnauthorizedClient
+		return
+	}
+
+	if request.CodeChallenge == "" || request.CodeChallengeMethod != common.CodeChallengeMethodS256 {
+		err = common.ErrInvalidRequest.WithDescription("PKCE with S256 code_challenge_method is required")
+		return
+	}
+
+	scopes, err = requestedScopes(client, request.Scope)
+	return
+}
+
+// build redirect carrying the error when the redirect uri is trusted, otherwise return the error itself
+func (o *OAuthUseCase) authorizeError(request common.AuthorizeRequest, redirectURI string, err error) (
+	info common.AuthorizeInfo, _ error) {
+	var oauthErr *common.Error
+	if redirectURI == "" || !errors.As(err, &oauthErr) {
+		return info, err
+	}
+
+	params := url.Values{}
+	params.Set("error", oauthErr.Code)
+	if oauthErr.Description != "" {
+		params.Set("error_description", oauthErr.Description)
+	}
+	if request.State != "" {
+		params.Set("state", request.State)
+	}
+
+	info.ClientID = request.ClientID
+	info.RedirectTo = appendQuery(redirectURI, params)
+	return info, nil
+}
+
+// issue single use authorization code and build the redirect carrying it
+func (o *OAuthUseCase) issueAuthorizationCode(userID int64, clientID, redirectURI string, scopes []string,
+	request common.AuthorizeRequest) (redirectTo string, err error) {
+	code, err := general.GenerateRandomToken(32)
+	if err != nil {
+		return "", fmt.Errorf("generate authorization code err: %+v", err)
+	}
+
+	grant, err := json.Marshal(authorizationGrant{
+		ClientID:      clientID,
+		UserID:        userID,
+		Scopes:        scopes,
+		RedirectURI:   request.RedirectURI,
+		CodeChallenge: request.CodeChallenge,
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal grant err: %+v", err)
+	}
+
+	err = o.redis.Set(authorizationCodeCacheKey(general.HashToken(code)), string(grant),
+		int(common.AuthorizationCodeLifetime.Seconds()))
+	if err != nil {
+		return "", fmt.Errorf("store authorization code err: %+v", err)
+	}
+
+	params := url.Values{}
+	params.Set("code", code)
+	if request.State != "" {
+		params.Set("state", request.State)
+	}
+
+	return appendQuery(redirectURI, params), nil
+}
+
+// requested scopes must be allowed for the client, no scope means every scope of the client
+func requestedScopes(client domain.OAuthClient, scope string) (scopes []string, err error) {
+	allowed := strings.Fields(client.Scopes)
+	scopes = uniqueValues(common.ParseScope(scope))
+
+	if len(scopes) == 0 {
+		return allowed, nil
+	}
+
+	if !common.ScopesAllowed(allowed, scopes) {
+		return nil, common.ErrInvalidScope
+	}
+
+	return scopes, nil
+}
+
+func appendQuery(rawURL string, params url.Values) string {
+	separator := "?"
+	if strings.Contains(rawURL, "?") {
+		separator = "&"
+	}
+
+	return rawURL + separator + params.Encode()
+}
+
+func authorizationCodeCacheKey(codeHash string) string {
+	return fmt.Sprintf("oauth-code:%s", codeHash)
+}
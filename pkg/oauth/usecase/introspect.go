@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/lactobasilusprotectus/go-template/pkg/oauth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Introspect tells a confidential client whether a token is active (RFC 7662). Tokens issued to oauth clients
+// are inspected here, first-party tokens by the auth use case. Only clients with the introspection privilege see
+// tokens of other clients and first-party sessions, to anyone else they are inactive.
+func (o *OAuthUseCase) Introspect(ctx context.Context, request common.IntrospectRequest) (
+	response common.IntrospectResponse, err error) {
+	client, err := o.authenticateClient(request.ClientID, request.ClientSecret)
+	if err != nil {
+		return
+	}
+
+	// public clients have no secret, anyone could pose as one to probe tokens
+	if !client.Confidential {
+		err = common.ErrUnauthorizedClient
+		return
+	}
+
+	data, active, err := o.inspectToken(ctx, request.Token, request.TokenTypeHint)
+	if err != nil {
+		return
+	}
+
+	if active && !client.Introspection && data.ClientID != client.ID {
+		return common.IntrospectResponse{}, nil
+	}
+
+	if active {
+		response = common.IntrospectResponse{
+			Active:    true,
+			Scope:     strings.Join(data.Scopes, " "),
+			ClientID:  data.ClientID,
+			Exp:       data.ExpiresAt.Unix(),
+			Iat:       data.IssuedAt.Unix(),
+			TokenType: data.Type,
+			Jti:       data.TokenID,
+			SessionID: data.SessionID,
+		}
+
+		// tokens of the client credentials grant have no user
+		if data.IdentityID != 0 {
+			response.Sub = strconv.FormatInt(data.IdentityID, 10)
+		}
+
+		return response, nil
+	}
+
+	if !client.Introspection {
+		return common.IntrospectResponse{}, nil
+	}
+
+	introspection, err := o.authUseCase.IntrospectToken(ctx, request.Token, request.TokenTypeHint)
+	if err != nil {
+		err = fmt.Errorf("introspect token err: %+v", err)
+		return
+	}
+
+	if !introspection.Active {
+		return common.IntrospectResponse{}, nil
+	}
+
+	return common.IntrospectResponse{
+		Active:    true,
+		Sub:       strconv.FormatInt(introspection.UserID, 10),
+		Exp:       introspection.ExpiresAt.Unix(),
+		Iat:       introspection.IssuedAt.Unix(),
+		TokenType: introspection.TokenType,
+		Jti:       introspection.TokenID,
+		SessionID: introspection.SessionID,
+	}, nil
+}
+
+// Revoke revokes a token (RFC 7009). A client may revoke the tokens issued to it, while first-party sessions
+// may only be ended by clients with the introspection privilege. Unknown tokens are ignored, so responses tell
+// nothing about them.
+func (o *OAuthUseCase) Revoke(ctx context.Context, request common.IntrospectRequest) (err error) {
+	client, err := o.authenticateClient(request.ClientID, request.ClientSecret)
+	if err != nil {
+		return
+	}
+
+	data, active, err := o.inspectToken(ctx, request.Token, request.TokenTypeHint)
+	if err != nil {
+		return
+	}
+
+	if active {
+		if data.ClientID != client.ID {
+			return nil
+		}
+
+		return o.revokeGrant(data)
+	}
+
+	if !client.Introspection {
+		return nil
+	}
+
+	if err = o.authUseCase.RevokeToken(ctx, request.Token, request.TokenTypeHint); err != nil {
+		err = fmt.Errorf("revoke token err: %+v", err)
+		return
+	}
+
+	return nil
+}
+
+// inspect a token issued to an oauth client, the hint picks which token type is tried first
+func (o *OAuthUseCase) inspectToken(ctx context.Context, token, tokenTypeHint string) (data jwt.JwtData,
+	active bool, err error) {
+	tokenTypes := []string{common.AccessTokenType, common.RefreshTokenType}
+	if tokenTypeHint == common.TokenTypeHintRefreshToken {
+		tokenTypes = []string{common.RefreshTokenType, common.AccessTokenType}
+	}
+
+	for _, tokenType := range tokenTypes {
+		data, err = o.jwtModule.ExtractToken(ctx, token, tokenType)
+		if err != nil || data.ClientID == "" {
+			err = nil
+			continue
+		}
+
+		// refresh tokens are single use, a used or revoked one is gone
+		if tokenType == common.RefreshTokenType {
+			_, err = o.redis.Get(refreshTokenCacheKey(data.TokenID))
+			if errors.Is(err, redis.ErrNilReturned) {
+				err = nil
+				continue
+			}
+
+			if err != nil {
+				err = fmt.Errorf("get refresh token err: %+v", err)
+				return
+			}
+
+			return data, true, nil
+		}
+
+		revoked, err := o.isGrantRevoked(data.SessionID)
+		if err != nil || revoked {
+			return data, false, err
+		}
+
+		return data, true, nil
+	}
+
+	return jwt.JwtData{}, false, nil
+}
+
+// revoke the grant a token was issued under, the refresh token is dropped and access tokens are rejected
+// until they would have expired anyway
+func (o *OAuthUseCase) revokeGrant(data jwt.JwtData) (err error) {
+	if data.Type == common.RefreshTokenType {
+		if err = o.redis.Del(refreshTokenCacheKey(data.TokenID)); err != nil {
+			return fmt.Errorf("delete refresh token err: %+v", err)
+		}
+	}
+
+	err = o.redis.Set(revokedGrantCacheKey(data.SessionID), "1", int(common.AccessTokenLifetime.Seconds()))
+	if err != nil {
+		return fmt.Errorf("revoke grant err: %+v", err)
+	}
+
+	log.Printf("oauth token revoked: client_id=%s, user_id=%d, type=%s", data.ClientID, data.IdentityID, data.Type)
+
+	return nil
+}
+
+// check whether access tokens of the grant have been revoked
+func (o *OAuthUseCase) isGrantRevoked(grantID string) (revoked bool, err error) {
+	_, err = o.redis.Get(revokedGrantCacheKey(grantID))
+	if errors.Is(err, redis.ErrNilReturned) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("isGrantRevoked err: %+v", err)
+	}
+
+	return true, nil
+}
+
+func revokedGrantCacheKey(grantID string) string {
+	return fmt.Sprintf("oauth-grant-revoked:%s", grantID)
+}
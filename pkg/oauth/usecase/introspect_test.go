@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	authCommon "github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/oauth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestOAuthUseCase_Introspect(t *testing.T) {
+	otherClientToken := jwt.JwtData{SessionID: "grant", IdentityID: 1, Type: common.AccessTokenType,
+		ClientID: "other"}
+
+	testCases := []struct {
+		name          string
+		introspection bool
+		tokenData     *jwt.JwtData // nil for first-party tokens
+		active        bool
+		introspected  bool // the first-party token is introspected by the auth use case
+	}{
+		{name: "own token", tokenData: &jwt.JwtData{SessionID: "grant", IdentityID: 1,
+			Type: common.AccessTokenType, ClientID: "client"}, active: true},
+		{name: "token of another client", tokenData: &otherClientToken},
+		{name: "token of another client with privilege", introspection: true, tokenData: &otherClientToken,
+			active: true},
+		{name: "first-party token"},
+		{name: "first-party token with privilege", introspection: true, active: true, introspected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			jwtModule := jwt.NewMockJwtInterface(ctrl)
+			redisMock := redis.NewMockInterface(ctrl)
+			oauthRepo := domain.NewMockOAuthRepository(ctrl)
+			authUseCase := domain.NewMockAuthUseCase(ctrl)
+
+			oauthRepo.EXPECT().FindClientByID("client").
+				Return(newConfidentialClient("client", "secret", tc.introspection), nil)
+
+			if tc.introspected {
+				authUseCase.EXPECT().IntrospectToken(gomock.Any(), "token", gomock.Any()).
+					Return(authCommon.TokenIntrospection{Active: true, UserID: 1}, nil)
+			}
+
+			if tc.tokenData != nil {
+				jwtModule.EXPECT().ExtractToken(gomock.Any(), "token", common.AccessTokenType).
+					Return(*tc.tokenData, nil)
+				redisMock.EXPECT().Get(revokedGrantCacheKey("grant")).Return(nil, redis.ErrNilReturned)
+			} else {
+				jwtModule.EXPECT().ExtractToken(gomock.Any(), "token", gomock.Any()).
+					Return(jwt.JwtData{}, errors.New("invalid")).Times(2)
+			}
+
+			o := &OAuthUseCase{
+				oauthRepo:   oauthRepo,
+				authUseCase: authUseCase,
+				jwtModule:   jwtModule,
+				redis:       redisMock,
+			}
+
+			response, err := o.Introspect(context.Background(), common.IntrospectRequest{Token: "token",
+				ClientID: "client", ClientSecret: "secret"})
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.active, response.Active)
+		})
+	}
+}
+
+func TestOAuthUseCase_Revoke(t *testing.T) {
+	testCases := []struct {
+		name          string
+		introspection bool
+		revoked       bool // the first-party token is revoked by the auth use case
+	}{
+		{name: "first-party token"},
+		{name: "first-party token with privilege", introspection: true, revoked: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			jwtModule := jwt.NewMockJwtInterface(ctrl)
+			oauthRepo := domain.NewMockOAuthRepository(ctrl)
+			authUseCase := domain.NewMockAuthUseCase(ctrl)
+
+			jwtModule.EXPECT().ExtractToken(gomock.Any(), "token", gomock.Any()).
+				Return(jwt.JwtData{}, errors.New("invalid")).Times(2)
+			oauthRepo.EXPECT().FindClientByID("client").
+				Return(newConfidentialClient("client", "secret", tc.introspection), nil)
+
+			if tc.revoked {
+				authUseCase.EXPECT().RevokeToken(gomock.Any(), "token", gomock.Any())
+			}
+
+			o := &OAuthUseCase{
+				oauthRepo:   oauthRepo,
+				authUseCase: authUseCase,
+				jwtModule:   jwtModule,
+			}
+
+			err := o.Revoke(context.Background(), common.IntrospectRequest{Token: "token", ClientID: "client",
+				ClientSecret: "secret"})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func newConfidentialClient(id, secret string, introspection bool) domain.OAuthClient {
+	return domain.OAuthClient{
+		ID:            id,
+		SecretHash:    general.HashToken(secret),
+		Confidential:  true,
+		Introspection: introspection,
+	}
+}
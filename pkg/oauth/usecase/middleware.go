@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/oauth/common"
+	httputil "github.com/lactobasilusprotectus/go-template/pkg/util/http"
+	"strings"
+)
+
+// RequireScope authenticates the request with an oauth access token holding every given scope
+func (o *OAuthUseCase) RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		// oauth access tokens are never kept in the cookies of the browser session mode
+		scheme, token := general.GetAuthorizationFromRequest(c, false)
+
+		if token == "" || !strings.EqualFold(scheme, general.AuthSchemeBearer) {
+			writeBearerChallenge(c, common.ErrInvalidToken)
+			return
+		}
+
+		data, err := o.jwtModule.ExtractToken(ctx, token, common.AccessTokenType)
+
+		if err != nil || data.Type != common.AccessTokenType || data.ClientID == "" {
+			writeBearerChallenge(c, common.ErrInvalidToken)
+			return
+		}
+
+		revoked, err := o.isGrantRevoked(data.SessionID)
+		if err != nil {
+			httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+			c.Abort()
+			return
+		}
+
+		if revoked {
+			writeBearerChallenge(c, common.ErrInvalidToken)
+			return
+		}
+
+		if !common.ScopesAllowed(data.Scopes, scopes) {
+			writeBearerChallenge(c, common.ErrInsufficientScope)
+			return
+		}
+
+		// tokens of the client credentials grant have no user
+		if data.IdentityID != 0 {
+			ctx = general.SetUserIDIntoCtx(ctx, data.IdentityID) // int64
+		}
+		ctx = general.SetOAuthClientIntoCtx(ctx, data.ClientID, data.Scopes) // string, []string
+
+		newReq := c.Request.WithContext(ctx)
+		c.Request = newReq
+
+		c.Next()
+	}
+}
+
+// write error along with WWW-Authenticate header as defined by RFC 6750 section 3
+func writeBearerChallenge(c *gin.Context, oauthErr *common.Error) {
+	c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error="%s"`, oauthErr.Code))
+
+	if errors.Is(oauthErr, common.ErrInsufficientScope) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, oauthErr)
+	} else {
+		httputil.WriteUnauthenticatedResponseWithErrMsg(c, oauthErr)
+	}
+
+	c.Abort()
+}
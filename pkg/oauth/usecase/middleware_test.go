@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/oauth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOAuthUseCase_RequireScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokenData := jwt.JwtData{
+		SessionID:  "grant",
+		IdentityID: 1,
+		Type:       common.AccessTokenType,
+		Scopes:     []string{common.ScopeProfile},
+		ClientID:   "client",
+	}
+
+	testCases := []struct {
+		name          string
+		authorization string
+		setup         func(jwtModule *jwt.MockJwtInterface, redisMock *redis.MockInterface)
+		status        int
+		challenge     string
+	}{
+		{
+			name:      "missing token",
+			status:    http.StatusUnauthorized,
+			challenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "invalid token",
+			authorization: "Bearer token",
+			setup: func(jwtModule *jwt.MockJwtInterface, redisMock *redis.MockInterface) {
+				jwtModule.EXPECT().ExtractToken(gomock.Any(), "token", common.AccessTokenType).
+					Return(jwt.JwtData{}, errors.New("invalid"))
+			},
+			status:    http.StatusUnauthorized,
+			challenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "first-party token",
+			authorization: "Bearer token",
+			setup: func(jwtModule *jwt.MockJwtInterface, redisMock *redis.MockInterface) {
+				data := tokenData
+				data.ClientID = ""
+				jwtModule.EXPECT().ExtractToken(gomock.Any(), "token", common.AccessTokenType).Return(data, nil)
+			},
+			status:    http.StatusUnauthorized,
+			challenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "revoked grant",
+			authorization: "Bearer token",
+			setup: func(jwtModule *jwt.MockJwtInterface, redisMock *redis.MockInterface) {
+				jwtModule.EXPECT().ExtractToken(gomock.Any(), "token", common.AccessTokenType).Return(tokenData, nil)
+				redisMock.EXPECT().Get(revokedGrantCacheKey("grant")).Return("1", nil)
+			},
+			status:    http.StatusUnauthorized,
+			challenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "insufficient scope",
+			authorization: "Bearer token",
+			setup: func(jwtModule *jwt.MockJwtInterface, redisMock *redis.MockInterface) {
+				data := tokenData
+				data.Scopes = []string{common.ScopeEmail}
+				jwtModule.EXPECT().ExtractToken(gomock.Any(), "token", common.AccessTokenType).Return(data, nil)
+				redisMock.EXPECT().Get(revokedGrantCacheKey("grant")).Return(nil, redis.ErrNilReturned)
+			},
+			status:    http.StatusForbidden,
+			challenge: `Bearer error="insufficient_scope"`,
+		},
+		{
+			name:          "ok",
+			authorization: "Bearer token",
+			setup: func(jwtModule *jwt.MockJwtInterface, redisMock *redis.MockInterface) {
+				jwtModule.EXPECT().ExtractToken(gomock.Any(), "token", common.AccessTokenType).Return(tokenData, nil)
+				redisMock.EXPECT().Get(revokedGrantCacheKey("grant")).Return(nil, redis.ErrNilReturned)
+			},
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			jwtModule := jwt.NewMockJwtInterface(ctrl)
+			redisMock := redis.NewMockInterface(ctrl)
+			if tc.setup != nil {
+				tc.setup(jwtModule, redisMock)
+			}
+
+			o := &OAuthUseCase{jwtModule: jwtModule, redis: redisMock}
+
+			g := gin.New()
+			g.GET("/resource", o.RequireScope(common.ScopeProfile), func(c *gin.Context) {
+				userID, _ := general.GetUserIDFromCtx(c.Request.Context())
+				clientID, _ := general.GetOAuthClientIDFromCtx(c.Request.Context())
+				assert.Equal(t, int64(1), userID)
+				assert.Equal(t, "client", clientID)
+				c.Status(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/resource", nil)
+			if tc.authorization != "" {
+				request.Header.Set("Authorization", tc.authorization)
+			}
+
+			recorder := httptest.NewRecorder()
+			g.ServeHTTP(recorder, request)
+
+			assert.Equal(t, tc.status, recorder.Code)
+			assert.Equal(t, tc.challenge, recorder.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/oauth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"log"
+	"strings"
+)
+
+// Token exchanges a grant for an access token, the client must be authenticated beforehand by the caller
+// filling ClientID and ClientSecret.
+func (o *OAuthUseCase) Token(ctx context.Context, request common.TokenRequest) (token common.TokenResponse,
+	err error) {
+	client, err := o.authenticateClient(request.ClientID, request.ClientSecret)
+	if err != nil {
+		return
+	}
+
+	switch request.GrantType {
+	case common.GrantTypeClientCredentials, common.GrantTypeAuthorizationCode, common.GrantTypeRefreshToken:
+	default:
+		err = common.ErrUnsupportedGrantType
+		return
+	}
+
+	if !containsValue(strings.Fields(client.GrantTypes), request.GrantType) {
+		err = common.ErrUnauthorizedClient
+		return
+	}
+
+	switch request.GrantType {
+	case common.GrantTypeClientCredentials:
+		return o.clientCredentialsGrant(ctx, client, request)
+	case common.GrantTypeAuthorizationCode:
+		return o.authorizationCodeGrant(ctx, client, request)
+	default:
+		return o.refreshTokenGrant(ctx, client, request)
+	}
+}
+
+// client acts on its own behalf, no user involved
+func (o *OAuthUseCase) clientCredentialsGrant(ctx context.Context, client domain.OAuthClient,
+	request common.TokenRequest) (token common.TokenResponse, err error) {
+	if !client.Confidential {
+		err = common.ErrUnauthorizedClient
+		return
+	}
+
+	scopes, err := requestedScopes(client, request.Scope)
+	if err != nil {
+		return
+	}
+
+	return o.issueTokens(ctx, client, authorizationGrant{ClientID: client.ID, Scopes: scopes}, scopes)
+}
+
+func (o *OAuthUseCase) authorizationCodeGrant(ctx context.Context, client domain.OAuthClient,
+	request common.TokenRequest) (token common.TokenResponse, err error) {
+	if request.Code == "" || request.CodeVerifier == "" {
+		err = common.ErrInvalidRequest.WithDescription("code and code_verifier are required")
+		return
+	}
+
+	// code is single use
+	grant, err := o.consumeGrant(authorizationCodeCacheKey(general.HashToken(request.Code)))
+	if err != nil {
+		return
+	}
+
+	if grant.ClientID != client.ID || grant.RedirectURI != request.RedirectURI {
+		err = common.ErrInvalidGrant
+		return
+	}
+
+	if !common.VerifyCodeChallenge(request.CodeVerifier, grant.CodeChallenge) {
+		err = common.ErrInvalidGrant.WithDescription("code_verifier mismatch")
+		return
+	}
+
+	if _, err = o.userRepo.FindUserByID(ctx, grant.UserID); err != nil {
+		err = common.ErrInvalidGrant
+		return
+	}
+
+	log.Printf("oauth authorization code exchanged: user_id=%d, client_id=%s", grant.UserID, client.ID)
+
+	grant.RedirectURI, grant.CodeChallenge = "", ""
+	return o.issueTokens(ctx, client, grant, grant.Scopes)
+}
+
+func (o *OAuthUseCase) refreshTokenGrant(ctx context.Context, client domain.OAuthClient,
+	request common.TokenRequest) (token common.TokenResponse, err error) {
+	data, err := o.jwtModule.ExtractToken(ctx, request.RefreshToken, common.RefreshTokenType)
+	if err != nil || data.Type != common.RefreshTokenType || data.TokenID == "" {
+		err = common.ErrInvalidGrant
+		return
+	}
+
+	// refresh token rotates, a used one is gone
+	grant, err := o.consumeGrant(refreshTokenCacheKey(data.TokenID))
+	if err != nil {
+		return
+	}
+
+	if grant.ClientID != client.ID {
+		err = common.ErrInvalidGrant
+		return
+	}
+
+	// scope can be narrowed down for the access token, the refresh token keeps the original grant
+	scopes := grant.Scopes
+	if requested := uniqueValues(common.ParseScope(request.Scope)); len(requested) > 0 {
+		if !common.ScopesAllowed(grant.Scopes, requested) {
+			err = common.ErrInvalidScope
+			return
+		}
+		scopes = requested
+	}
+
+	if grant.UserID != 0 {
+		if _, err = o.userRepo.FindUserByID(ctx, grant.UserID); err != nil {
+			err = common.ErrInvalidGrant
+			return
+		}
+	}
+
+	return o.issueTokens(ctx, client, grant, scopes)
+}
+
+// authenticate client, public clients have no secret to check
+func (o *OAuthUseCase) authenticateClient(clientID, clientSecret string) (client domain.OAuthClient, err error) {
+	if clientID == "" {
+		err = common.ErrInvalidClient
+		return
+	}
+
+	client, err = o.oauthRepo.FindClientByID(clientID)
+	if errors.Is(err, common.ErrClientNotFound) {
+		err = common.ErrInvalidClient
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("find client err: %+v", err)
+		return
+	}
+
+	if !client.Confidential {
+		return client, nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(general.HashToken(clientSecret)), []byte(client.SecretHash)) != 1 {
+		err = common.ErrInvalidClient
+		return
+	}
+
+	return client, nil
+}
+
+// issue access token, and refresh token if the client may use it and a user is involved
+func (o *OAuthUseCase) issueTokens(ctx context.Context, client domain.OAuthClient, grant authorizationGrant,
+	scopes []string) (token common.TokenResponse, err error) {
+	grantID := uuid.New().String()
+
+	token.AccessToken, err = o.jwtModule.GenerateToken(ctx, jwt.JwtData{
+		TokenID:    uuid.New().String(),
+		SessionID:  grantID,
+		IdentityID: grant.UserID,
+		Type:       common.AccessTokenType,
+		Lifetime:   common.AccessTokenLifetime,
+		Scopes:     scopes,
+		ClientID:   client.ID,
+	})
+	if err != nil {
+		err = fmt.Errorf("generate access token err: %+v", err)
+		return
+	}
+
+	token.TokenType = general.AuthSchemeBearer
+	token.ExpiresIn = int(common.AccessTokenLifetime.Seconds())
+	token.Scope = strings.Join(scopes, " ")
+
+	if grant.UserID == 0 || !containsValue(strings.Fields(client.GrantTypes), common.GrantTypeRefreshToken) {
+		return
+	}
+
+	refreshTokenID := uuid.New().String()
+
+	grantJSON, err := json.Marshal(grant)
+	if err != nil {
+		err = fmt.Errorf("marshal grant err: %+v", err)
+		return
+	}
+
+	err = o.redis.Set(refreshTokenCacheKey(refreshTokenID), string(grantJSON),
+		int(common.RefreshTokenLifetime.Seconds()))
+	if err != nil {
+		err = fmt.Errorf("store refresh token err: %+v", err)
+		return
+	}
+
+	token.RefreshToken, err = o.jwtModule.GenerateToken(ctx, jwt.JwtData{
+		TokenID:    refreshTokenID,
+		SessionID:  grantID,
+		IdentityID: grant.UserID,
+		Type:       common.RefreshTokenType,
+		Lifetime:   common.RefreshTokenLifetime,
+		ClientID:   client.ID,
+	})
+	if err != nil {
+		err = fmt.Errorf("generate refresh token err: %+v", err)
+		return
+	}
+
+	return
+}
+
+// read and delete the grant stored at key
+func (o *OAuthUseCase) consumeGrant(key string) (grant authorizationGrant, err error) {
+	value, err := o.redis.GetDel(key)
+	if errors.Is(err, redis.ErrNilReturned) {
+		err = common.ErrInvalidGrant
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("consume grant err: %+v", err)
+		return
+	}
+
+	if err = json.Unmarshal([]byte(fmt.Sprint(value)), &grant); err != nil {
+		err = fmt.Errorf("unmarshal grant err: %+v", err)
+		return
+	}
+
+	return grant, nil
+}
+
+func refreshTokenCacheKey(tokenID string) string {
+	return fmt.Sprintf("oauth-refresh-token:%s", tokenID)
+}
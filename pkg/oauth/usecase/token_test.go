@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/oauth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestOAuthUseCase_Token_authorizationCode(t *testing.T) {
+	const (
+		verifier    = "dBjftJeZ4CVP-mJ92K27uhbUJU1p1r_wW1gFWFOEjXk"
+		redirectURI = "https://app.example.com/callback"
+	)
+
+	sum := sha256.Sum256([]byte(verifier))
+	grant := authorizationGrant{
+		ClientID:      "client",
+		UserID:        1,
+		Scopes:        []string{common.ScopeProfile},
+		RedirectURI:   redirectURI,
+		CodeChallenge: base64.RawURLEncoding.EncodeToString(sum[:]),
+	}
+
+	request := common.TokenRequest{
+		GrantType:    common.GrantTypeAuthorizationCode,
+		ClientID:     "client",
+		Code:         "code",
+		CodeVerifier: verifier,
+		RedirectURI:  redirectURI,
+	}
+
+	testCases := []struct {
+		name     string
+		request  func(request *common.TokenRequest)
+		grant    func(grant *authorizationGrant) // nil when the code is unknown or used already
+		lookedUp bool                            // the user of the grant is looked up
+		err      error
+	}{
+		{name: "exchanged", grant: func(grant *authorizationGrant) {}, lookedUp: true},
+		{name: "missing verifier", request: func(request *common.TokenRequest) { request.CodeVerifier = "" },
+			err: common.ErrInvalidRequest},
+		{name: "unknown or used code", err: common.ErrInvalidGrant},
+		{name: "verifier mismatch", request: func(request *common.TokenRequest) { request.CodeVerifier = "other" },
+			grant: func(grant *authorizationGrant) {}, err: common.ErrInvalidGrant},
+		{name: "code of another client", grant: func(grant *authorizationGrant) { grant.ClientID = "other" },
+			err: common.ErrInvalidGrant},
+		{name: "redirect uri mismatch", request: func(request *common.TokenRequest) { request.RedirectURI = "" },
+			grant: func(grant *authorizationGrant) {}, err: common.ErrInvalidGrant},
+		{name: "user deleted since", grant: func(grant *authorizationGrant) {}, lookedUp: true,
+			err: common.ErrInvalidGrant},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			jwtModule := jwt.NewMockJwtInterface(ctrl)
+			redisMock := redis.NewMockInterface(ctrl)
+			oauthRepo := domain.NewMockOAuthRepository(ctrl)
+			userRepo := domain.NewMockUserRepository(ctrl)
+
+			oauthRepo.EXPECT().FindClientByID("client").Return(domain.OAuthClient{ID: "client",
+				GrantTypes: "authorization_code refresh_token", RedirectURIs: redirectURI}, nil)
+
+			req := request
+			if tc.request != nil {
+				tc.request(&req)
+			}
+
+			codeKey := authorizationCodeCacheKey(general.HashToken("code"))
+
+			switch {
+			case req.CodeVerifier == "":
+			case tc.grant == nil:
+				redisMock.EXPECT().GetDel(codeKey).Return(nil, redis.ErrNilReturned)
+			default:
+				g := grant
+				tc.grant(&g)
+				data, err := json.Marshal(g)
+				require.NoError(t, err)
+
+				redisMock.EXPECT().GetDel(codeKey).Return(string(data), nil)
+			}
+
+			var (
+				issued []jwt.JwtData
+				stored string
+			)
+			if tc.err == nil {
+				jwtModule.EXPECT().GenerateToken(gomock.Any(), gomock.Any()).Times(2).
+					DoAndReturn(func(ctx context.Context, data jwt.JwtData) (string, error) {
+						issued = append(issued, data)
+						return data.Type, nil
+					})
+				redisMock.EXPECT().Set(gomock.Any(), gomock.Any(), int(common.RefreshTokenLifetime.Seconds())).
+					DoAndReturn(func(key string, value interface{}, expireSeconds int) error {
+						stored = value.(string)
+						return nil
+					})
+			}
+
+			switch {
+			case tc.lookedUp && tc.err == nil:
+				userRepo.EXPECT().FindUserByID(gomock.Any(), int64(1)).Return(domain.User{ID: 1}, nil)
+			case tc.lookedUp:
+				userRepo.EXPECT().FindUserByID(gomock.Any(), int64(1)).Return(domain.User{}, gorm.ErrRecordNotFound)
+			}
+
+			o := &OAuthUseCase{
+				oauthRepo: oauthRepo,
+				userRepo:  userRepo,
+				jwtModule: jwtModule,
+				redis:     redisMock,
+			}
+
+			token, err := o.Token(context.Background(), req)
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, common.AccessTokenType, token.AccessToken)
+			assert.Equal(t, common.RefreshTokenType, token.RefreshToken)
+			assert.Equal(t, common.ScopeProfile, token.Scope)
+
+			require.Len(t, issued, 2)
+			assert.Equal(t, int64(1), issued[0].IdentityID)
+			assert.Equal(t, "client", issued[0].ClientID)
+			assert.Equal(t, []string{common.ScopeProfile}, issued[0].Scopes)
+
+			// the refresh token grant no longer carries what only the code exchange needed
+			var refreshGrant authorizationGrant
+			require.NoError(t, json.Unmarshal([]byte(stored), &refreshGrant))
+			assert.Equal(t, authorizationGrant{ClientID: "client", UserID: 1, Scopes: []string{common.ScopeProfile}},
+				refreshGrant)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/oauth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/jwt"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"log"
+	"strings"
+)
+
+type OAuthUseCase struct {
+	oauthRepo   domain.OAuthRepository
+	userRepo    domain.UserRepository
+	authUseCase domain.AuthUseCase
+	jwtModule   jwt.JwtInterface
+	redis       redis.Interface
+	time        commonTime.TimeInterface
+	config      config.Config
+}
+
+func NewOAuthUseCase(oauthRepo domain.OAuthRepository, userRepo domain.UserRepository, authUseCase domain.AuthUseCase,
+	jwtModule jwt.JwtInterface, redis redis.Interface, time commonTime.TimeInterface,
+	config config.Config) *OAuthUseCase {
+	return &OAuthUseCase{
+		oauthRepo:   oauthRepo,
+		userRepo:    userRepo,
+		authUseCase: authUseCase,
+		jwtModule:   jwtModule,
+		redis:       redis,
+		time:        time,
+		config:      config,
+	}
+}
+
+// CreateClient registers a new client, the secret of confidential clients is only ever returned here.
+func (o *OAuthUseCase) CreateClient(ctx context.Context, request common.CreateClientRequest) (
+	client common.CreatedClient, err error) {
+	grantTypes := uniqueValues(request.GrantTypes)
+
+	if containsValue(grantTypes, common.GrantTypeAuthorizationCode) && len(request.RedirectURIs) == 0 {
+		err = fmt.Errorf("%w: authorization_code grant requires a redirect uri", common.ErrClientInvalid)
+		return
+	}
+
+	if containsValue(grantTypes, common.GrantTypeClientCredentials) && !request.Confidential {
+		err = fmt.Errorf("%w: client_credentials grant requires a confidential client", common.ErrClientInvalid)
+		return
+	}
+
+	if request.Introspection && !request.Confidential {
+		err = fmt.Errorf("%w: introspection requires a confidential client", common.ErrClientInvalid)
+		return
+	}
+
+	oauthClient := domain.OAuthClient{
+		ID:            uuid.New().String(),
+		Name:          request.Name,
+		RedirectURIs:  strings.Join(request.RedirectURIs, " "),
+		Scopes:        strings.Join(uniqueValues(request.Scopes), " "),
+		GrantTypes:    strings.Join(grantTypes, " "),
+		Confidential:  request.Confidential,
+		Introspection: request.Introspection,
+		CreatedAt:     o.time.Now(),
+	}
+
+	if request.Confidential {
+		client.Secret, err = general.GenerateRandomToken(32)
+		if err != nil {
+			err = fmt.Errorf("generate client secret err: %+v", err)
+			return
+		}
+
+		oauthClient.SecretHash = general.HashToken(client.Secret)
+	}
+
+	if err = o.oauthRepo.InsertClient(oauthClient); err != nil {
+		err = fmt.Errorf("insert client err: %+v", err)
+		return
+	}
+
+	log.Printf("oauth client created: client_id=%s, introspection=%t", oauthClient.ID, oauthClient.Introspection)
+
+	client.ClientInfo = toClientInfo(oauthClient)
+	return
+}
+
+func (o *OAuthUseCase) ListClients(ctx context.Context) (clients []common.ClientInfo, err error) {
+	oauthClients, err := o.oauthRepo.FindClients()
+	if err != nil {
+		err = fmt.Errorf("find clients err: %+v", err)
+		return
+	}
+
+	clients = make([]common.ClientInfo, 0, len(oauthClients))
+	for _, oauthClient := range oauthClients {
+		clients = append(clients, toClientInfo(oauthClient))
+	}
+
+	return
+}
+
+func (o *OAuthUseCase) DeleteClient(ctx context.Context, clientID string) (err error) {
+	err = o.oauthRepo.DeleteClient(clientID)
+	if errors.Is(err, common.ErrClientNotFound) {
+		return err
+	}
+
+	if err != nil {
+		return fmt.Errorf("delete client err: %+v", err)
+	}
+
+	log.Printf("oauth client deleted: client_id=%s", clientID)
+	return nil
+}
+
+func toClientInfo(client domain.OAuthClient) common.ClientInfo {
+	return common.ClientInfo{
+		ID:            client.ID,
+		Name:          client.Name,
+		RedirectURIs:  strings.Fields(client.RedirectURIs),
+		Scopes:        strings.Fields(client.Scopes),
+		GrantTypes:    strings.Fields(client.GrantTypes),
+		Confidential:  client.Confidential,
+		Introspection: client.Introspection,
+		CreatedAt:     client.CreatedAt,
+	}
+}
+
+func uniqueValues(values []string) []string {
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !containsValue(unique, value) {
+			unique = append(unique, value)
+		}
+	}
+
+	return unique
+}
+
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	authCommon "github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/oauth/common"
+	"gorm.io/gorm"
+	"strconv"
+)
+
+// UserInfo returns the claims of the user the access token was granted by, must be chained after RequireScope.
+func (o *OAuthUseCase) UserInfo(ctx context.Context) (info common.UserInfo, err error) {
+	// tokens of the client credentials grant have no user
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = authCommon.ErrAuthUnauthenticated
+		return
+	}
+
+	user, err := o.userRepo.FindUserByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = authCommon.ErrAuthUnauthenticated
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("find user err: %+v", err)
+		return
+	}
+
+	info = common.UserInfo{
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Username: user.Username,
+	}
+
+	scopes, _ := general.GetScopesFromCtx(ctx)
+	if containsValue(scopes, common.ScopeEmail) {
+		emailVerified := user.EmailVerifiedAt != nil
+		info.Email = user.Email
+		info.EmailVerified = &emailVerified
+	}
+
+	return
+}
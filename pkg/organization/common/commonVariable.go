@@ -0,0 +1,27 @@
+package common
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	ErrOrganizationNotFound = fmt.Errorf("organization not found")
+	ErrMemberNotFound       = fmt.Errorf("member not found")
+	ErrUserNotFound         = fmt.Errorf("user not found")
+)
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type AddMemberRequest struct {
+	UserID int64 `json:"user_id" validate:"required,gt=0"`
+}
+
+type OrganizationInfo struct {
+	ID       int64     `json:"id"`
+	Name     string    `json:"name"`
+	JoinedAt time.Time `json:"joined_at"`
+	Current  bool      `json:"current"` // the organization the request is made within
+}
@@ -0,0 +1,214 @@
+package delivery
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/organization/common"
+	rbacCommon "github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	httputil "github.com/lactobasilusprotectus/go-template/pkg/util/http"
+	"net/http"
+	"strconv"
+)
+
+type OrganizationHttpHandler struct {
+	authMiddleware      domain.GinAuthentication
+	organizationUseCase domain.OrganizationUseCase
+}
+
+func NewOrganizationHttpHandler(authMiddleware domain.GinAuthentication,
+	organizationUseCase domain.OrganizationUseCase) *OrganizationHttpHandler {
+	return &OrganizationHttpHandler{
+		authMiddleware:      authMiddleware,
+		organizationUseCase: organizationUseCase,
+	}
+}
+
+func (o *OrganizationHttpHandler) Register(g *gin.Engine) {
+	g.GET("organizations", o.authMiddleware.MustLogin(), o.ListOrganizations)
+
+	admin := g.Group("admin", o.authMiddleware.MustLogin(),
+		o.authMiddleware.RequirePermission(rbacCommon.PermissionOrganizationsManage))
+
+	admin.POST("organizations", o.CreateOrganization)
+	admin.POST("organizations/:id/members", o.AddMember)
+	admin.DELETE("organizations/:id/members/:user_id", o.RemoveMember)
+}
+
+// ListOrganizations	godoc
+//
+//	@Summary		List organizations.
+//	@Description	List organizations the current user is a member of, flagging the current one.
+//	@Produce		application/json
+//	@Tags			organization
+//	@Security		JWT
+//	@Success		200	{object}	http.BaseResponse{data=[]common.OrganizationInfo}
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/organizations [get]
+func (o *OrganizationHttpHandler) ListOrganizations(c *gin.Context) {
+	// call use case
+	organizations, err := o.organizationUseCase.ListOrganizations(c.Request.Context())
+
+	// handle error
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, organizations)
+	return
+}
+
+// CreateOrganization	godoc
+//
+//	@Summary		Create an organization.
+//	@Description	Create an organization with the current user as its first member.
+//	@Produce		application/json
+//	@Tags			admin
+//	@Security		JWT
+//	@Param			body	body		common.CreateOrganizationRequest	true	"Create Organization Request"
+//	@Success		200		{object}	http.BaseResponse{data=domain.Organization}
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/admin/organizations [post]
+func (o *OrganizationHttpHandler) CreateOrganization(c *gin.Context) {
+	// init request body
+	var createRequest common.CreateOrganizationRequest
+
+	//bind request body
+	if err := c.ShouldBindJSON(&createRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err := validator.New().Struct(&createRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	organization, err := o.organizationUseCase.CreateOrganization(c.Request.Context(), createRequest)
+
+	// handle error
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, organization)
+	return
+}
+
+// AddMember			godoc
+//
+//	@Summary		Add a member to an organization.
+//	@Description	Add the given user to the organization, adding twice is a no-op. Within an organization, only
+//	@Description	that organization can be managed.
+//	@Produce		application/json
+//	@Tags			admin
+//	@Security		JWT
+//	@Param			id		path		int							true	"Organization ID"
+//	@Param			body	body		common.AddMemberRequest		true	"Add Member Request"
+//	@Success		200		{object}	http.BaseResponse
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		404		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/admin/organizations/{id}/members [post]
+func (o *OrganizationHttpHandler) AddMember(c *gin.Context) {
+	organizationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// init request body
+	var addRequest common.AddMemberRequest
+
+	//bind request body
+	if err = c.ShouldBindJSON(&addRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err = validator.New().Struct(&addRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	err = o.organizationUseCase.AddMember(c.Request.Context(), organizationID, addRequest.UserID)
+
+	// handle error
+	if errors.Is(err, common.ErrOrganizationNotFound) || errors.Is(err, common.ErrUserNotFound) {
+		httputil.WriteNotOkResponseWithErrMsg(c, http.StatusNotFound, httputil.ResponseNotFoundError, err.Error())
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "Member added")
+	return
+}
+
+// RemoveMember			godoc
+//
+//	@Summary		Remove a member from an organization.
+//	@Description	Remove the given user from the organization. Within an organization, only that organization
+//	@Description	can be managed.
+//	@Produce		application/json
+//	@Tags			admin
+//	@Security		JWT
+//	@Param			id		path		int	true	"Organization ID"
+//	@Param			user_id	path		int	true	"User ID"
+//	@Success		200		{object}	http.BaseResponse
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		404		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/admin/organizations/{id}/members/{user_id} [delete]
+func (o *OrganizationHttpHandler) RemoveMember(c *gin.Context) {
+	organizationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	err = o.organizationUseCase.RemoveMember(c.Request.Context(), organizationID, userID)
+
+	// handle error
+	if errors.Is(err, common.ErrOrganizationNotFound) || errors.Is(err, common.ErrMemberNotFound) {
+		httputil.WriteNotOkResponseWithErrMsg(c, http.StatusNotFound, httputil.ResponseNotFoundError, err.Error())
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "Member removed")
+	return
+}
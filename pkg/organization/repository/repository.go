@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/organization/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrganizationRepository struct {
+	dbClient *db.DatabaseConnection
+	time     commonTime.TimeInterface
+}
+
+func NewOrganizationRepository(dbClient *db.DatabaseConnection, time commonTime.TimeInterface) *OrganizationRepository {
+	return &OrganizationRepository{
+		dbClient: dbClient,
+		time:     time,
+	}
+}
+
+// InsertOrganization creates the organization with its owner as the first member
+func (r *OrganizationRepository) InsertOrganization(organization *domain.Organization, ownerID int64) (err error) {
+	return r.dbClient.Master.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(organization).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&domain.OrganizationMember{
+			OrganizationID: organization.ID,
+			UserID:         ownerID,
+			CreatedAt:      organization.CreatedAt,
+		}).Error
+	})
+}
+
+func (r *OrganizationRepository) FindOrganizationByID(id int64) (domain.Organization, error) {
+	var organization domain.Organization
+
+	result := r.dbClient.Slave.Where("id = ?", id).First(&organization)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.Organization{}, common.ErrOrganizationNotFound
+	}
+
+	if result.Error != nil {
+		return domain.Organization{}, result.Error
+	}
+
+	return organization, nil
+}
+
+// FindMembershipsByUserID returns the organizations of the user, the one joined first comes first
+func (r *OrganizationRepository) FindMembershipsByUserID(userID int64) ([]domain.Membership, error) {
+	var memberships []domain.Membership
+
+	result := r.dbClient.Slave.Table("organizations").
+		Select("organizations.*, organization_members.created_at AS joined_at").
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ?", userID).
+		Order("organization_members.created_at").Order("organizations.id").
+		Find(&memberships)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return memberships, nil
+}
+
+func (r *OrganizationRepository) IsMember(organizationID, userID int64) (bool, error) {
+	var count int64
+
+	result := r.dbClient.Slave.Model(&domain.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).Count(&count)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return count > 0, nil
+}
+
+func (r *OrganizationRepository) InsertMember(organizationID, userID int64) (err error) {
+	// adding twice is fine
+	result := r.dbClient.Master.Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.OrganizationMember{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		CreatedAt:      r.time.Now(),
+	})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) DeleteMember(organizationID, userID int64) (err error) {
+	result := r.dbClient.Master.Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Delete(&domain.OrganizationMember{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return common.ErrMemberNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	authCommon "github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/organization/common"
+	"log"
+)
+
+type OrganizationUseCase struct {
+	organizationRepo domain.OrganizationRepository
+	userRepo         domain.UserRepository
+	time             commonTime.TimeInterface
+	config           config.Config
+}
+
+func NewOrganizationUseCase(organizationRepo domain.OrganizationRepository, userRepo domain.UserRepository,
+	time commonTime.TimeInterface, config config.Config) *OrganizationUseCase {
+	return &OrganizationUseCase{
+		organizationRepo: organizationRepo,
+		userRepo:         userRepo,
+		time:             time,
+		config:           config,
+	}
+}
+
+// CreateOrganization creates an organization with the current user as its first member.
+func (o *OrganizationUseCase) CreateOrganization(ctx context.Context, request common.CreateOrganizationRequest) (
+	organization domain.Organization, err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = authCommon.ErrAuthUnauthenticated
+		return
+	}
+
+	organization = domain.Organization{
+		Name:      request.Name,
+		CreatedAt: o.time.Now(),
+	}
+
+	if err = o.organizationRepo.InsertOrganization(&organization, userID); err != nil {
+		err = fmt.Errorf("insert organization err: %+v", err)
+		return
+	}
+
+	log.Printf("organization created: organization_id=%d, owner_id=%d", organization.ID, userID)
+	return
+}
+
+// ListOrganizations lists the organizations of the current user, flagging the one the request is made within.
+func (o *OrganizationUseCase) ListOrganizations(ctx context.Context) (organizations []common.OrganizationInfo,
+	err error) {
+	userID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		err = authCommon.ErrAuthUnauthenticated
+		return
+	}
+
+	tenantID, _ := general.GetTenantIDFromCtx(ctx)
+
+	memberships, err := o.organizationRepo.FindMembershipsByUserID(userID)
+	if err != nil {
+		err = fmt.Errorf("find memberships err: %+v", err)
+		return
+	}
+
+	organizations = make([]common.OrganizationInfo, 0, len(memberships))
+	for _, membership := range memberships {
+		organizations = append(organizations, common.OrganizationInfo{
+			ID:       membership.ID,
+			Name:     membership.Name,
+			JoinedAt: membership.JoinedAt,
+			Current:  membership.ID == tenantID,
+		})
+	}
+
+	return
+}
+
+// AddMember adds the user to the organization. Within an organization, users are looked up among its members
+// only, so users from elsewhere are added from outside any organization.
+func (o *OrganizationUseCase) AddMember(ctx context.Context, organizationID, userID int64) (err error) {
+	if err = o.findOrganization(ctx, organizationID); err != nil {
+		return
+	}
+
+	if _, err = o.userRepo.FindUserByID(ctx, userID); err != nil {
+		return common.ErrUserNotFound
+	}
+
+	if err = o.organizationRepo.InsertMember(organizationID, userID); err != nil {
+		return fmt.Errorf("insert member err: %+v", err)
+	}
+
+	log.Printf("member added: organization_id=%d, user_id=%d", organizationID, userID)
+	return nil
+}
+
+// RemoveMember removes the user from the organization, its sessions leave the organization on their next
+// refresh.
+func (o *OrganizationUseCase) RemoveMember(ctx context.Context, organizationID, userID int64) (err error) {
+	if err = o.findOrganization(ctx, organizationID); err != nil {
+		return
+	}
+
+	err = o.organizationRepo.DeleteMember(organizationID, userID)
+	if errors.Is(err, common.ErrMemberNotFound) {
+		return
+	}
+
+	if err != nil {
+		return fmt.Errorf("delete member err: %+v", err)
+	}
+
+	log.Printf("member removed: organization_id=%d, user_id=%d", organizationID, userID)
+	return nil
+}
+
+// find the organization, requests made within an organization never reach another one
+func (o *OrganizationUseCase) findOrganization(ctx context.Context, organizationID int64) (err error) {
+	if tenantID, ok := general.GetTenantIDFromCtx(ctx); ok && tenantID != 0 && tenantID != organizationID {
+		return common.ErrOrganizationNotFound
+	}
+
+	_, err = o.organizationRepo.FindOrganizationByID(organizationID)
+	if errors.Is(err, common.ErrOrganizationNotFound) {
+		return
+	}
+
+	if err != nil {
+		return fmt.Errorf("find organization err: %+v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrRoleNotFound     = fmt.Errorf("role not found")
+	ErrUserNotFound     = fmt.Errorf("user not found")
+	ErrPermissionDenied = fmt.Errorf("permission denied")
+
+	// roles grant their permissions across every organization, so tenants can't hand them out
+	ErrRolesPlatformWide = fmt.Errorf("%w: roles are platform-wide and can't be assigned within an organization",
+		ErrPermissionDenied)
+)
+
+// Built-in roles, seeded on startup
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Permissions are named resource:action, "*" grants everything and "resource:*" every action of a resource
+const (
+	PermissionAll         = "*"
+	PermissionRolesRead   = "roles:read"
+	PermissionRolesAssign = "roles:assign"
+	PermissionUsersUnlock = "users:unlock"
+	PermissionAuditRead   = "audit:read"
+
+	PermissionUsersImpersonate = "users:impersonate"
+
+	PermissionOAuthClientsManage = "oauth-clients:manage"
+
+	PermissionOrganizationsManage = "organizations:manage"
+
+	PermissionInvitationsManage = "invitations:manage"
+)
+
+type AssignRoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// PermissionGranted reports whether required is covered by one of granted permissions
+func PermissionGranted(granted []string, required string) bool {
+	for _, permission := range granted {
+		if permission == PermissionAll || permission == required {
+			return true
+		}
+
+		// resource wildcard
+		if strings.HasSuffix(permission, ":*") && strings.HasPrefix(required, strings.TrimSuffix(permission, "*")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PermissionsCovered reports whether every one of required permissions is covered by granted permissions
+func PermissionsCovered(granted, required []string) bool {
+	for _, permission := range required {
+		if !PermissionGranted(granted, permission) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,53 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPermissionGranted(t *testing.T) {
+	testCases := []struct {
+		name     string
+		granted  []string
+		required string
+		expected bool
+	}{
+		{name: "exact", granted: []string{"roles:read"}, required: "roles:read", expected: true},
+		{name: "all", granted: []string{PermissionAll}, required: "roles:assign", expected: true},
+		{name: "resource_wildcard", granted: []string{"roles:*"}, required: "roles:assign", expected: true},
+		{name: "other_resource_wildcard", granted: []string{"users:*"}, required: "roles:assign", expected: false},
+		{name: "prefix_is_not_resource", granted: []string{"role:*"}, required: "roles:assign", expected: false},
+		{name: "missing", granted: []string{"roles:read"}, required: "roles:assign", expected: false},
+		{name: "none", granted: nil, required: "roles:read", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, PermissionGranted(tc.granted, tc.required))
+		})
+	}
+}
+
+func TestPermissionsCovered(t *testing.T) {
+	testCases := []struct {
+		name     string
+		granted  []string
+		required []string
+		expected bool
+	}{
+		{name: "subset", granted: []string{"roles:read", "users:read"}, required: []string{"users:read"},
+			expected: true},
+		{name: "wildcard", granted: []string{"roles:*"}, required: []string{"roles:read", "roles:assign"},
+			expected: true},
+		{name: "one_missing", granted: []string{"roles:read"}, required: []string{"roles:read", "users:read"},
+			expected: false},
+		{name: "wider_wildcard", granted: []string{"roles:*"}, required: []string{PermissionAll}, expected: false},
+		{name: "nothing_required", granted: nil, required: nil, expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, PermissionsCovered(tc.granted, tc.required))
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package delivery
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	httputil "github.com/lactobasilusprotectus/go-template/pkg/util/http"
+	"net/http"
+	"strconv"
+)
+
+type RbacHttpHandler struct {
+	authMiddleware domain.GinAuthentication
+	rbacUseCase    domain.RbacUseCase
+}
+
+func NewRbacHttpHandler(authMiddleware domain.GinAuthentication, rbacUseCase domain.RbacUseCase) *RbacHttpHandler {
+	return &RbacHttpHandler{
+		authMiddleware: authMiddleware,
+		rbacUseCase:    rbacUseCase,
+	}
+}
+
+func (r *RbacHttpHandler) Register(g *gin.Engine) {
+	admin := g.Group("admin", r.authMiddleware.MustLogin())
+
+	admin.GET("roles", r.authMiddleware.RequirePermission(common.PermissionRolesRead), r.ListRoles)
+	admin.GET("users/:id/roles", r.authMiddleware.RequirePermission(common.PermissionRolesRead), r.ListUserRoles)
+	admin.POST("users/:id/roles", r.authMiddleware.RequirePermission(common.PermissionRolesAssign), r.AssignRole)
+	admin.DELETE("users/:id/roles/:role", r.authMiddleware.RequirePermission(common.PermissionRolesAssign),
+		r.RevokeRole)
+}
+
+// ListRoles			godoc
+//
+//	@Summary		List roles.
+//	@Description	List every role with its permissions.
+//	@Produce		application/json
+//	@Tags			admin
+//	@Security		JWT
+//	@Success		200	{object}	http.BaseResponse{data=[]domain.Role}
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		403	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/admin/roles [get]
+func (r *RbacHttpHandler) ListRoles(c *gin.Context) {
+	// call use case
+	roles, err := r.rbacUseCase.ListRoles(c.Request.Context())
+
+	// handle error
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, roles)
+	return
+}
+
+// ListUserRoles		godoc
+//
+//	@Summary		List roles of a user.
+//	@Description	List roles assigned to the given user.
+//	@Produce		application/json
+//	@Tags			admin
+//	@Security		JWT
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{object}	http.BaseResponse{data=[]domain.Role}
+//	@Failure		400	{object}	http.BaseResponse
+//	@Failure		401	{object}	http.BaseResponse
+//	@Failure		403	{object}	http.BaseResponse
+//	@Failure		404	{object}	http.BaseResponse
+//	@Failure		500	{object}	http.BaseResponse
+//	@Router			/admin/users/{id}/roles [get]
+func (r *RbacHttpHandler) ListUserRoles(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	roles, err := r.rbacUseCase.ListUserRoles(c.Request.Context(), userID)
+
+	// handle error
+	if errors.Is(err, common.ErrUserNotFound) {
+		httputil.WriteNotFoundResponse(c, httputil.ResponseNotFoundError)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, roles)
+	return
+}
+
+// AssignRole			godoc
+//
+//	@Summary		Assign role to a user.
+//	@Description	Assign role to the given user, assigning twice is a no-op. Roles are platform-wide, they can't
+//	@Description	be assigned within an organization. Only roles whose every permission the caller holds can be
+//	@Description	assigned.
+//	@Produce		application/json
+//	@Tags			admin
+//	@Security		JWT
+//	@Param			id		path		int							true	"User ID"
+//	@Param			body	body		common.AssignRoleRequest	true	"Assign Role Request"
+//	@Success		200		{object}	http.BaseResponse
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		404		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/admin/users/{id}/roles [post]
+func (r *RbacHttpHandler) AssignRole(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// init request body
+	var assignRequest common.AssignRoleRequest
+
+	//bind request body
+	if err = c.ShouldBindJSON(&assignRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// validate request body
+	if err = validator.New().Struct(&assignRequest); err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	err = r.rbacUseCase.AssignRole(c.Request.Context(), userID, assignRequest.Role)
+
+	// handle error
+	if errors.Is(err, common.ErrUserNotFound) || errors.Is(err, common.ErrRoleNotFound) {
+		httputil.WriteNotOkResponseWithErrMsg(c, http.StatusNotFound, httputil.ResponseNotFoundError, err.Error())
+		return
+	}
+
+	if errors.Is(err, common.ErrPermissionDenied) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "Role assigned")
+	return
+}
+
+// RevokeRole			godoc
+//
+//	@Summary		Revoke role from a user.
+//	@Description	Revoke role from the given user. Roles are platform-wide, they can't be revoked within an
+//	@Description	organization.
+//	@Produce		application/json
+//	@Tags			admin
+//	@Security		JWT
+//	@Param			id		path		int		true	"User ID"
+//	@Param			role	path		string	true	"Role Name"
+//	@Success		200		{object}	http.BaseResponse
+//	@Failure		400		{object}	http.BaseResponse
+//	@Failure		401		{object}	http.BaseResponse
+//	@Failure		403		{object}	http.BaseResponse
+//	@Failure		404		{object}	http.BaseResponse
+//	@Failure		500		{object}	http.BaseResponse
+//	@Router			/admin/users/{id}/roles/{role} [delete]
+func (r *RbacHttpHandler) RevokeRole(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequestResponseWithErrMsg(c, httputil.ResponseBadRequestError, err)
+		return
+	}
+
+	// call use case
+	err = r.rbacUseCase.RevokeRole(c.Request.Context(), userID, c.Param("role"))
+
+	// handle error
+	if errors.Is(err, common.ErrRoleNotFound) {
+		httputil.WriteNotOkResponseWithErrMsg(c, http.StatusNotFound, httputil.ResponseNotFoundError, err.Error())
+		return
+	}
+
+	if errors.Is(err, common.ErrPermissionDenied) {
+		httputil.WriteForbiddenResponseWithErrMsg(c, err)
+		return
+	}
+
+	if err != nil {
+		httputil.WriteServerErrorResponse(c, httputil.ResponseServerError, err)
+		return
+	}
+
+	// write response
+	httputil.WriteOkResponse(c, "Role revoked")
+	return
+}
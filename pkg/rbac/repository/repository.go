@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"errors"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RbacRepository struct {
+	dbClient *db.DatabaseConnection
+	time     commonTime.TimeInterface
+}
+
+func NewRbacRepository(dbClient *db.DatabaseConnection, time commonTime.TimeInterface) *RbacRepository {
+	return &RbacRepository{
+		dbClient: dbClient,
+		time:     time,
+	}
+}
+
+// EnsureRole creates the role and its permissions when missing, and grants the permissions to it
+func (r *RbacRepository) EnsureRole(name, description string, permissions []string) (domain.Role, error) {
+	role := domain.Role{Name: name, Description: description}
+
+	err := r.dbClient.Master.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(domain.Role{Name: name}).Attrs(domain.Role{Description: description}).
+			FirstOrCreate(&role).Error; err != nil {
+			return err
+		}
+
+		for _, name := range permissions {
+			permission := domain.Permission{Name: name}
+			if err := tx.Where(domain.Permission{Name: name}).FirstOrCreate(&permission).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&role).Association("Permissions").Append(&permission); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return domain.Role{}, err
+	}
+
+	return role, nil
+}
+
+func (r *RbacRepository) FindRoles() ([]domain.Role, error) {
+	var roles []domain.Role
+
+	result := r.dbClient.Slave.Preload("Permissions").Order("name").Find(&roles)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return roles, nil
+}
+
+func (r *RbacRepository) FindRoleByName(name string) (domain.Role, error) {
+	var role domain.Role
+
+	result := r.dbClient.Slave.Preload("Permissions").Where("name = ?", name).First(&role)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.Role{}, common.ErrRoleNotFound
+	}
+
+	if result.Error != nil {
+		return domain.Role{}, result.Error
+	}
+
+	return role, nil
+}
+
+func (r *RbacRepository) FindRolesByUserID(userID int64) ([]domain.Role, error) {
+	var roles []domain.Role
+
+	result := r.dbClient.Slave.Preload("Permissions").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Find(&roles)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return roles, nil
+}
+
+func (r *RbacRepository) FindPermissionsByUserID(userID int64) ([]string, error) {
+	var permissions []string
+
+	result := r.dbClient.Slave.Model(&domain.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN user_roles ON user_roles.role_id = role_permissions.role_id").
+		Where("user_roles.user_id = ?", userID).
+		Distinct().
+		Pluck("permissions.name", &permissions)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return permissions, nil
+}
+
+func (r *RbacRepository) AssignUserRole(userID, roleID int64) (err error) {
+	// assigning twice is fine
+	result := r.dbClient.Master.Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.UserRole{
+		UserID:    userID,
+		RoleID:    roleID,
+		CreatedAt: r.time.Now(),
+	})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+func (r *RbacRepository) RevokeUserRole(userID, roleID int64) (err error) {
+	result := r.dbClient.Master.Where("user_id = ? AND role_id = ?", userID, roleID).Delete(&domain.UserRole{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return common.ErrRoleNotFound
+	}
+
+	return nil
+}
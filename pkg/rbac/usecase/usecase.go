@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	"log"
+)
+
+type RbacUseCase struct {
+	rbacRepo domain.RbacRepository
+	userRepo domain.UserRepository
+	config   config.Config
+}
+
+func NewRbacUseCase(rbacRepo domain.RbacRepository, userRepo domain.UserRepository, config config.Config) *RbacUseCase {
+	return &RbacUseCase{
+		rbacRepo: rbacRepo,
+		userRepo: userRepo,
+		config:   config,
+	}
+}
+
+// SeedDefaultRoles makes sure built-in roles exist, and grants admin role to the configured admin account.
+func (r *RbacUseCase) SeedDefaultRoles() (err error) {
+	admin, err := r.rbacRepo.EnsureRole(common.RoleAdmin, "Full access", []string{common.PermissionAll})
+	if err != nil {
+		return fmt.Errorf("ensure role %s err: %+v", common.RoleAdmin, err)
+	}
+
+	if _, err = r.rbacRepo.EnsureRole(common.RoleUser, "Regular user", nil); err != nil {
+		return fmt.Errorf("ensure role %s err: %+v", common.RoleUser, err)
+	}
+
+	if r.config.AdminEmail == "" {
+		return nil
+	}
+
+	// the account might not be registered yet, it will be granted on next startup
+	user, err := r.userRepo.FindUserByEmail(context.Background(), r.config.AdminEmail)
+	if err != nil {
+		log.Printf("admin account %s not found, skip granting admin role", r.config.AdminEmail)
+		return nil
+	}
+
+	if err = r.rbacRepo.AssignUserRole(user.ID, admin.ID); err != nil {
+		return fmt.Errorf("assign admin role err: %+v", err)
+	}
+
+	return nil
+}
+
+func (r *RbacUseCase) ListRoles(ctx context.Context) (roles []domain.Role, err error) {
+	return r.rbacRepo.FindRoles()
+}
+
+func (r *RbacUseCase) ListUserRoles(ctx context.Context, userID int64) (roles []domain.Role, err error) {
+	if _, err = r.userRepo.FindUserByID(ctx, userID); err != nil {
+		return nil, common.ErrUserNotFound
+	}
+
+	return r.rbacRepo.FindRolesByUserID(userID)
+}
+
+// AssignRole assigns the role to the user. The current user must already hold every permission of the role, so that
+// roles:assign can't be used to escalate privileges.
+func (r *RbacUseCase) AssignRole(ctx context.Context, userID int64, roleName string) (err error) {
+	if err = rejectTenant(ctx); err != nil {
+		return
+	}
+
+	if _, err = r.userRepo.FindUserByID(ctx, userID); err != nil {
+		return common.ErrUserNotFound
+	}
+
+	role, err := r.rbacRepo.FindRoleByName(roleName)
+	if err != nil {
+		return err
+	}
+
+	if err = r.authorizeRoleGrant(ctx, role); err != nil {
+		return
+	}
+
+	if err = r.rbacRepo.AssignUserRole(userID, role.ID); err != nil {
+		return fmt.Errorf("assign role err: %+v", err)
+	}
+
+	log.Printf("role assigned: user_id=%d, role=%s", userID, roleName)
+	return nil
+}
+
+func (r *RbacUseCase) RevokeRole(ctx context.Context, userID int64, roleName string) (err error) {
+	if err = rejectTenant(ctx); err != nil {
+		return
+	}
+
+	role, err := r.rbacRepo.FindRoleByName(roleName)
+	if err != nil {
+		return err
+	}
+
+	if err = r.rbacRepo.RevokeUserRole(userID, role.ID); err != nil {
+		return err
+	}
+
+	log.Printf("role revoked: user_id=%d, role=%s", userID, roleName)
+	return nil
+}
+
+// authorize the current user to grant the role: the user must be allowed to assign roles, through the scopes of the
+// api key as well, and already hold every permission of the role
+func (r *RbacUseCase) authorizeRoleGrant(ctx context.Context, role domain.Role) error {
+	actorID, ok := general.GetUserIDFromCtx(ctx)
+	if !ok {
+		return common.ErrPermissionDenied
+	}
+
+	granted, err := r.rbacRepo.FindPermissionsByUserID(actorID)
+	if err != nil {
+		return fmt.Errorf("find permissions err: %+v", err)
+	}
+
+	// api keys may be restricted to a subset of the user permissions
+	if scopes, _ := general.GetScopesFromCtx(ctx); len(scopes) != 0 {
+		if !common.PermissionGranted(scopes, common.PermissionRolesAssign) {
+			return common.ErrPermissionDenied
+		}
+	}
+
+	required := make([]string, 0, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		required = append(required, permission.Name)
+	}
+
+	if !common.PermissionGranted(granted, common.PermissionRolesAssign) || !common.PermissionsCovered(granted, required) {
+		return common.ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// roles are platform-wide, requests made within an organization can't change who holds them
+func rejectTenant(ctx context.Context) error {
+	if tenantID, ok := general.GetTenantIDFromCtx(ctx); ok && tenantID != 0 {
+		return common.ErrRolesPlatformWide
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/rbac/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRbacUseCase_AssignRole(t *testing.T) {
+	ctx := general.SetUserIDIntoCtx(context.Background(), 1)
+	role := domain.Role{ID: 3, Name: "auditor", Permissions: []domain.Permission{{Name: common.PermissionAuditRead}}}
+
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		granted []string // permissions of the current user
+		err     error
+	}{
+		{name: "assigned", ctx: ctx, granted: []string{common.PermissionRolesAssign, common.PermissionAuditRead}},
+		{name: "assigned by admin", ctx: ctx, granted: []string{common.PermissionAll}},
+		{name: "role beyond own permissions", ctx: ctx, granted: []string{common.PermissionRolesAssign},
+			err: common.ErrPermissionDenied},
+		{name: "not allowed to assign", ctx: ctx, granted: []string{common.PermissionAuditRead},
+			err: common.ErrPermissionDenied},
+		{name: "api key without the scope", ctx: general.SetApiKeyIntoCtx(ctx, 1, []string{common.PermissionAuditRead}),
+			granted: []string{common.PermissionAll}, err: common.ErrPermissionDenied},
+		{name: "api key with the scope", ctx: general.SetApiKeyIntoCtx(ctx, 1, []string{common.PermissionRolesAssign}),
+			granted: []string{common.PermissionAll}},
+		{name: "within an organization", ctx: general.SetTenantIDIntoCtx(ctx, 5), err: common.ErrRolesPlatformWide},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			rbacRepo := domain.NewMockRbacRepository(ctrl)
+			userRepo := domain.NewMockUserRepository(ctrl)
+
+			if !errors.Is(tc.err, common.ErrRolesPlatformWide) {
+				userRepo.EXPECT().FindUserByID(gomock.Any(), int64(2)).Return(domain.User{ID: 2}, nil)
+				rbacRepo.EXPECT().FindRoleByName("auditor").Return(role, nil)
+				rbacRepo.EXPECT().FindPermissionsByUserID(int64(1)).Return(tc.granted, nil)
+			}
+
+			if tc.err == nil {
+				rbacRepo.EXPECT().AssignUserRole(int64(2), int64(3))
+			}
+
+			r := &RbacUseCase{rbacRepo: rbacRepo, userRepo: userRepo}
+
+			err := r.AssignRole(tc.ctx, 2, "auditor")
+			assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemorySessionRepository stores sessions in process memory,
+// it is only suitable for a single instance deployment and tests
+type MemorySessionRepository struct {
+	mu       sync.RWMutex
+	sessions map[string]domain.Session
+	time     commonTime.TimeInterface
+}
+
+func NewMemorySessionRepository(time commonTime.TimeInterface) *MemorySessionRepository {
+	return &MemorySessionRepository{
+		sessions: make(map[string]domain.Session),
+		time:     time,
+	}
+}
+
+func (m *MemorySessionRepository) InsertSession(session domain.Session) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// drop expired sessions, so they don't pile up
+	for id := range m.sessions {
+		if _, ok := m.find(id); !ok {
+			delete(m.sessions, id)
+		}
+	}
+
+	m.sessions[session.ID] = session
+	return nil
+}
+
+func (m *MemorySessionRepository) UpdateSession(session domain.Session) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.find(session.ID); !ok {
+		return common.ErrSessionNotFound
+	}
+
+	m.sessions[session.ID] = session
+	return nil
+}
+
+func (m *MemorySessionRepository) TouchSession(id string, lastSeenAt time.Time) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.find(id)
+	if !ok {
+		return common.ErrSessionNotFound
+	}
+
+	session.LastSeenAt = lastSeenAt
+	m.sessions[id] = session
+	return nil
+}
+
+func (m *MemorySessionRepository) FindSessionByID(id string) (domain.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session, ok := m.find(id)
+	if !ok {
+		return domain.Session{}, common.ErrSessionNotFound
+	}
+
+	return session, nil
+}
+
+func (m *MemorySessionRepository) FindSessionsByUserID(userID int64) ([]domain.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sessions := make([]domain.Session, 0)
+
+	for id, session := range m.sessions {
+		if session.UserID != userID {
+			continue
+		}
+
+		if _, ok := m.find(id); ok {
+			sessions = append(sessions, session)
+		}
+	}
+
+	// map iteration order is random, keep the result stable
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+
+	return sessions, nil
+}
+
+func (m *MemorySessionRepository) DeleteSession(id string) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.find(id); !ok {
+		return common.ErrSessionNotFound
+	}
+
+	delete(m.sessions, id)
+	return nil
+}
+
+// find returns the session if it exists and has not expired, caller must hold the lock
+func (m *MemorySessionRepository) find(id string) (domain.Session, bool) {
+	session, ok := m.sessions[id]
+	if !ok || !session.ExpiresAt.After(m.time.Now()) {
+		return domain.Session{}, false
+	}
+
+	return session, true
+}
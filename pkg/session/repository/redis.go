@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"time"
+)
+
+// RedisSessionRepository stores sessions in redis, each session expires on its own
+type RedisSessionRepository struct {
+	redis redis.Interface
+	time  commonTime.TimeInterface
+}
+
+func NewRedisSessionRepository(redis redis.Interface, time commonTime.TimeInterface) *RedisSessionRepository {
+	return &RedisSessionRepository{
+		redis: redis,
+		time:  time,
+	}
+}
+
+func (r *RedisSessionRepository) InsertSession(session domain.Session) (err error) {
+	return r.save(session, false)
+}
+
+func (r *RedisSessionRepository) UpdateSession(session domain.Session) (err error) {
+	// make sure we don't resurrect a deleted session
+	return r.save(session, true)
+}
+
+// TouchSession records the activity of the session apart from it, so that it doesn't revert concurrent updates
+func (r *RedisSessionRepository) TouchSession(id string, lastSeenAt time.Time) (err error) {
+	ttl, err := r.redis.TTL(sessionCacheKey(id))
+
+	if err != nil {
+		return err
+	}
+
+	// -2 when the session is gone, the activity would outlive it otherwise
+	if ttl <= 0 {
+		return common.ErrSessionNotFound
+	}
+
+	return r.redis.Set(sessionLastSeenCacheKey(id), lastSeenAt.Format(time.RFC3339Nano), ttl)
+}
+
+func (r *RedisSessionRepository) FindSessionByID(id string) (domain.Session, error) {
+	var session domain.Session
+
+	reply, err := r.redis.Get(sessionCacheKey(id))
+
+	if errors.Is(err, redis.ErrNilReturned) {
+		return domain.Session{}, common.ErrSessionNotFound
+	}
+
+	if err != nil {
+		return domain.Session{}, err
+	}
+
+	value, ok := reply.(string)
+	if !ok {
+		return domain.Session{}, fmt.Errorf("unexpected session value type %T", reply)
+	}
+
+	if err = json.Unmarshal([]byte(value), &session); err != nil {
+		return domain.Session{}, err
+	}
+
+	reply, err = r.redis.Get(sessionLastSeenCacheKey(id))
+
+	if errors.Is(err, redis.ErrNilReturned) {
+		return session, nil
+	}
+
+	if err != nil {
+		return domain.Session{}, err
+	}
+
+	lastSeenAt, err := time.Parse(time.RFC3339Nano, fmt.Sprint(reply))
+
+	if err != nil {
+		return domain.Session{}, err
+	}
+
+	if lastSeenAt.After(session.LastSeenAt) {
+		session.LastSeenAt = lastSeenAt
+	}
+
+	return session, nil
+}
+
+func (r *RedisSessionRepository) FindSessionsByUserID(userID int64) ([]domain.Session, error) {
+	ids, err := r.redis.SMembers(userSessionsCacheKey(userID))
+
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]domain.Session, 0, len(ids))
+
+	for _, id := range ids {
+		session, err := r.FindSessionByID(id)
+
+		// session has expired, forget about it
+		if errors.Is(err, common.ErrSessionNotFound) {
+			if err = r.redis.SRem(userSessionsCacheKey(userID), id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+func (r *RedisSessionRepository) DeleteSession(id string) (err error) {
+	session, err := r.FindSessionByID(id)
+
+	if err != nil {
+		return err
+	}
+
+	if err = r.redis.Del(sessionCacheKey(id), sessionLastSeenCacheKey(id)); err != nil {
+		return err
+	}
+
+	return r.redis.SRem(userSessionsCacheKey(session.UserID), id)
+}
+
+// save writes the session and keeps the user index alive as long as its longest living session,
+// only overwriting a stored session when existing is set
+func (r *RedisSessionRepository) save(session domain.Session, existing bool) (err error) {
+	ttl := int(session.ExpiresAt.Sub(r.time.Now()).Seconds())
+
+	if ttl <= 0 && existing {
+		return common.ErrSessionNotFound
+	}
+
+	if ttl <= 0 {
+		return fmt.Errorf("session %s has expired", session.ID)
+	}
+
+	value, err := json.Marshal(session)
+
+	if err != nil {
+		return err
+	}
+
+	if existing {
+		var ok bool
+		if ok, err = r.redis.SetXX(sessionCacheKey(session.ID), string(value), ttl); err != nil {
+			return err
+		}
+
+		if !ok {
+			return common.ErrSessionNotFound
+		}
+	} else if err = r.redis.Set(sessionCacheKey(session.ID), string(value), ttl); err != nil {
+		return err
+	}
+
+	if err = r.redis.SAdd(userSessionsCacheKey(session.UserID), session.ID); err != nil {
+		return err
+	}
+
+	indexTTL, err := r.redis.TTL(userSessionsCacheKey(session.UserID))
+
+	if err != nil {
+		return err
+	}
+
+	if indexTTL >= ttl {
+		return nil
+	}
+
+	return r.redis.Expire(userSessionsCacheKey(session.UserID), ttl)
+}
+
+func sessionCacheKey(id string) string {
+	return fmt.Sprintf("session:%s", id)
+}
+
+func sessionLastSeenCacheKey(id string) string {
+	return fmt.Sprintf("session-last-seen:%s", id)
+}
+
+func userSessionsCacheKey(userID int64) string {
+	return fmt.Sprintf("user-sessions:%d", userID)
+}
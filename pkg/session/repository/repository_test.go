@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/lactobasilusprotectus/go-template/pkg/auth/common"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/lactobasilusprotectus/go-template/pkg/domain"
+	"github.com/lactobasilusprotectus/go-template/pkg/util/redis"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestSessionRepository(t *testing.T) {
+	run, err := miniredis.Run()
+
+	if err != nil {
+		log.Fatalf("miniredis.Run returns err: %+v\n", err)
+	}
+
+	defer run.Close()
+
+	testCases := []struct {
+		name    string
+		newRepo func(clock commonTime.TimeInterface) domain.SessionRepository
+		advance func(d time.Duration) // lets the storage expire keys, besides the clock
+	}{
+		{
+			name: "redis",
+			newRepo: func(clock commonTime.TimeInterface) domain.SessionRepository {
+				return NewRedisSessionRepository(redis.NewRedisClient(config.RedisConfig{Host: run.Addr()}), clock)
+			},
+			advance: run.FastForward,
+		},
+		{
+			name: "memory",
+			newRepo: func(clock commonTime.TimeInterface) domain.SessionRepository {
+				return NewMemorySessionRepository(clock)
+			},
+			advance: func(d time.Duration) {},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			now := time.Unix(time.Now().Unix(), 0).UTC()
+
+			clock := commonTime.NewMockTimeInterface(ctrl)
+			clock.EXPECT().Now().DoAndReturn(func() time.Time { return now }).AnyTimes()
+
+			repo := tc.newRepo(clock)
+
+			first := domain.Session{
+				ID:         "first",
+				UserID:     1,
+				UserAgent:  "agent",
+				ClientIP:   "127.0.0.1",
+				CreatedAt:  now,
+				LastSeenAt: now,
+				ExpiresAt:  now.Add(time.Hour),
+			}
+			second := first
+			second.ID = "second"
+			second.CreatedAt = now.Add(time.Second)
+			second.ExpiresAt = now.Add(2 * time.Hour)
+
+			assert.NoError(t, repo.InsertSession(first))
+			assert.NoError(t, repo.InsertSession(second))
+
+			found, err := repo.FindSessionByID("first")
+			assert.NoError(t, err)
+			assert.Equal(t, first, found)
+
+			// last seen is updated
+			first.LastSeenAt = now.Add(time.Minute)
+			assert.NoError(t, repo.UpdateSession(first))
+
+			// activity is recorded on its own, it doesn't revert other changes of the session
+			second.OrganizationID = 2
+			assert.NoError(t, repo.UpdateSession(second))
+			assert.NoError(t, repo.TouchSession("second", now.Add(2*time.Minute)))
+			second.LastSeenAt = now.Add(2 * time.Minute)
+
+			found, err = repo.FindSessionByID("second")
+			assert.NoError(t, err)
+			assert.Equal(t, second, found)
+
+			sessions, err := repo.FindSessionsByUserID(1)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, []domain.Session{first, second}, sessions)
+
+			// first session expires
+			now = now.Add(time.Hour + time.Second)
+			tc.advance(time.Hour + time.Second)
+
+			_, err = repo.FindSessionByID("first")
+			assert.ErrorIs(t, err, common.ErrSessionNotFound)
+			assert.ErrorIs(t, repo.UpdateSession(first), common.ErrSessionNotFound)
+
+			sessions, err = repo.FindSessionsByUserID(1)
+			assert.NoError(t, err)
+			assert.Equal(t, []domain.Session{second}, sessions)
+
+			// deleted session is gone, and can't be written back
+			assert.NoError(t, repo.DeleteSession("second"))
+			assert.ErrorIs(t, repo.DeleteSession("second"), common.ErrSessionNotFound)
+			assert.ErrorIs(t, repo.UpdateSession(second), common.ErrSessionNotFound)
+			assert.ErrorIs(t, repo.TouchSession("second", now), common.ErrSessionNotFound)
+
+			sessions, err = repo.FindSessionsByUserID(1)
+			assert.NoError(t, err)
+			assert.Empty(t, sessions)
+		})
+	}
+}
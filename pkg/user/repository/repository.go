@@ -1,10 +1,13 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
 	"github.com/lactobasilusprotectus/go-template/pkg/domain"
 	"github.com/lactobasilusprotectus/go-template/pkg/util/db"
+	"gorm.io/gorm"
+	"time"
 )
 
 type UserRepository struct {
@@ -19,8 +22,8 @@ func NewUserRepository(dbClient *db.DatabaseConnection, time commonTime.TimeInte
 	}
 }
 
-func (u *UserRepository) InsertUser(user domain.User) (err error) {
-	result := u.dbClient.Master.Create(&user)
+func (u *UserRepository) InsertUser(ctx context.Context, user domain.User) (err error) {
+	result := u.master(ctx).Create(&user)
 
 	if result.Error != nil {
 		return result.Error
@@ -33,10 +36,10 @@ func (u *UserRepository) InsertUser(user domain.User) (err error) {
 	return nil
 }
 
-func (u *UserRepository) FindUserByEmail(email string) (domain.User, error) {
+func (u *UserRepository) FindUserByEmail(ctx context.Context, email string) (domain.User, error) {
 	var user domain.User
 
-	result := u.dbClient.Slave.Where("email = ?", email).First(&user)
+	result := u.slave(ctx).Where("email = ?", email).First(&user)
 
 	if result.Error != nil {
 		return domain.User{}, result.Error
@@ -45,10 +48,10 @@ func (u *UserRepository) FindUserByEmail(email string) (domain.User, error) {
 	return user, nil
 }
 
-func (u *UserRepository) FindUserByID(id int64) (domain.User, error) {
+func (u *UserRepository) FindUserByID(ctx context.Context, id int64) (domain.User, error) {
 	var user domain.User
 
-	result := u.dbClient.Slave.Where("id = ?", id).First(&user)
+	result := u.slave(ctx).Where("id = ?", id).First(&user)
 
 	if result.Error != nil {
 		return domain.User{}, result.Error
@@ -56,3 +59,90 @@ func (u *UserRepository) FindUserByID(id int64) (domain.User, error) {
 
 	return user, nil
 }
+
+func (u *UserRepository) UpdateUserPassword(ctx context.Context, id int64, password string) (err error) {
+	result := u.master(ctx).Model(&domain.User{}).Where("id = ?", id).Update("password", password)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no row affected")
+	}
+
+	return nil
+}
+
+// UpdateUserPasswordHash replaces the password hash only while it is still the given one
+func (u *UserRepository) UpdateUserPasswordHash(ctx context.Context, id int64, currentHash, newHash string) (err error) {
+	result := u.master(ctx).Model(&domain.User{}).Where("id = ? AND password = ?", id, currentHash).
+		Update("password", newHash)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no row affected")
+	}
+
+	return nil
+}
+
+func (u *UserRepository) UpdateUserEmailVerifiedAt(ctx context.Context, id int64, verifiedAt time.Time) (err error) {
+	result := u.master(ctx).Model(&domain.User{}).Where("id = ?", id).Update("email_verified_at", verifiedAt)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no row affected")
+	}
+
+	return nil
+}
+
+func (u *UserRepository) UpdateUserTotp(ctx context.Context, id int64, secret string, enabledAt *time.Time) (err error) {
+	result := u.master(ctx).Model(&domain.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"totp_secret":     secret,
+		"totp_enabled_at": enabledAt,
+	})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no row affected")
+	}
+
+	return nil
+}
+
+// DeleteUser soft deletes the user, it isn't found anymore until purged for good
+func (u *UserRepository) DeleteUser(ctx context.Context, id int64) (err error) {
+	result := u.master(ctx).Delete(&domain.User{}, id)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no row affected")
+	}
+
+	return nil
+}
+
+// queries within a tenant only reach the members of its organization
+const tenantCondition = "id IN (SELECT user_id FROM organization_members WHERE organization_id = ?)"
+
+func (u *UserRepository) master(ctx context.Context) *gorm.DB {
+	return u.dbClient.Master.WithContext(ctx).Scopes(db.TenantScope(ctx, tenantCondition))
+}
+
+func (u *UserRepository) slave(ctx context.Context) *gorm.DB {
+	return u.dbClient.Slave.WithContext(ctx).Scopes(db.TenantScope(ctx, tenantCondition))
+}
@@ -0,0 +1,20 @@
+package db
+
+import (
+	"context"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"gorm.io/gorm"
+)
+
+// TenantScope restricts a query to the tenant of the context, the condition is given the tenant ID,
+// e.g. "organization_id = ?". Queries made outside any tenant, like logging in, are left as they are.
+func TenantScope(ctx context.Context, condition string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		tenantID, ok := general.GetTenantIDFromCtx(ctx)
+		if !ok || tenantID == 0 {
+			return tx
+		}
+
+		return tx.Where(condition, tenantID)
+	}
+}
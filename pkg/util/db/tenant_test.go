@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"testing"
+)
+
+type tenantRow struct {
+	ID             int64
+	OrganizationID int64
+}
+
+func TestTenantScope(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{
+			name:     "outside any tenant",
+			ctx:      context.Background(),
+			expected: "SELECT * FROM `tenant_rows` WHERE id = ?",
+		},
+		{
+			name:     "zero tenant",
+			ctx:      general.SetTenantIDIntoCtx(context.Background(), 0),
+			expected: "SELECT * FROM `tenant_rows` WHERE id = ?",
+		},
+		{
+			name:     "within tenant",
+			ctx:      general.SetTenantIDIntoCtx(context.Background(), 7),
+			expected: "SELECT * FROM `tenant_rows` WHERE id = ? AND organization_id = ?",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var rows []tenantRow
+			stmt := conn.Scopes(TenantScope(tc.ctx, "organization_id = ?")).Where("id = ?", 1).Find(&rows).Statement
+
+			assert.Equal(t, tc.expected, stmt.SQL.String())
+		})
+	}
+}
@@ -0,0 +1,410 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"os"
+)
+
+var (
+	ErrDatabaseInvalid = errors.New("invalid MaxMind database")
+	ErrIPInvalid       = errors.New("invalid IP address")
+)
+
+// marks the start of the metadata section, which is looked for from the end of the file
+var metadataStartMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// the search tree is followed by 16 zero bytes before the data section starts
+const dataSectionSeparatorSize = 16
+
+// data field types of the MaxMind DB format
+const (
+	typeExtended  = 0
+	typePointer   = 1
+	typeString    = 2
+	typeDouble    = 3
+	typeBytes     = 4
+	typeUint16    = 5
+	typeUint32    = 6
+	typeMap       = 7
+	typeInt32     = 8
+	typeUint64    = 9
+	typeUint128   = 10
+	typeArray     = 11
+	typeContainer = 12
+	typeEndMarker = 13
+	typeBool      = 14
+	typeFloat     = 15
+)
+
+type Interface interface {
+	// Country returns the ISO 3166-1 alpha-2 code of the country the IP is located in, empty when unknown
+	Country(ip string) (isoCode string, err error)
+}
+
+// Reader looks IPs up in a MaxMind DB file, such as GeoLite2-Country or GeoLite2-City, loaded in memory
+type Reader struct {
+	buffer     []byte
+	data       []byte
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	ipv4Start  uint
+}
+
+// Open loads the MaxMind DB file at the given path
+func Open(path string) (reader *Reader, err error) {
+	buffer, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read geoip database err: %+v", err)
+	}
+
+	return New(buffer)
+}
+
+// New reads a MaxMind DB from its content
+func New(buffer []byte) (reader *Reader, err error) {
+	markerIndex := bytes.LastIndex(buffer, metadataStartMarker)
+	if markerIndex < 0 {
+		return nil, ErrDatabaseInvalid
+	}
+
+	metadataStart := markerIndex + len(metadataStartMarker)
+	metadata, _, err := decoder{buffer: buffer[metadataStart:]}.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("decode geoip metadata err: %+v", err)
+	}
+
+	fields, ok := metadata.(map[string]interface{})
+	if !ok {
+		return nil, ErrDatabaseInvalid
+	}
+
+	reader = &Reader{
+		buffer:     buffer,
+		nodeCount:  uintField(fields, "node_count"),
+		recordSize: uintField(fields, "record_size"),
+		ipVersion:  uintField(fields, "ip_version"),
+	}
+
+	if reader.recordSize != 24 && reader.recordSize != 28 && reader.recordSize != 32 {
+		return nil, fmt.Errorf("%w: unsupported record size %d", ErrDatabaseInvalid, reader.recordSize)
+	}
+
+	if reader.ipVersion != 4 && reader.ipVersion != 6 {
+		return nil, fmt.Errorf("%w: unsupported ip version %d", ErrDatabaseInvalid, reader.ipVersion)
+	}
+
+	treeSize := reader.nodeCount * reader.recordSize / 4
+	if treeSize+dataSectionSeparatorSize > uint(markerIndex) {
+		return nil, ErrDatabaseInvalid
+	}
+
+	reader.data = buffer[treeSize+dataSectionSeparatorSize : markerIndex]
+
+	// IPv4 addresses live under ::/96 of IPv6 trees
+	if reader.ipVersion == 6 {
+		for i := 0; i < 96 && reader.ipv4Start < reader.nodeCount; i++ {
+			if reader.ipv4Start, err = reader.readNode(reader.ipv4Start, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return reader, nil
+}
+
+// Country returns the ISO 3166-1 alpha-2 code of the country the IP is located in, falling back to the country
+// the network is registered in. It is empty when the database doesn't know the IP.
+func (r *Reader) Country(ip string) (isoCode string, err error) {
+	record, err := r.Lookup(ip)
+	if err != nil || record == nil {
+		return "", err
+	}
+
+	for _, key := range []string{"country", "registered_country"} {
+		if country, ok := record[key].(map[string]interface{}); ok {
+			if isoCode, ok = country["iso_code"].(string); ok && isoCode != "" {
+				return isoCode, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// Lookup returns the record of the network the IP belongs to, nil when there is none
+func (r *Reader) Lookup(ip string) (record map[string]interface{}, err error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, ErrIPInvalid
+	}
+
+	node := uint(0)
+	address := parsed.To4()
+
+	switch {
+	case address != nil && r.ipVersion == 6:
+		node = r.ipv4Start
+	case address == nil && r.ipVersion == 4:
+		// IPv6 addresses can't be found in IPv4 only databases
+		return nil, nil
+	case address == nil:
+		address = parsed.To16()
+	}
+
+	for i := 0; i < len(address)*8 && node < r.nodeCount; i++ {
+		bit := uint(address[i/8]>>(7-i%8)) & 1
+		if node, err = r.readNode(node, bit); err != nil {
+			return nil, err
+		}
+	}
+
+	// node count itself means no data
+	if node <= r.nodeCount {
+		return nil, nil
+	}
+
+	offset := node - r.nodeCount - dataSectionSeparatorSize
+	value, _, err := decoder{buffer: r.data}.decode(offset, 0)
+	if err != nil {
+		return nil, fmt.Errorf("decode geoip record err: %+v", err)
+	}
+
+	record, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, ErrDatabaseInvalid
+	}
+
+	return record, nil
+}
+
+// read the left (bit 0) or right (bit 1) record of the node
+func (r *Reader) readNode(node, bit uint) (uint, error) {
+	nodeSize := r.recordSize / 4
+	offset := node * nodeSize
+	if offset+nodeSize > uint(len(r.buffer)) {
+		return 0, ErrDatabaseInvalid
+	}
+
+	b := r.buffer[offset : offset+nodeSize]
+
+	switch r.recordSize {
+	case 24:
+		if bit == 0 {
+			return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3])<<16 | uint(b[4])<<8 | uint(b[5]), nil
+	case 28:
+		// the middle byte holds the high bits of both records
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6]), nil
+	default:
+		if bit == 0 {
+			return uint(binary.BigEndian.Uint32(b[:4])), nil
+		}
+		return uint(binary.BigEndian.Uint32(b[4:])), nil
+	}
+}
+
+func uintField(fields map[string]interface{}, key string) uint {
+	value, _ := fields[key].(uint64)
+	return uint(value)
+}
+
+// decoder decodes the data section, pointers are offsets within its buffer
+type decoder struct {
+	buffer []byte
+}
+
+// nested pointers and containers are bounded, so a corrupted database can't recurse forever
+const maxDecodeDepth = 64
+
+// decode the field at the offset, returning the offset following it
+func (d decoder) decode(offset uint, depth int) (value interface{}, next uint, err error) {
+	if depth > maxDecodeDepth {
+		return nil, 0, ErrDatabaseInvalid
+	}
+
+	fieldType, size, offset, err := d.decodeControl(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if fieldType == typePointer {
+		pointer, next, err := d.decodePointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		value, _, err = d.decode(pointer, depth+1)
+		return value, next, err
+	}
+
+	switch fieldType {
+	case typeMap:
+		fields := make(map[string]interface{}, size)
+		for i := uint(0); i < size; i++ {
+			var key, field interface{}
+			if key, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+
+			name, ok := key.(string)
+			if !ok {
+				return nil, 0, ErrDatabaseInvalid
+			}
+
+			if field, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+
+			fields[name] = field
+		}
+		return fields, offset, nil
+	case typeArray:
+		items := make([]interface{}, 0, size)
+		for i := uint(0); i < size; i++ {
+			var item interface{}
+			if item, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+
+			items = append(items, item)
+		}
+		return items, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	}
+
+	if offset+size > uint(len(d.buffer)) {
+		return nil, 0, ErrDatabaseInvalid
+	}
+
+	b := d.buffer[offset : offset+size]
+	next = offset + size
+
+	switch fieldType {
+	case typeString:
+		return string(b), next, nil
+	case typeBytes:
+		return append([]byte(nil), b...), next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, ErrDatabaseInvalid
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, ErrDatabaseInvalid
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), next, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > 8 {
+			return nil, 0, ErrDatabaseInvalid
+		}
+
+		var number uint64
+		for _, digit := range b {
+			number = number<<8 | uint64(digit)
+		}
+		return number, next, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, ErrDatabaseInvalid
+		}
+
+		var number uint32
+		for _, digit := range b {
+			number = number<<8 | uint32(digit)
+		}
+		return int32(number), next, nil
+	case typeUint128:
+		return new(big.Int).SetBytes(b), next, nil
+	default:
+		return nil, 0, fmt.Errorf("%w: unexpected data type %d", ErrDatabaseInvalid, fieldType)
+	}
+}
+
+// decode the control byte, and extended type and size bytes, of the field at the offset
+func (d decoder) decodeControl(offset uint) (fieldType, size, next uint, err error) {
+	if offset >= uint(len(d.buffer)) {
+		return 0, 0, 0, ErrDatabaseInvalid
+	}
+
+	control := d.buffer[offset]
+	offset++
+
+	fieldType = uint(control >> 5)
+	if fieldType == typeExtended {
+		if offset >= uint(len(d.buffer)) {
+			return 0, 0, 0, ErrDatabaseInvalid
+		}
+
+		fieldType = 7 + uint(d.buffer[offset])
+		offset++
+
+		if fieldType < typeInt32 || fieldType > typeFloat {
+			return 0, 0, 0, fmt.Errorf("%w: unexpected data type %d", ErrDatabaseInvalid, fieldType)
+		}
+	}
+
+	size = uint(control & 0x1F)
+
+	// pointers encode their value in the size bits
+	if fieldType == typePointer || size < 29 {
+		return fieldType, size, offset, nil
+	}
+
+	extraBytes := size - 28
+	if offset+extraBytes > uint(len(d.buffer)) {
+		return 0, 0, 0, ErrDatabaseInvalid
+	}
+
+	var extra uint
+	for _, b := range d.buffer[offset : offset+extraBytes] {
+		extra = extra<<8 | uint(b)
+	}
+
+	switch size {
+	case 29:
+		size = 29 + extra
+	case 30:
+		size = 285 + extra
+	default:
+		size = 65821 + extra
+	}
+
+	return fieldType, size, offset + extraBytes, nil
+}
+
+// decode the pointer whose size bits are given, returning the offset it points to
+func (d decoder) decodePointer(sizeBits, offset uint) (pointer, next uint, err error) {
+	pointerSize := (sizeBits >> 3) & 0x3
+	if offset+pointerSize+1 > uint(len(d.buffer)) {
+		return 0, 0, ErrDatabaseInvalid
+	}
+
+	b := d.buffer[offset : offset+pointerSize+1]
+	next = offset + pointerSize + 1
+
+	switch pointerSize {
+	case 0:
+		pointer = (sizeBits&0x7)<<8 | uint(b[0])
+	case 1:
+		pointer = ((sizeBits&0x7)<<16 | uint(b[0])<<8 | uint(b[1])) + 2048
+	case 2:
+		pointer = ((sizeBits&0x7)<<24 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])) + 526336
+	default:
+		pointer = uint(binary.BigEndian.Uint32(b))
+	}
+
+	return pointer, next, nil
+}
@@ -0,0 +1,159 @@
+package geoip
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// records of the test databases, the second one reuses keys of the first one through pointers
+var testRecords = []byte{
+	0xE1,                                    // map of 1
+	0x47, 'c', 'o', 'u', 'n', 't', 'r', 'y', // offset 1
+	0xE1,
+	0x48, 'i', 's', 'o', '_', 'c', 'o', 'd', 'e', // offset 10
+	0x42, 'I', 'D',
+	// offset 22
+	0xE1,
+	0x20, 0x01, // pointer to "country"
+	0xE1,
+	0x20, 0x0A, // pointer to "iso_code"
+	0x42, 'U', 'S',
+	// offset 31
+	0xE1,
+	0x52, 'r', 'e', 'g', 'i', 's', 't', 'e', 'r', 'e', 'd', '_', 'c', 'o', 'u', 'n', 't', 'r', 'y',
+	0xE1,
+	0x20, 0x0A,
+	0x42, 'S', 'G',
+}
+
+// build a database of the given nodes, records pointing to data are given as offsets within testRecords
+func buildDatabase(ipVersion, recordSize uint, nodes [][2]uint) []byte {
+	nodeCount := uint(len(nodes))
+
+	var database []byte
+	for _, node := range nodes {
+		left, right := node[0], node[1]
+		switch recordSize {
+		case 24:
+			database = append(database, byte(left>>16), byte(left>>8), byte(left),
+				byte(right>>16), byte(right>>8), byte(right))
+		case 28:
+			database = append(database, byte(left>>16), byte(left>>8), byte(left),
+				byte(left>>20&0xF0|right>>24&0x0F), byte(right>>16), byte(right>>8), byte(right))
+		default:
+			database = append(database, byte(left>>24), byte(left>>16), byte(left>>8), byte(left),
+				byte(right>>24), byte(right>>16), byte(right>>8), byte(right))
+		}
+	}
+
+	database = append(database, make([]byte, dataSectionSeparatorSize)...)
+	database = append(database, testRecords...)
+	database = append(database, metadataStartMarker...)
+	database = append(database, 0xE3,
+		0x4A, 'n', 'o', 'd', 'e', '_', 'c', 'o', 'u', 'n', 't', 0xC2, byte(nodeCount>>8), byte(nodeCount),
+		0x4B, 'r', 'e', 'c', 'o', 'r', 'd', '_', 's', 'i', 'z', 'e', 0xA1, byte(recordSize),
+		0x4A, 'i', 'p', '_', 'v', 'e', 'r', 's', 'i', 'o', 'n', 0xA1, byte(ipVersion))
+
+	return database
+}
+
+// record pointing to the given offset of testRecords, for a tree of the given node count
+func dataRecord(nodeCount, offset uint) uint {
+	return nodeCount + dataSectionSeparatorSize + offset
+}
+
+func TestReader_Country(t *testing.T) {
+	// IPv4 tree: 0.0.0.0/2 is ID, 64.0.0.0/2 is US, 128.0.0.0/2 is registered in SG, 192.0.0.0/2 is unknown
+	ipv4Nodes := [][2]uint{
+		{1, 2},
+		{dataRecord(3, 0), dataRecord(3, 22)},
+		{dataRecord(3, 31), 3},
+	}
+
+	// IPv6 tree: IPv4 addresses under ::/96, 0.0.0.0/1 is ID, everything else is unknown
+	var ipv6Nodes [][2]uint
+	for i := uint(0); i < 96; i++ {
+		ipv6Nodes = append(ipv6Nodes, [2]uint{i + 1, 97})
+	}
+	ipv6Nodes = append(ipv6Nodes, [2]uint{dataRecord(97, 0), 97})
+
+	testCases := []struct {
+		name       string
+		ipVersion  uint
+		recordSize uint
+		nodes      [][2]uint
+		ip         string
+		isoCode    string
+	}{
+		{name: "ipv4 24 bits", ipVersion: 4, recordSize: 24, nodes: ipv4Nodes, ip: "10.0.0.1", isoCode: "ID"},
+		{name: "ipv4 28 bits", ipVersion: 4, recordSize: 28, nodes: ipv4Nodes, ip: "10.0.0.1", isoCode: "ID"},
+		{name: "ipv4 32 bits", ipVersion: 4, recordSize: 32, nodes: ipv4Nodes, ip: "10.0.0.1", isoCode: "ID"},
+		{name: "pointers", ipVersion: 4, recordSize: 24, nodes: ipv4Nodes, ip: "100.64.0.1", isoCode: "US"},
+		{name: "registered country", ipVersion: 4, recordSize: 24, nodes: ipv4Nodes, ip: "172.16.0.1",
+			isoCode: "SG"},
+		{name: "unknown", ipVersion: 4, recordSize: 24, nodes: ipv4Nodes, ip: "203.0.113.1"},
+		{name: "ipv6 in ipv4 database", ipVersion: 4, recordSize: 24, nodes: ipv4Nodes, ip: "2001:db8::1"},
+		{name: "ipv4 in ipv6 database", ipVersion: 6, recordSize: 28, nodes: ipv6Nodes, ip: "10.0.0.1",
+			isoCode: "ID"},
+		{name: "unknown ipv4 in ipv6 database", ipVersion: 6, recordSize: 28, nodes: ipv6Nodes, ip: "192.0.2.1"},
+		{name: "unknown ipv6", ipVersion: 6, recordSize: 28, nodes: ipv6Nodes, ip: "2001:db8::1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader, err := New(buildDatabase(tc.ipVersion, tc.recordSize, tc.nodes))
+			require.NoError(t, err)
+
+			isoCode, err := reader.Country(tc.ip)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.isoCode, isoCode)
+		})
+	}
+}
+
+func TestReader_Country_invalidIP(t *testing.T) {
+	reader, err := New(buildDatabase(4, 24, [][2]uint{{dataRecord(1, 0), 1}}))
+	require.NoError(t, err)
+
+	_, err = reader.Country("not an ip")
+
+	assert.ErrorIs(t, err, ErrIPInvalid)
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "GeoLite2-Country.mmdb")
+	require.NoError(t, os.WriteFile(path, buildDatabase(4, 24, [][2]uint{{dataRecord(1, 0), 1}}), 0600))
+
+	reader, err := Open(path)
+	require.NoError(t, err)
+
+	isoCode, err := reader.Country("127.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "ID", isoCode)
+
+	_, err = Open(filepath.Join(t.TempDir(), "missing.mmdb"))
+	assert.Error(t, err)
+}
+
+func TestNew_invalid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		database []byte
+	}{
+		{name: "not a database", database: []byte("hello world")},
+		{name: "unsupported record size", database: buildDatabase(4, 16, [][2]uint{{1, 1}})},
+		{name: "truncated tree", database: buildDatabase(4, 24, make([][2]uint, 1000))[6000:]},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.database)
+
+			assert.ErrorIs(t, err, ErrDatabaseInvalid)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+)
+
+// ClientInfoMiddleware writes user agent and client IP into the request context,
+// so use cases can access them without depending on gin
+func ClientInfoMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := general.SetClientInfoIntoCtx(c.Request.Context(), c.Request.UserAgent(), c.ClientIP())
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,130 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ErrCsrfTokenInvalid = errors.New("csrf token missing or invalid")
+
+// SetAuthCookies keeps the token pair in HttpOnly cookies living as long as the given lifetime, along with a new
+// CSRF token readable by the client. The CSRF token is returned as well, for clients of another origin.
+func SetAuthCookies(c *gin.Context, cfg config.CookieConfig, accessToken, refreshToken string,
+	lifetime time.Duration) (csrfToken string, err error) {
+	csrfToken, err = general.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	maxAge := int(lifetime.Seconds())
+	setCookie(c, cfg, general.AccessTokenCookie, "/", accessToken, maxAge, true)
+	setCookie(c, cfg, general.RefreshTokenCookie, general.RefreshTokenCookiePath, refreshToken, maxAge, true)
+	setCookie(c, cfg, general.CsrfTokenCookie, "/", csrfToken, maxAge, false)
+
+	return csrfToken, nil
+}
+
+// ClearAuthCookies removes the cookies set by SetAuthCookies
+func ClearAuthCookies(c *gin.Context, cfg config.CookieConfig) {
+	setCookie(c, cfg, general.AccessTokenCookie, "/", "", -1, true)
+	setCookie(c, cfg, general.RefreshTokenCookie, general.RefreshTokenCookiePath, "", -1, true)
+	setCookie(c, cfg, general.CsrfTokenCookie, "/", "", -1, false)
+}
+
+// SetBindingCookie keeps a value binding a login flow to the browser starting it, in an HttpOnly cookie sent to
+// the given path only. The flow comes back by a link or redirect from another site, so strict SameSite is relaxed to
+// lax for the cookie to be sent along.
+func SetBindingCookie(c *gin.Context, cfg config.CookieConfig, name, path, value string, lifetime time.Duration) {
+	setBindingCookie(c, cfg, name, path, value, int(lifetime.Seconds()))
+}
+
+// ClearBindingCookie removes the cookie set by SetBindingCookie
+func ClearBindingCookie(c *gin.Context, cfg config.CookieConfig, name, path string) {
+	setBindingCookie(c, cfg, name, path, "", -1)
+}
+
+// CsrfMiddleware rejects state-changing requests authenticated by the cookies of the browser session mode, unless
+// they send the CSRF cookie back in the CSRF header, which other sites can't read. Requests with an Authorization
+// header can't be forged by other sites, so they go through like requests without cookies.
+func CsrfMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isSafeMethod(c.Request.Method) || c.GetHeader("Authorization") != "" || !hasAuthCookie(c) {
+			c.Next()
+			return
+		}
+
+		cookie, err := c.Cookie(general.CsrfTokenCookie)
+		header := c.GetHeader(general.CsrfTokenHeader)
+
+		if err != nil || cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) != 1 {
+			WriteForbiddenResponseWithErrMsg(c, ErrCsrfTokenInvalid)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func setCookie(c *gin.Context, cfg config.CookieConfig, name, path, value string, maxAge int, httpOnly bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   cfg.Domain,
+		MaxAge:   maxAge,
+		Secure:   cfg.Secure,
+		HttpOnly: httpOnly,
+		SameSite: sameSite(cfg.SameSite),
+	})
+}
+
+func setBindingCookie(c *gin.Context, cfg config.CookieConfig, name, path, value string, maxAge int) {
+	mode := sameSite(cfg.SameSite)
+	if mode == http.SameSiteStrictMode {
+		mode = http.SameSiteLaxMode
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   cfg.Domain,
+		MaxAge:   maxAge,
+		Secure:   cfg.Secure,
+		HttpOnly: true,
+		SameSite: mode,
+	})
+}
+
+func sameSite(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions ||
+		method == http.MethodTrace
+}
+
+func hasAuthCookie(c *gin.Context) bool {
+	for _, name := range []string{general.AccessTokenCookie, general.RefreshTokenCookie} {
+		if cookie, err := c.Cookie(name); err == nil && cookie != "" {
+			return true
+		}
+	}
+
+	return false
+}
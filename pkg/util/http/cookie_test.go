@@ -0,0 +1,225 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSetAuthCookies(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+
+	csrfToken, err := SetAuthCookies(c, config.CookieConfig{Domain: "example.com", Secure: true, SameSite: "strict"},
+		"access", "refresh", time.Hour)
+	require.NoError(t, err)
+	assert.NotEmpty(t, csrfToken)
+
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range recorder.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+
+	testCases := []struct {
+		name     string
+		value    string
+		path     string
+		httpOnly bool
+	}{
+		{name: general.AccessTokenCookie, value: "access", path: "/", httpOnly: true},
+		{name: general.RefreshTokenCookie, value: "refresh", path: general.RefreshTokenCookiePath, httpOnly: true},
+		{name: general.CsrfTokenCookie, value: csrfToken, path: "/", httpOnly: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cookie, ok := cookies[tc.name]
+			require.True(t, ok)
+
+			assert.Equal(t, tc.value, cookie.Value)
+			assert.Equal(t, tc.httpOnly, cookie.HttpOnly)
+			assert.True(t, cookie.Secure)
+			assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+			assert.Equal(t, "example.com", cookie.Domain)
+			assert.Equal(t, tc.path, cookie.Path)
+			assert.Equal(t, 3600, cookie.MaxAge)
+		})
+	}
+}
+
+func TestClearAuthCookies(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+
+	ClearAuthCookies(c, config.CookieConfig{})
+
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 3)
+
+	for _, cookie := range cookies {
+		assert.Empty(t, cookie.Value)
+		assert.Negative(t, cookie.MaxAge)
+	}
+}
+
+func TestSetBindingCookie(t *testing.T) {
+	testCases := []struct {
+		name     string
+		sameSite string
+		expected http.SameSite
+	}{
+		{name: "strict relaxed to lax", sameSite: "strict", expected: http.SameSiteLaxMode},
+		{name: "lax", sameSite: "lax", expected: http.SameSiteLaxMode},
+		{name: "none", sameSite: "none", expected: http.SameSiteNoneMode},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+
+			SetBindingCookie(c, config.CookieConfig{Secure: true, SameSite: tc.sameSite}, "binding", "/flow",
+				"value", time.Minute)
+
+			cookies := recorder.Result().Cookies()
+			require.Len(t, cookies, 1)
+
+			assert.Equal(t, "binding", cookies[0].Name)
+			assert.Equal(t, "value", cookies[0].Value)
+			assert.Equal(t, "/flow", cookies[0].Path)
+			assert.Equal(t, 60, cookies[0].MaxAge)
+			assert.True(t, cookies[0].HttpOnly)
+			assert.True(t, cookies[0].Secure)
+			assert.Equal(t, tc.expected, cookies[0].SameSite)
+		})
+	}
+}
+
+func TestCsrfMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	g := gin.New()
+	g.Use(CsrfMiddleware())
+	g.Any("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	testCases := []struct {
+		name          string
+		method        string
+		authorization string
+		cookies       map[string]string
+		csrfHeader    string
+		expected      int
+	}{
+		{
+			name:     "without cookies",
+			method:   http.MethodPost,
+			expected: http.StatusOK,
+		},
+		{
+			name:     "safe method",
+			method:   http.MethodGet,
+			cookies:  map[string]string{general.AccessTokenCookie: "access"},
+			expected: http.StatusOK,
+		},
+		{
+			name:          "authorization header",
+			method:        http.MethodPost,
+			authorization: "Bearer access",
+			cookies:       map[string]string{general.AccessTokenCookie: "access"},
+			expected:      http.StatusOK,
+		},
+		{
+			name:       "matching csrf token",
+			method:     http.MethodDelete,
+			cookies:    map[string]string{general.AccessTokenCookie: "access", general.CsrfTokenCookie: "csrf"},
+			csrfHeader: "csrf",
+			expected:   http.StatusOK,
+		},
+		{
+			name:     "missing csrf header",
+			method:   http.MethodPost,
+			cookies:  map[string]string{general.AccessTokenCookie: "access", general.CsrfTokenCookie: "csrf"},
+			expected: http.StatusForbidden,
+		},
+		{
+			name:       "mismatching csrf token",
+			method:     http.MethodPost,
+			cookies:    map[string]string{general.RefreshTokenCookie: "refresh", general.CsrfTokenCookie: "csrf"},
+			csrfHeader: "forged",
+			expected:   http.StatusForbidden,
+		},
+		{
+			name:       "missing csrf cookie",
+			method:     http.MethodPut,
+			cookies:    map[string]string{general.AccessTokenCookie: "access"},
+			csrfHeader: "csrf",
+			expected:   http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest(tc.method, "/", nil)
+			if tc.authorization != "" {
+				request.Header.Set("Authorization", tc.authorization)
+			}
+
+			if tc.csrfHeader != "" {
+				request.Header.Set(general.CsrfTokenHeader, tc.csrfHeader)
+			}
+
+			for name, value := range tc.cookies {
+				request.AddCookie(&http.Cookie{Name: name, Value: value})
+			}
+
+			recorder := httptest.NewRecorder()
+			g.ServeHTTP(recorder, request)
+
+			assert.Equal(t, tc.expected, recorder.Code)
+		})
+	}
+}
+
+func TestCorsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name           string
+		allowedOrigins string
+		origin         string
+		allowOrigin    string
+		allowCreds     string
+	}{
+		{name: "any origin", origin: "https://app.example.com", allowOrigin: "*"},
+		{name: "configured origin", allowedOrigins: "https://app.example.com, https://admin.example.com",
+			origin: "https://admin.example.com", allowOrigin: "https://admin.example.com", allowCreds: "true"},
+		{name: "other origin", allowedOrigins: "https://app.example.com", origin: "https://evil.example.com"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := gin.New()
+			g.Use(corsMiddleware(tc.allowedOrigins))
+			g.GET("/", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.Header.Set("Origin", tc.origin)
+
+			recorder := httptest.NewRecorder()
+			g.ServeHTTP(recorder, request)
+
+			assert.Equal(t, tc.allowOrigin, recorder.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, tc.allowCreds, recorder.Header().Get("Access-Control-Allow-Credentials"))
+		})
+	}
+}
@@ -12,11 +12,22 @@ const (
 	ResponseOk                   = "OK"
 	ResponseServerError          = "SERVER_ERROR"
 	ResponseBadRequestError      = "BAD_REQUEST"
+	ResponseNotFoundError        = "NOT_FOUND"
 	ResponseTimedOut             = "TIMED_OUT"
 	ResponseUnauthorizedError    = "UNAUTHORIZED"
 	ResponseUnauthenticatedError = "UNAUTHENTICATED"
+	ResponseForbiddenError       = "FORBIDDEN"
+	ResponseTooManyRequestsError = "TOO_MANY_REQUESTS"
+	ResponseConflictError        = "CONFLICT"
 )
 
+// FieldError tells why a field of the request is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
 // BaseResponse represents base http response
 type BaseResponse struct {
 	Status       string      `json:"status"`
@@ -57,6 +68,17 @@ func WriteBadRequestResponseWithErrMsg(ctx *gin.Context, status string, err erro
 	WriteNotOkResponseWithErrMsg(ctx, http.StatusBadRequest, status, err.Error())
 }
 
+// WriteValidationErrorResponse writes 400 response listing the invalid fields as data
+func WriteValidationErrorResponse(ctx *gin.Context, fieldErrors []FieldError) {
+	resp := BaseResponse{
+		Status:       ResponseBadRequestError,
+		ErrorMessage: "validation failed",
+		Data:         fieldErrors,
+	}
+
+	WriteResponse(ctx, resp, http.StatusBadRequest)
+}
+
 func WriteNotFoundResponse(ctx *gin.Context, status string) {
 	WriteNotOkResponse(ctx, http.StatusNotFound, status)
 }
@@ -69,6 +91,46 @@ func WriteUnauthenticatedResponse(ctx *gin.Context) {
 	WriteNotOkResponse(ctx, http.StatusUnauthorized, ResponseUnauthenticatedError)
 }
 
+func WriteUnauthenticatedResponseWithErrMsg(ctx *gin.Context, err error) {
+	if err == nil {
+		WriteUnauthenticatedResponse(ctx)
+		return
+	}
+
+	WriteNotOkResponseWithErrMsg(ctx, http.StatusUnauthorized, ResponseUnauthenticatedError, err.Error())
+}
+
+func WriteForbiddenResponse(ctx *gin.Context) {
+	WriteNotOkResponse(ctx, http.StatusForbidden, ResponseForbiddenError)
+}
+
+func WriteForbiddenResponseWithErrMsg(ctx *gin.Context, err error) {
+	if err == nil {
+		WriteForbiddenResponse(ctx)
+		return
+	}
+
+	WriteNotOkResponseWithErrMsg(ctx, http.StatusForbidden, ResponseForbiddenError, err.Error())
+}
+
+func WriteConflictResponseWithErrMsg(ctx *gin.Context, err error) {
+	if err == nil {
+		WriteNotOkResponse(ctx, http.StatusConflict, ResponseConflictError)
+		return
+	}
+
+	WriteNotOkResponseWithErrMsg(ctx, http.StatusConflict, ResponseConflictError, err.Error())
+}
+
+func WriteTooManyRequestsResponseWithErrMsg(ctx *gin.Context, err error) {
+	if err == nil {
+		WriteNotOkResponse(ctx, http.StatusTooManyRequests, ResponseTooManyRequestsError)
+		return
+	}
+
+	WriteNotOkResponseWithErrMsg(ctx, http.StatusTooManyRequests, ResponseTooManyRequestsError, err.Error())
+}
+
 func WriteTimedOutResponse(ctx *gin.Context) {
 	WriteNotOkResponse(ctx, http.StatusGatewayTimeout, ResponseTimedOut)
 }
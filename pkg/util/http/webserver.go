@@ -6,8 +6,10 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -36,6 +38,11 @@ func NewServer(opt config.HttpConfig) *Server {
 	// Creates a router without any middleware by default
 	g := gin.New()
 
+	// client IPs are taken from X-Forwarded-For only when sent by a trusted proxy, anyone could forge it otherwise
+	if err := g.SetTrustedProxies(splitList(opt.TrustedProxies)); err != nil {
+		log.Fatalf("invalid trusted proxies: %s\n", err)
+	}
+
 	//==================================================================================================
 	// Global Middleware
 	//==================================================================================================
@@ -43,7 +50,10 @@ func NewServer(opt config.HttpConfig) *Server {
 	// Cors middleware will handle the OPTIONS method and add the corresponding headers to the response
 	// Logger middleware will write the logs to gin.DefaultWriter even if you set with GIN_MODE=release.
 	// Recovery middleware recovers from any panics and writes a 500 if there was one.
-	g.Use(cors.Default(), gin.Recovery(), LoggingMiddleware())
+	// ClientInfo middleware exposes user agent and client IP through the request context.
+	// Csrf middleware protects state-changing requests authenticated by cookies.
+	g.Use(corsMiddleware(opt.CorsAllowedOrigins), gin.Recovery(), LoggingMiddleware(), ClientInfoMiddleware(),
+		CsrfMiddleware())
 
 	return &Server{
 		port: opt.Port,
@@ -57,6 +67,34 @@ func NewServer(opt config.HttpConfig) *Server {
 	}
 }
 
+// corsMiddleware allows the given comma separated origins to send credentials, such as the cookies of the browser
+// session mode. Without any, every origin is allowed but can't send credentials.
+func corsMiddleware(allowedOrigins string) gin.HandlerFunc {
+	origins := splitList(allowedOrigins)
+	if len(origins) == 0 {
+		return cors.Default()
+	}
+
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowOrigins = origins
+	corsConfig.AllowCredentials = true
+	corsConfig.AllowHeaders = append(corsConfig.AllowHeaders, "Authorization", general.CsrfTokenHeader)
+
+	return cors.New(corsConfig)
+}
+
+// splitList splits a comma separated list, leaving out empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
 // RegisterHandler registers our API handler
 func (s *Server) RegisterHandler(api RouterHandler) {
 	api.Register(s.gin)
@@ -3,6 +3,10 @@ package http
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/general"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -22,3 +26,34 @@ func TestHttpServer_ok(t *testing.T) {
 	time.Sleep(time.Millisecond * 300)
 	srv.Stop()
 }
+
+func TestHttpServer_trustedProxies(t *testing.T) {
+	testCases := []struct {
+		name           string
+		trustedProxies string
+		expected       string
+	}{
+		{name: "no trusted proxy", expected: "192.0.2.1"},
+		{name: "trusted proxy", trustedProxies: "10.0.0.1, 192.0.2.0/24", expected: "203.0.113.7"},
+		{name: "other proxy", trustedProxies: "10.0.0.0/8", expected: "192.0.2.1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := NewServer(config.HttpConfig{TrustedProxies: tc.trustedProxies})
+
+			var clientIP string
+			srv.gin.GET("/", func(c *gin.Context) {
+				clientIP, _ = general.GetClientIPFromCtx(c.Request.Context())
+			})
+
+			// httptest requests come from 192.0.2.1
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.Header.Set("X-Forwarded-For", "203.0.113.7")
+
+			srv.gin.ServeHTTP(httptest.NewRecorder(), request)
+
+			assert.Equal(t, tc.expected, clientIP)
+		})
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
 	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"log"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -24,51 +27,183 @@ type jwtClaims struct {
 	IdentityID int64
 	Type       string
 	Lifetime   time.Duration
+	Roles      []string `json:"Roles,omitempty"`
+	Scopes     []string `json:"Scopes,omitempty"`
+	ClientID   string   `json:"ClientID,omitempty"`
+	TenantID   int64    `json:"TenantID,omitempty"`
+	Act        *actor   `json:"act,omitempty"`
+}
+
+// actor is the party acting on behalf of the subject, as the act claim of RFC 8693
+type actor struct {
+	Subject string `json:"sub"`
 }
 
 // JwtData is the data used to generate jwt token
 type JwtData struct {
+	TokenID    string        // unique token identifier (jti)
 	SessionID  string        // unique session identifier
 	IdentityID int64         // identity identifier (user ID, etc.)
 	Type       string        // token type based on usage (access token, refresh token, etc.)
 	Lifetime   time.Duration // expected token lifetime
+	IssuedAt   time.Time     // issuance time, filled on extraction
+	ExpiresAt  time.Time     // expiration time, filled on extraction
+	Roles      []string      // optional role names embedded in the token
+	Scopes     []string      // optional scopes granted to an oauth client
+	ClientID   string        // optional oauth client the token was issued to
+	ActorID    int64         // optional user acting on behalf of the identity, when impersonating
+	TenantID   int64         // optional organization the token is scoped to
 }
 
 type JwtInterface interface {
 	// GenerateToken generate jwt token based on given data
 	GenerateToken(ctx context.Context, data JwtData) (token string, err error)
 
-	// ExtractToken the reverse of generate: extract
-	ExtractToken(ctx context.Context, token string) (data JwtData, err error)
+	// ExtractToken the reverse of generate: extract a token of the given type,
+	// tokens of any other type fail verification
+	ExtractToken(ctx context.Context, token string, tokenType string) (data JwtData, err error)
+
+	// JWKS returns the public keys tokens may be verified with
+	JWKS(ctx context.Context) JSONWebKeySet
+
+	// RotateKeys switches to a new signing key, keeping the previous one for verification
+	RotateKeys(ctx context.Context) error
 }
 
 // JwtModule is the jwt module
 type JwtModule struct {
-	secret string
-	time   commonTime.TimeInterface
+	keyring         *Keyring // access tokens, its public keys are published
+	internalKeyring *Keyring // internal token types
+	internalTypes   map[string]bool
+	algorithm       string
+	keyFiles        []string
+	time            commonTime.TimeInterface
+}
+
+// New creates new JwtModule.
+// Internal token types are only ever verified by this service: they are signed with keys derived from
+// the refresh token secret, never with a published key, so they can't be mistaken for access tokens.
+func New(t commonTime.TimeInterface, internalTokenTypes ...string) (*JwtModule, error) {
+	cfg := config.Global.JwtKeys
+	module := &JwtModule{
+		algorithm:     cfg.Algorithm,
+		internalTypes: map[string]bool{},
+		time:          t,
+	}
+
+	for _, path := range strings.Split(cfg.PrivateKeyFiles, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			module.keyFiles = append(module.keyFiles, path)
+		}
+	}
+
+	for _, tokenType := range internalTokenTypes {
+		module.internalTypes[tokenType] = true
+	}
+
+	refreshSecret, err := requireSecret(config.Global.JwtSecretRefreshToken, "JWT_SECRET_KEY_RT")
+	if err != nil {
+		return nil, err
+	}
+	module.internalKeyring = NewKeyring(NewHmacKey([]byte(refreshSecret)), nil, cfg.Retention)
+
+	if module.algorithm == "" || module.algorithm == AlgorithmHS256 {
+		module.algorithm = AlgorithmHS256
+
+		secret, err := requireSecret(config.Global.JwtSecretAccessToken, "JWT_SECRET_KEY_AT")
+		if err != nil {
+			return nil, err
+		}
+
+		module.keyring = NewKeyring(NewHmacKey([]byte(secret)), nil, cfg.Retention)
+		return module, nil
+	}
+
+	if len(module.keyFiles) == 0 && !isLocal() {
+		return nil, fmt.Errorf("%w: JWT_PRIVATE_KEY_FILES is required for %s", ErrKeyMissing, module.algorithm)
+	}
+
+	keys, err := module.loadKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	module.keyring = NewKeyring(keys[0], keys[1:], cfg.Retention)
+
+	return module, nil
 }
 
-// New creates new JwtModule
-func New(t commonTime.TimeInterface) *JwtModule {
-	secret := config.Global.JwtSecretAccessToken
+// requireSecret returns the configured secret, a random one is only acceptable locally
+// since tokens signed with it break across replicas and restarts
+func requireSecret(secret string, name string) (string, error) {
+	if secret != "" {
+		return secret, nil
+	}
 
-	if secret == "" {
-		secret = generateRandomString()
+	if !isLocal() {
+		return "", fmt.Errorf("%w: %s is required", ErrKeyMissing, name)
 	}
 
-	return &JwtModule{
-		secret: secret,
-		time:   t,
+	log.Printf("jwt: %s is empty, generated secret is lost on restart", name)
+
+	return generateRandomString(), nil
+}
+
+// isLocal tells whether the app runs in the local environment
+func isLocal() bool {
+	return config.Global.Env == config.LOC
+}
+
+// keyringFor returns the keyring tokens of the given type are signed with
+func (j *JwtModule) keyringFor(tokenType string) *Keyring {
+	if j.internalTypes[tokenType] {
+		return j.internalKeyring
 	}
+
+	return j.keyring
+}
+
+// loadKeys loads the configured key files, the first one signs, or generates a new key without files
+func (j *JwtModule) loadKeys() (keys []Key, err error) {
+	now := j.time.Now()
+
+	if len(j.keyFiles) == 0 {
+		log.Printf("jwt: no private key files configured, generated %s key is lost on restart", j.algorithm)
+
+		key, err := GenerateKey(j.algorithm, now)
+		if err != nil {
+			return nil, err
+		}
+
+		return []Key{key}, nil
+	}
+
+	for _, path := range j.keyFiles {
+		key, err := LoadKeyFile(path, now)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	if keys[0].Method.Alg() != j.algorithm {
+		return nil, fmt.Errorf("%w: %s is a %s key, expected %s", ErrKeyInvalid, j.keyFiles[0], keys[0].Method.Alg(), j.algorithm)
+	}
+
+	return keys, nil
 }
 
 // GenerateToken generate jwt token based on given data
 func (j *JwtModule) GenerateToken(ctx context.Context, data JwtData) (token string, err error) {
-	claims := j.newClaims(data.SessionID, data.IdentityID, data.Type, data.Lifetime)
+	claims := j.newClaims(data)
+
+	key := j.keyringFor(data.Type).SigningKey().forType(data.Type)
 
-	tokenUnsigned := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenUnsigned := jwt.NewWithClaims(key.Method, claims)
+	tokenUnsigned.Header["kid"] = key.ID
 
-	tokenString, err := tokenUnsigned.SignedString([]byte(j.secret))
+	tokenString, err := tokenUnsigned.SignedString(key.Private)
 
 	if err != nil {
 		return "", err
@@ -77,15 +212,27 @@ func (j *JwtModule) GenerateToken(ctx context.Context, data JwtData) (token stri
 	return tokenString, nil
 }
 
-// ExtractToken the reverse of generate: extract
-func (j *JwtModule) ExtractToken(ctx context.Context, token string) (data JwtData, err error) {
+// ExtractToken the reverse of generate: extract a token of the given type
+func (j *JwtModule) ExtractToken(ctx context.Context, token string, tokenType string) (data JwtData, err error) {
+	keyring := j.keyringFor(tokenType)
+
 	// parse tokenString into token
 	parsedToken, err := jwt.ParseWithClaims(token, &jwtClaims{}, func(token *jwt.Token) (interface{}, error) {
+		// tokens issued before key ids were introduced are verified with the signing key
+		key := keyring.SigningKey()
+
+		if kid, ok := token.Header["kid"].(string); ok {
+			if key, ok = keyring.VerificationKey(kid); !ok {
+				return nil, fmt.Errorf("unknown key id: %s", kid)
+			}
+		}
+		key = key.forType(tokenType)
+
 		// Don't forget to validate the alg is what you expect:
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		if token.Method.Alg() != key.Method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(j.secret), nil
+		return key.Public, nil
 	})
 
 	// parsing token returns error
@@ -114,13 +261,63 @@ func (j *JwtModule) ExtractToken(ctx context.Context, token string) (data JwtDat
 		return
 	}
 
+	// each token type is its own audience
+	if !claims.VerifyAudience(tokenType, true) {
+		err = fmt.Errorf("%w: audience is not %s", ErrTokenInvalid, tokenType)
+		return
+	}
+
+	data.TokenID = claims.ID
 	data.IdentityID = claims.IdentityID
 	data.SessionID = claims.SessionID
 	data.Type = claims.Type
+	data.Roles = claims.Roles
+	data.Scopes = claims.Scopes
+	data.ClientID = claims.ClientID
+	data.TenantID = claims.TenantID
+
+	if claims.Act != nil {
+		if data.ActorID, err = strconv.ParseInt(claims.Act.Subject, 10, 64); err != nil || data.ActorID == 0 {
+			return JwtData{}, fmt.Errorf("%w: invalid act claim", ErrTokenInvalid)
+		}
+	}
+
+	if claims.IssuedAt != nil {
+		data.IssuedAt = claims.IssuedAt.Time
+	}
+
+	if claims.ExpiresAt != nil {
+		data.ExpiresAt = claims.ExpiresAt.Time
+	}
 
 	return data, nil
 }
 
+// JWKS returns the public keys tokens may be verified with
+func (j *JwtModule) JWKS(ctx context.Context) JSONWebKeySet {
+	return j.keyring.JWKS()
+}
+
+// RotateKeys switches to a new signing key, keeping the previous one for verification.
+// With key files configured they are reloaded, so rotation is done by replacing the files.
+func (j *JwtModule) RotateKeys(ctx context.Context) (err error) {
+	if j.algorithm == AlgorithmHS256 {
+		return fmt.Errorf("%w: %s", ErrRotationUnsupported, j.algorithm)
+	}
+
+	keys, err := j.loadKeys()
+	if err != nil {
+		return
+	}
+
+	for _, key := range keys[1:] {
+		j.keyring.Add(key)
+	}
+	j.keyring.Rotate(keys[0], j.time.Now())
+
+	return nil
+}
+
 // generateRandomString returns securely generated random string.
 // It will return an error if the system's secure random
 // number generator fails to function correctly, in which
@@ -137,17 +334,29 @@ func generateRandomString() string {
 	return base64.URLEncoding.EncodeToString(b)
 }
 
-// newClaims creates new claims with given sessionID, identityID, type and lifetime.
-func (j *JwtModule) newClaims(sessionID string, identityID int64, typ string, lifeTime time.Duration) *jwtClaims {
+// newClaims creates new claims with given token ID, sessionID, identityID, type and lifetime.
+func (j *JwtModule) newClaims(data JwtData) *jwtClaims {
 	now := j.time.Now()
 
-	return &jwtClaims{
+	claims := &jwtClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        data.TokenID,
+			Audience:  jwt.ClaimStrings{data.Type},
 			IssuedAt:  jwt.NewNumericDate(time.Unix(now.Unix(), 0)),
-			ExpiresAt: jwt.NewNumericDate(time.Unix(now.Add(lifeTime).Unix(), 0)),
+			ExpiresAt: jwt.NewNumericDate(time.Unix(now.Add(data.Lifetime).Unix(), 0)),
 		},
-		SessionID:  sessionID,
-		IdentityID: identityID,
-		Type:       typ,
+		SessionID:  data.SessionID,
+		IdentityID: data.IdentityID,
+		Type:       data.Type,
+		Roles:      data.Roles,
+		Scopes:     data.Scopes,
+		ClientID:   data.ClientID,
+		TenantID:   data.TenantID,
+	}
+
+	if data.ActorID != 0 {
+		claims.Act = &actor{Subject: strconv.FormatInt(data.ActorID, 10)}
 	}
+
+	return claims
 }
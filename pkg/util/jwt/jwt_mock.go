@@ -35,18 +35,18 @@ func (m *MockJwtInterface) EXPECT() *MockJwtInterfaceMockRecorder {
 }
 
 // ExtractToken mocks base method.
-func (m *MockJwtInterface) ExtractToken(ctx context.Context, token string) (JwtData, error) {
+func (m *MockJwtInterface) ExtractToken(ctx context.Context, token, tokenType string) (JwtData, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExtractToken", ctx, token)
+	ret := m.ctrl.Call(m, "ExtractToken", ctx, token, tokenType)
 	ret0, _ := ret[0].(JwtData)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ExtractToken indicates an expected call of ExtractToken.
-func (mr *MockJwtInterfaceMockRecorder) ExtractToken(ctx, token interface{}) *gomock.Call {
+func (mr *MockJwtInterfaceMockRecorder) ExtractToken(ctx, token, tokenType interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtractToken", reflect.TypeOf((*MockJwtInterface)(nil).ExtractToken), ctx, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtractToken", reflect.TypeOf((*MockJwtInterface)(nil).ExtractToken), ctx, token, tokenType)
 }
 
 // GenerateToken mocks base method.
@@ -63,3 +63,31 @@ func (mr *MockJwtInterfaceMockRecorder) GenerateToken(ctx, data interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockJwtInterface)(nil).GenerateToken), ctx, data)
 }
+
+// JWKS mocks base method.
+func (m *MockJwtInterface) JWKS(ctx context.Context) JSONWebKeySet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS", ctx)
+	ret0, _ := ret[0].(JSONWebKeySet)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockJwtInterfaceMockRecorder) JWKS(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockJwtInterface)(nil).JWKS), ctx)
+}
+
+// RotateKeys mocks base method.
+func (m *MockJwtInterface) RotateKeys(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateKeys", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateKeys indicates an expected call of RotateKeys.
+func (mr *MockJwtInterfaceMockRecorder) RotateKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateKeys", reflect.TypeOf((*MockJwtInterface)(nil).RotateKeys), ctx)
+}
@@ -11,8 +11,12 @@ func TestMock(t *testing.T) {
 	mock := NewMockJwtInterface(ctrl)
 
 	mock.EXPECT().GenerateToken(gomock.Any(), gomock.Any())
-	mock.EXPECT().ExtractToken(gomock.Any(), gomock.Any())
+	mock.EXPECT().ExtractToken(gomock.Any(), gomock.Any(), gomock.Any())
+	mock.EXPECT().JWKS(gomock.Any())
+	mock.EXPECT().RotateKeys(gomock.Any())
 
 	_, _ = mock.GenerateToken(nil, JwtData{})
-	_, _ = mock.ExtractToken(nil, "")
+	_, _ = mock.ExtractToken(nil, "", "")
+	_ = mock.JWKS(nil)
+	_ = mock.RotateKeys(nil)
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/golang/mock/gomock"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
 	"github.com/lactobasilusprotectus/go-template/pkg/common/constant"
 	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
 	"github.com/stretchr/testify/assert"
@@ -13,7 +14,8 @@ import (
 )
 
 const (
-	secret = "super-secret"
+	secret        = "super-secret"
+	refreshSecret = "super-refresh-secret"
 )
 
 var (
@@ -41,8 +43,13 @@ func initModule(mocks map[int]interface{}, mockFunc func()) (module *JwtModule)
 	mockTime := mocks[constant.MockTime].(*commonTime.MockTimeInterface)
 	mockFunc()
 
-	module = New(mockTime)
-	module.secret = secret
+	module = &JwtModule{
+		keyring:         NewKeyring(NewHmacKey([]byte(secret)), nil, 0),
+		internalKeyring: NewKeyring(NewHmacKey([]byte(refreshSecret)), nil, 0),
+		internalTypes:   map[string]bool{"refresh": true},
+		algorithm:       AlgorithmHS256,
+		time:            mockTime,
+	}
 
 	return
 }
@@ -104,6 +111,7 @@ func TestExtractToken(t *testing.T) {
 			generateToken: func() string {
 				token := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwtClaims{
 					RegisteredClaims: jwt.RegisteredClaims{
+						Audience:  jwt.ClaimStrings{"type"},
 						IssuedAt:  jwt.NewNumericDate(time.Unix(fiveMinsAgo.Unix(), 0)),
 						ExpiresAt: jwt.NewNumericDate(time.Unix(fiveMinsAgo.Unix(), 0)),
 					},
@@ -111,17 +119,38 @@ func TestExtractToken(t *testing.T) {
 					IdentityID: 10,
 					Type:       "type",
 				})
-				tokenString, _ := token.SignedString([]byte(secret))
+				tokenString, _ := token.SignedString(NewHmacKey([]byte(secret)).forType("type").Private)
 
 				return tokenString
 			},
 			err: ErrTokenExpired,
 		},
+		{
+			name: "invalid_actor",
+			generateToken: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwtClaims{
+					RegisteredClaims: jwt.RegisteredClaims{
+						Audience:  jwt.ClaimStrings{"type"},
+						IssuedAt:  jwt.NewNumericDate(time.Unix(fiveMinsAgo.Unix(), 0)),
+						ExpiresAt: jwt.NewNumericDate(time.Unix(fiveMinsLater.Unix(), 0)),
+					},
+					SessionID:  "session",
+					IdentityID: 10,
+					Type:       "type",
+					Act:        &actor{Subject: "admin"},
+				})
+				tokenString, _ := token.SignedString(NewHmacKey([]byte(secret)).forType("type").Private)
+
+				return tokenString
+			},
+			err: ErrTokenInvalid,
+		},
 		{
 			name: "token_not_issued_yet",
 			generateToken: func() string {
 				token := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwtClaims{
 					RegisteredClaims: jwt.RegisteredClaims{
+						Audience:  jwt.ClaimStrings{"type"},
 						IssuedAt:  jwt.NewNumericDate(time.Unix(fiveMinsLater.Unix(), 0)),
 						ExpiresAt: jwt.NewNumericDate(time.Unix(fiveMinsLater.Unix(), 0)),
 					},
@@ -129,7 +158,7 @@ func TestExtractToken(t *testing.T) {
 					IdentityID: 10,
 					Type:       "type",
 				})
-				tokenString, _ := token.SignedString([]byte(secret))
+				tokenString, _ := token.SignedString(NewHmacKey([]byte(secret)).forType("type").Private)
 
 				return tokenString
 			},
@@ -140,21 +169,36 @@ func TestExtractToken(t *testing.T) {
 			generateToken: func() string {
 				token := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwtClaims{
 					RegisteredClaims: jwt.RegisteredClaims{
+						ID:        "token-id",
+						Audience:  jwt.ClaimStrings{"type"},
 						IssuedAt:  jwt.NewNumericDate(time.Unix(fiveMinsAgo.Unix(), 0)),
 						ExpiresAt: jwt.NewNumericDate(time.Unix(fiveMinsLater.Unix(), 0)),
 					},
 					SessionID:  "session",
 					IdentityID: 10,
 					Type:       "type",
+					Roles:      []string{"admin"},
+					Scopes:     []string{"read"},
+					ClientID:   "client",
+					Act:        &actor{Subject: "3"},
+					TenantID:   7,
 				})
-				tokenString, _ := token.SignedString([]byte(secret))
+				tokenString, _ := token.SignedString(NewHmacKey([]byte(secret)).forType("type").Private)
 
 				return tokenString
 			},
 			data: JwtData{
+				TokenID:    "token-id",
 				SessionID:  "session",
 				IdentityID: 10,
 				Type:       "type",
+				IssuedAt:   time.Unix(fiveMinsAgo.Unix(), 0),
+				ExpiresAt:  time.Unix(fiveMinsLater.Unix(), 0),
+				Roles:      []string{"admin"},
+				Scopes:     []string{"read"},
+				ClientID:   "client",
+				ActorID:    3,
+				TenantID:   7,
 			},
 			err: nil,
 		},
@@ -168,7 +212,7 @@ func TestExtractToken(t *testing.T) {
 			defer deferFunc()
 
 			// call the function
-			data, err := module.ExtractToken(context.Background(), tc.generateToken())
+			data, err := module.ExtractToken(context.Background(), tc.generateToken(), "type")
 
 			// assert returned values
 			assert.True(t, errors.Is(err, tc.err))
@@ -176,3 +220,115 @@ func TestExtractToken(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractToken_tokenTypes(t *testing.T) {
+	testCases := []struct {
+		name          string
+		generatedType string
+		extractedType string
+		err           error
+	}{
+		{
+			name:          "access token",
+			generatedType: "type",
+			extractedType: "type",
+		},
+		{
+			name:          "internal token",
+			generatedType: "refresh",
+			extractedType: "refresh",
+		},
+		{
+			name:          "internal token extracted as access token",
+			generatedType: "refresh",
+			extractedType: "type",
+			err:           ErrTokenInvalid,
+		},
+		{
+			name:          "access token extracted as internal token",
+			generatedType: "type",
+			extractedType: "refresh",
+			err:           ErrTokenInvalid,
+		},
+		{
+			name:          "access token extracted as another access token type",
+			generatedType: "type",
+			extractedType: "other",
+			err:           ErrTokenInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mocks, deferFunc := initMocks(t)
+			module := initModule(mocks, func() {
+				mocks[constant.MockTime].(*commonTime.MockTimeInterface).EXPECT().Now().Return(now)
+			})
+			defer deferFunc()
+
+			token, err := module.GenerateToken(context.Background(), JwtData{Type: tc.generatedType, Lifetime: time.Minute})
+			assert.NoError(t, err)
+
+			_, err = module.ExtractToken(context.Background(), token, tc.extractedType)
+
+			assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name   string
+		global config.GlobalConfig
+		err    error
+	}{
+		{
+			name:   "local generates missing secrets",
+			global: config.GlobalConfig{Env: config.LOC},
+		},
+		{
+			name: "configured secrets",
+			global: config.GlobalConfig{
+				Env:                   "production",
+				JwtSecretAccessToken:  secret,
+				JwtSecretRefreshToken: refreshSecret,
+			},
+		},
+		{
+			name:   "missing access token secret",
+			global: config.GlobalConfig{Env: "production", JwtSecretRefreshToken: refreshSecret},
+			err:    ErrKeyMissing,
+		},
+		{
+			name:   "missing refresh token secret",
+			global: config.GlobalConfig{Env: "production", JwtSecretAccessToken: secret},
+			err:    ErrKeyMissing,
+		},
+		{
+			name: "missing key files",
+			global: config.GlobalConfig{
+				Env:                   "production",
+				JwtSecretRefreshToken: refreshSecret,
+				JwtKeys:               config.JwtKeyConfig{Algorithm: AlgorithmES256},
+			},
+			err: ErrKeyMissing,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config.Global = tc.global
+			defer config.ResetGlobalConfig()
+
+			module, err := New(commonTime.New(), "refresh")
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, module.internalTypes["refresh"])
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var (
+	ErrAlgorithmUnsupported = errors.New("signing algorithm unsupported")
+	ErrKeyInvalid           = errors.New("signing key invalid")
+	ErrKeyMissing           = errors.New("signing key missing")
+	ErrRotationUnsupported  = errors.New("key rotation unsupported")
+)
+
+// Key is a key able to sign and verify tokens, identified by its kid
+type Key struct {
+	ID        string
+	Method    jwt.SigningMethod
+	Private   interface{} // []byte for HMAC, crypto.Signer otherwise
+	Public    interface{} // []byte for HMAC, crypto.PublicKey otherwise
+	CreatedAt time.Time
+}
+
+// JSONWebKey is the public part of an asymmetric key, as defined by RFC 7517
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet is the set of keys published for downstream verification
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// Keyring holds the current signing key and every key tokens may still be verified with
+type Keyring struct {
+	mu        sync.RWMutex
+	signing   Key
+	keys      map[string]Key
+	retiredAt map[string]time.Time
+	retention time.Duration
+}
+
+// NewKeyring creates a keyring signing with the given key, and verifying with it and any additional key
+func NewKeyring(signing Key, verification []Key, retention time.Duration) *Keyring {
+	k := &Keyring{
+		signing:   signing,
+		keys:      map[string]Key{signing.ID: signing},
+		retiredAt: map[string]time.Time{},
+		retention: retention,
+	}
+
+	for _, key := range verification {
+		k.keys[key.ID] = key
+	}
+
+	return k
+}
+
+// SigningKey returns the key new tokens are signed with
+func (k *Keyring) SigningKey() Key {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.signing
+}
+
+// VerificationKey returns the key with the given kid
+func (k *Keyring) VerificationKey(kid string) (key Key, ok bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok = k.keys[kid]
+	return
+}
+
+// Rotate makes next the signing key, the previous one keeps verifying tokens until the retention passes
+func (k *Keyring) Rotate(next Key, now time.Time) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if next.ID != k.signing.ID {
+		k.retiredAt[k.signing.ID] = now
+	}
+
+	k.signing = next
+	k.keys[next.ID] = next
+	delete(k.retiredAt, next.ID)
+
+	// drop keys whose tokens have outlived the retention
+	for kid, retiredAt := range k.retiredAt {
+		if now.Sub(retiredAt) >= k.retention {
+			delete(k.keys, kid)
+			delete(k.retiredAt, kid)
+		}
+	}
+}
+
+// Add adds a key tokens may be verified with, without signing with it
+func (k *Keyring) Add(key Key) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.keys[key.ID]; !ok {
+		k.keys[key.ID] = key
+	}
+}
+
+// JWKS returns the public part of every asymmetric key, HMAC secrets are never published
+func (k *Keyring) JWKS() (set JSONWebKeySet) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	set.Keys = []JSONWebKey{}
+	for _, key := range k.keys {
+		jwk, ok := toJSONWebKey(key)
+		if ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+
+	return set
+}
+
+// NewHmacKey creates a key from a shared secret
+func NewHmacKey(secret []byte) Key {
+	sum := sha256.Sum256(secret)
+
+	return Key{
+		ID:      "hs-" + hex.EncodeToString(sum[:8]),
+		Method:  jwt.SigningMethodHS256,
+		Private: secret,
+		Public:  secret,
+	}
+}
+
+// forType returns the key used for the given token type: a shared secret is never used as is,
+// each token type signs with its own secret derived from it
+func (key Key) forType(tokenType string) Key {
+	secret, ok := key.Private.([]byte)
+	if !ok {
+		return key
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(tokenType))
+	key.Private = mac.Sum(nil)
+	key.Public = key.Private
+
+	return key
+}
+
+// GenerateKey generates a new in-memory key for the given asymmetric algorithm
+func GenerateKey(algorithm string, now time.Time) (key Key, err error) {
+	var signer crypto.Signer
+
+	switch algorithm {
+	case AlgorithmRS256:
+		signer, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgorithmES256:
+		signer, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgorithmEdDSA:
+		_, signer, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = fmt.Errorf("%w: %s", ErrAlgorithmUnsupported, algorithm)
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("generate key err: %+v", err)
+		return
+	}
+
+	return newAsymmetricKey(signer, now)
+}
+
+// LoadKeyFile loads a PEM encoded PKCS#8, PKCS#1 or SEC 1 private key, its algorithm follows the key type
+func LoadKeyFile(path string, now time.Time) (key Key, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		err = fmt.Errorf("read key file err: %+v", err)
+		return
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		err = fmt.Errorf("%w: %s contains no PEM block", ErrKeyInvalid, path)
+		return
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("%w: %s: %+v", ErrKeyInvalid, path, err)
+		return
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		err = fmt.Errorf("%w: %s is not a signing key", ErrKeyInvalid, path)
+		return
+	}
+
+	return newAsymmetricKey(signer, now)
+}
+
+// newAsymmetricKey creates a key from a private key, identified by its RFC 7638 thumbprint
+// so every replica loading the same file agrees on the kid
+func newAsymmetricKey(signer crypto.Signer, now time.Time) (key Key, err error) {
+	key = Key{
+		Private:   signer,
+		Public:    signer.Public(),
+		CreatedAt: now,
+	}
+
+	switch pub := signer.Public().(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			err = fmt.Errorf("%w: only P-256 curve is supported", ErrKeyInvalid)
+			return
+		}
+		key.Method = jwt.SigningMethodES256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		err = fmt.Errorf("%w: unsupported key type %T", ErrKeyInvalid, pub)
+		return
+	}
+
+	jwk, _ := toJSONWebKey(key)
+	key.ID = thumbprint(jwk)
+
+	return key, nil
+}
+
+// toJSONWebKey returns the public JWK of an asymmetric key
+func toJSONWebKey(key Key) (jwk JSONWebKey, ok bool) {
+	jwk = JSONWebKey{Use: "sig", Alg: key.Method.Alg(), Kid: key.ID}
+
+	switch pub := key.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	default:
+		return JSONWebKey{}, false
+	}
+
+	return jwk, true
+}
+
+// thumbprint computes the RFC 7638 thumbprint of a JWK
+func thumbprint(jwk JSONWebKey) string {
+	// required members only, in lexicographic order
+	var members interface{}
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	content, _ := json.Marshal(members)
+	sum := sha256.Sum256(content)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
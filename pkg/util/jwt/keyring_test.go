@@ -0,0 +1,264 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"github.com/golang-jwt/jwt/v4"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newKeyringModule(t *testing.T, algorithm string) *JwtModule {
+	key, err := GenerateKey(algorithm, now)
+	require.NoError(t, err)
+
+	return &JwtModule{
+		keyring:         NewKeyring(key, nil, time.Hour),
+		internalKeyring: NewKeyring(NewHmacKey([]byte(refreshSecret)), nil, time.Hour),
+		internalTypes:   map[string]bool{"refresh": true},
+		algorithm:       algorithm,
+		time:            commonTime.New(),
+	}
+}
+
+func TestKeyring_signAndVerify(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmRS256, AlgorithmES256, AlgorithmEdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			module := newKeyringModule(t, algorithm)
+
+			token, err := module.GenerateToken(context.Background(), JwtData{
+				TokenID:    "token-id",
+				IdentityID: 10,
+				Type:       "access",
+				Lifetime:   time.Minute,
+				ActorID:    3,
+				TenantID:   7,
+			})
+			require.NoError(t, err)
+
+			parsed, _, err := new(jwt.Parser).ParseUnverified(token, &jwtClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, algorithm, parsed.Method.Alg())
+			assert.Equal(t, module.keyring.SigningKey().ID, parsed.Header["kid"])
+
+			data, err := module.ExtractToken(context.Background(), token, "access")
+			require.NoError(t, err)
+			assert.Equal(t, "token-id", data.TokenID)
+			assert.Equal(t, int64(10), data.IdentityID)
+			assert.Equal(t, int64(3), data.ActorID)
+			assert.Equal(t, int64(7), data.TenantID)
+		})
+	}
+}
+
+func TestKeyring_rejectsForeignTokens(t *testing.T) {
+	module := newKeyringModule(t, AlgorithmRS256)
+	other := newKeyringModule(t, AlgorithmRS256)
+
+	testCases := []struct {
+		name          string
+		generateToken func() string
+	}{
+		{
+			name: "unknown kid",
+			generateToken: func() string {
+				token, _ := other.GenerateToken(context.Background(), JwtData{Type: "access", Lifetime: time.Minute})
+				return token
+			},
+		},
+		{
+			name: "known kid signed by another key",
+			generateToken: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodRS256, other.newClaims(JwtData{Type: "access", Lifetime: time.Minute}))
+				token.Header["kid"] = module.keyring.SigningKey().ID
+				tokenString, _ := token.SignedString(other.keyring.SigningKey().Private)
+				return tokenString
+			},
+		},
+		{
+			name: "algorithm confusion with the public key as HMAC secret",
+			generateToken: func() string {
+				pub := module.keyring.SigningKey().Public.(*rsa.PublicKey)
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, module.newClaims(JwtData{Type: "access", Lifetime: time.Minute}))
+				token.Header["kid"] = module.keyring.SigningKey().ID
+				tokenString, _ := token.SignedString(x509.MarshalPKCS1PublicKey(pub))
+				return tokenString
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := module.ExtractToken(context.Background(), tc.generateToken(), "access")
+
+			assert.True(t, errors.Is(err, ErrTokenInvalid))
+		})
+	}
+}
+
+func TestKeyring_Rotate(t *testing.T) {
+	module := newKeyringModule(t, AlgorithmES256)
+	previous := module.keyring.SigningKey()
+
+	token, err := module.GenerateToken(context.Background(), JwtData{Type: "access", Lifetime: time.Minute})
+	require.NoError(t, err)
+
+	require.NoError(t, module.RotateKeys(context.Background()))
+	assert.NotEqual(t, previous.ID, module.keyring.SigningKey().ID)
+
+	// tokens signed before the rotation stay valid
+	_, err = module.ExtractToken(context.Background(), token, "access")
+	assert.NoError(t, err)
+	assert.Len(t, module.JWKS(context.Background()).Keys, 2)
+
+	// and are dropped once the retention passes
+	next, err := GenerateKey(AlgorithmES256, now)
+	require.NoError(t, err)
+	module.keyring.Rotate(next, time.Now().Add(2*time.Hour))
+
+	_, err = module.ExtractToken(context.Background(), token, "access")
+	assert.True(t, errors.Is(err, ErrTokenInvalid))
+	assert.Len(t, module.JWKS(context.Background()).Keys, 2)
+}
+
+func TestKeyring_RotateHmac(t *testing.T) {
+	module := &JwtModule{keyring: NewKeyring(NewHmacKey([]byte(secret)), nil, 0), algorithm: AlgorithmHS256}
+
+	err := module.RotateKeys(context.Background())
+
+	assert.True(t, errors.Is(err, ErrRotationUnsupported))
+	assert.Empty(t, module.JWKS(context.Background()).Keys)
+}
+
+func TestKeyring_JWKS(t *testing.T) {
+	rsaKey, err := GenerateKey(AlgorithmRS256, now)
+	require.NoError(t, err)
+	ecKey, err := GenerateKey(AlgorithmES256, now)
+	require.NoError(t, err)
+	edKey, err := GenerateKey(AlgorithmEdDSA, now)
+	require.NoError(t, err)
+
+	keyring := NewKeyring(rsaKey, []Key{ecKey, edKey, NewHmacKey([]byte(secret))}, time.Hour)
+
+	keys := map[string]JSONWebKey{}
+	for _, jwk := range keyring.JWKS().Keys {
+		keys[jwk.Kid] = jwk
+		assert.Equal(t, "sig", jwk.Use)
+	}
+
+	require.Len(t, keys, 3)
+	assert.Equal(t, "RSA", keys[rsaKey.ID].Kty)
+	assert.Equal(t, "RS256", keys[rsaKey.ID].Alg)
+	assert.NotEmpty(t, keys[rsaKey.ID].N)
+	assert.Equal(t, "AQAB", keys[rsaKey.ID].E)
+	assert.Equal(t, "P-256", keys[ecKey.ID].Crv)
+	assert.Len(t, keys[ecKey.ID].X, 43)
+	assert.Len(t, keys[ecKey.ID].Y, 43)
+	assert.Equal(t, "OKP", keys[edKey.ID].Kty)
+	assert.Equal(t, "EdDSA", keys[edKey.ID].Alg)
+}
+
+func TestThumbprint(t *testing.T) {
+	// RFC 7638 section 3.1
+	jwk := JSONWebKey{
+		Kty: "RSA",
+		N: "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMs" +
+			"tn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91" +
+			"CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E: "AQAB",
+	}
+
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", thumbprint(jwk))
+}
+
+func TestLoadKeyFile(t *testing.T) {
+	dir := t.TempDir()
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	sec1, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	require.NoError(t, err)
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	p384, err := x509.MarshalECPrivateKey(p384Key)
+	require.NoError(t, err)
+
+	writePem := func(name, blockType string, content []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: content}), 0600))
+		return path
+	}
+
+	testCases := []struct {
+		name   string
+		path   string
+		method jwt.SigningMethod
+		err    error
+	}{
+		{
+			name:   "SEC 1 EC key",
+			path:   writePem("ec.pem", "EC PRIVATE KEY", sec1),
+			method: jwt.SigningMethodES256,
+		},
+		{
+			name:   "PKCS#1 RSA key",
+			path:   writePem("rsa1.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)),
+			method: jwt.SigningMethodRS256,
+		},
+		{
+			name:   "PKCS#8 RSA key",
+			path:   writePem("rsa8.pem", "PRIVATE KEY", pkcs8),
+			method: jwt.SigningMethodRS256,
+		},
+		{
+			name: "unsupported curve",
+			path: writePem("p384.pem", "EC PRIVATE KEY", p384),
+			err:  ErrKeyInvalid,
+		},
+		{
+			name: "not PEM",
+			path: func() string {
+				path := filepath.Join(dir, "garbage.pem")
+				require.NoError(t, os.WriteFile(path, []byte("garbage"), 0600))
+				return path
+			}(),
+			err: ErrKeyInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := LoadKeyFile(tc.path, now)
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.method, key.Method)
+
+			// the same file always yields the same kid
+			again, err := LoadKeyFile(tc.path, now)
+			require.NoError(t, err)
+			assert.Equal(t, key.ID, again.ID)
+		})
+	}
+}
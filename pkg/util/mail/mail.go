@@ -0,0 +1,70 @@
+package mail
+
+import (
+	"fmt"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"log"
+	"net/smtp"
+	"strings"
+)
+
+type Interface interface {
+	// Send sends a plain text email to the given address
+	Send(to, subject, body string) (err error)
+}
+
+// Mailer sends email through SMTP
+type Mailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+// NewMailer constructs new Mailer
+func NewMailer(config config.MailConfig) *Mailer {
+	return &Mailer{
+		host:     config.Host,
+		port:     config.Port,
+		username: config.Username,
+		password: config.Password,
+		from:     config.From,
+	}
+}
+
+// Send sends a plain text email to the given address.
+// When no SMTP host is configured, the email is only logged, which is handy for local development.
+func (m *Mailer) Send(to, subject, body string) (err error) {
+	if m.host == "" {
+		log.Printf("[Mail] SMTP host is not configured, skip sending email: to=%s, subject=%s", to, subject)
+		return nil
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	err = smtp.SendMail(fmt.Sprintf("%s:%s", m.host, m.port), auth, m.from, []string{to}, buildMessage(m.from, to, subject, body))
+	if err != nil {
+		return fmt.Errorf("send mail err: %w", err)
+	}
+
+	return nil
+}
+
+// buildMessage builds RFC 822 message
+func buildMessage(from, to, subject, body string) []byte {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("From: %s\r\n", from))
+	sb.WriteString(fmt.Sprintf("To: %s\r\n", to))
+	sb.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(body)
+
+	return []byte(sb.String())
+}
@@ -0,0 +1,420 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// clockSkew is the leeway given to time based claims of ID tokens
+	clockSkew = time.Minute
+
+	httpTimeout = 10 * time.Second
+)
+
+var (
+	ErrDiscoveryFailed = errors.New("oidc discovery failed")
+	ErrExchangeFailed  = errors.New("oidc code exchange failed")
+	ErrInvalidIDToken  = errors.New("oidc id token invalid")
+)
+
+type Interface interface {
+	// Name returns the name the provider is configured with
+	Name() string
+
+	// AuthCodeURL returns the url of the provider to send the user to, including the S256 PKCE challenge
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (authURL string, err error)
+
+	// Exchange exchanges the authorization code and returns the identity carried by the verified ID token
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (identity Identity, err error)
+}
+
+// Identity is the user as asserted by the provider
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// CodeChallengeS256 derives the PKCE challenge of the verifier, see RFC 7636 section 4.2
+func CodeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// metadata is the subset of the provider discovery document we rely on
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	AuthorizedParty string       `json:"azp"`
+	Nonce           string       `json:"nonce"`
+	Email           string       `json:"email"`
+	EmailVerified   flexibleBool `json:"email_verified"`
+	Name            string       `json:"name"`
+}
+
+// flexibleBool accepts both true and "true", some providers send booleans as strings
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	*b = flexibleBool(strings.Trim(string(data), `"`) == "true")
+	return nil
+}
+
+// Provider is an OpenID Connect provider, its discovery document and signing keys are fetched lazily
+type Provider struct {
+	name         string
+	discoveryURL string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       []string
+	httpClient   *http.Client
+	time         commonTime.TimeInterface
+
+	mu       sync.Mutex
+	metadata *metadata
+	keys     map[string]crypto.PublicKey
+}
+
+// NewProvider constructs new Provider
+func NewProvider(config config.OidcProviderConfig, t commonTime.TimeInterface) *Provider {
+	return &Provider{
+		name:         config.Name,
+		discoveryURL: config.DiscoveryURL,
+		clientID:     config.ClientID,
+		clientSecret: config.ClientSecret,
+		redirectURL:  config.RedirectURL,
+		scopes:       config.Scopes,
+		httpClient:   &http.Client{Timeout: httpTimeout},
+		time:         t,
+	}
+}
+
+func (p *Provider) Name() string {
+	return p.name
+}
+
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (authURL string, err error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.clientID)
+	params.Set("redirect_uri", p.redirectURL)
+	params.Set("scope", strings.Join(p.scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(md.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return md.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (identity Identity, err error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectURL)
+	form.Set("client_id", p.clientID)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return identity, fmt.Errorf("%w: %+v", ErrExchangeFailed, err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+
+	var tokenResponse struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+
+	status, err := p.doJSON(req, &tokenResponse)
+	if err != nil {
+		return identity, fmt.Errorf("%w: %+v", ErrExchangeFailed, err)
+	}
+
+	if status != http.StatusOK {
+		return identity, fmt.Errorf("%w: status %d, %s %s", ErrExchangeFailed, status, tokenResponse.Error,
+			tokenResponse.ErrorDescription)
+	}
+
+	if tokenResponse.IDToken == "" {
+		return identity, fmt.Errorf("%w: no id_token in response", ErrExchangeFailed)
+	}
+
+	return p.verifyIDToken(ctx, md, tokenResponse.IDToken, nonce)
+}
+
+// verifyIDToken verifies signature and claims of the ID token as specified by OpenID Connect Core section 3.1.3.7
+func (p *Provider) verifyIDToken(ctx context.Context, md *metadata, rawIDToken, nonce string) (identity Identity,
+	err error) {
+	parser := &jwt.Parser{
+		ValidMethods:         []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()},
+		SkipClaimsValidation: true, // validated below against our own clock
+	}
+
+	var claims idTokenClaims
+	_, err = parser.ParseWithClaims(rawIDToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(ctx, md, kid)
+	})
+	if err != nil {
+		return identity, fmt.Errorf("%w: %+v", ErrInvalidIDToken, err)
+	}
+
+	now := p.time.Now()
+
+	switch {
+	case claims.Issuer != md.Issuer:
+		err = fmt.Errorf("%w: unexpected issuer %s", ErrInvalidIDToken, claims.Issuer)
+	case !claims.VerifyAudience(p.clientID, true):
+		err = fmt.Errorf("%w: audience mismatch", ErrInvalidIDToken)
+	case len(claims.Audience) > 1 && claims.AuthorizedParty != p.clientID:
+		err = fmt.Errorf("%w: authorized party mismatch", ErrInvalidIDToken)
+	case claims.ExpiresAt == nil || !now.Before(claims.ExpiresAt.Add(clockSkew)):
+		err = fmt.Errorf("%w: expired", ErrInvalidIDToken)
+	case claims.IssuedAt != nil && claims.IssuedAt.After(now.Add(clockSkew)):
+		err = fmt.Errorf("%w: issued in the future", ErrInvalidIDToken)
+	case claims.Nonce != nonce:
+		err = fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	case claims.Subject == "":
+		err = fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	if err != nil {
+		return
+	}
+
+	return Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// discover fetches and caches the discovery document
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.discoveryURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %+v", ErrDiscoveryFailed, err)
+	}
+
+	var md metadata
+	status, err := p.doJSON(req, &md)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %+v", ErrDiscoveryFailed, err)
+	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrDiscoveryFailed, status)
+	}
+
+	if md.Issuer == "" || md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JwksURI == "" {
+		return nil, fmt.Errorf("%w: incomplete discovery document", ErrDiscoveryFailed)
+	}
+
+	p.metadata = &md
+	return p.metadata, nil
+}
+
+// publicKey returns the signing key of the given kid, keys are refetched once when kid is unknown since the
+// provider may have rotated them
+func (p *Provider) publicKey(ctx context.Context, md *metadata, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	keys, err := p.fetchKeys(ctx, md.JwksURI)
+	if err != nil {
+		return nil, err
+	}
+
+	p.keys = keys
+
+	key, ok := p.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	return key, nil
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (p *Provider) fetchKeys(ctx context.Context, jwksURI string) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	status, err := p.doJSON(req, &jwks)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks err: %+v", err)
+	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks err: status %d", status)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		// keys of unsupported types are skipped rather than failing the whole set
+		key, err := parsePublicKey(jwk)
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+// parsePublicKey converts RSA and P-256 keys of RFC 7518 section 6
+func parsePublicKey(jwk jsonWebKey) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("rsa exponent too large")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if jwk.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Crv)
+		}
+
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, errors.New("ec point not on curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", jwk.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
+
+// doJSON sends the request and decodes the JSON body into v whatever the status is
+func (p *Provider) doJSON(req *http.Request, v interface{}) (status int, err error) {
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+
+	if err = json.Unmarshal(body, v); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, err
+	}
+
+	return resp.StatusCode, nil
+}
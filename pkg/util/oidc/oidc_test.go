@@ -0,0 +1,316 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	commonTime "github.com/lactobasilusprotectus/go-template/pkg/common/time"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const (
+	testClientID     = "client-id"
+	testClientSecret = "client-secret"
+	testRedirectURL  = "http://app.test/callback"
+	testCode         = "valid-code"
+	testVerifier     = "code-verifier"
+	testNonce        = "nonce"
+)
+
+// fakeProvider is an in-process OpenID Connect provider
+type fakeProvider struct {
+	server *httptest.Server
+
+	key *rsa.PrivateKey
+	kid string
+
+	// claims of the next issued ID token, signed with signKey under signKid
+	claims  jwt.MapClaims
+	signKey *rsa.PrivateKey
+	signKid string
+
+	jwksRequests int
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	f := &fakeProvider{key: key, kid: "key-1"}
+	f.signKey, f.signKid = key, f.kid
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                 f.server.URL,
+			"authorization_endpoint": f.server.URL + "/authorize",
+			"token_endpoint":         f.server.URL + "/token",
+			"jwks_uri":               f.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		f.jwksRequests++
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"use": "sig",
+				"kid": f.kid,
+				"n":   base64.RawURLEncoding.EncodeToString(f.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(f.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, _ := r.BasicAuth()
+		if clientID != testClientID || clientSecret != testClientSecret {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+			return
+		}
+
+		if r.PostFormValue("code") != testCode || r.PostFormValue("code_verifier") != testVerifier ||
+			r.PostFormValue("redirect_uri") != testRedirectURL {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, f.claims)
+		token.Header["kid"] = f.signKid
+		idToken, err := token.SignedString(f.signKey)
+		require.NoError(t, err)
+
+		writeJSON(w, http.StatusOK, map[string]string{"access_token": "access", "id_token": idToken})
+	})
+
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+func (f *fakeProvider) validClaims() jwt.MapClaims {
+	now := time.Now()
+
+	return jwt.MapClaims{
+		"iss":            f.server.URL,
+		"sub":            "subject-1",
+		"aud":            testClientID,
+		"exp":            now.Add(time.Hour).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          testNonce,
+		"email":          "user@example.com",
+		"email_verified": true,
+		"name":           "User",
+	}
+}
+
+func (f *fakeProvider) newProvider() *Provider {
+	return NewProvider(config.OidcProviderConfig{
+		Name:         "fake",
+		DiscoveryURL: f.server.URL + "/.well-known/openid-configuration",
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"openid", "email"},
+	}, commonTime.New())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestCodeChallengeS256(t *testing.T) {
+	// RFC 7636 appendix B
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		CodeChallengeS256("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	fake := newFakeProvider(t)
+	provider := fake.newProvider()
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", testNonce, "challenge")
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+
+	assert.Equal(t, fake.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+
+	query := parsed.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, testClientID, query.Get("client_id"))
+	assert.Equal(t, testRedirectURL, query.Get("redirect_uri"))
+	assert.Equal(t, "openid email", query.Get("scope"))
+	assert.Equal(t, "state", query.Get("state"))
+	assert.Equal(t, testNonce, query.Get("nonce"))
+	assert.Equal(t, "challenge", query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+}
+
+func TestProvider_AuthCodeURL_discoveryFailed(t *testing.T) {
+	provider := NewProvider(config.OidcProviderConfig{DiscoveryURL: "http://127.0.0.1:1/missing"}, commonTime.New())
+
+	_, err := provider.AuthCodeURL(context.Background(), "state", testNonce, "challenge")
+
+	assert.True(t, errors.Is(err, ErrDiscoveryFailed))
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		prepare  func(f *fakeProvider)
+		code     string
+		nonce    string
+		identity Identity
+		err      error
+	}{
+		{
+			name:    "positive",
+			prepare: func(f *fakeProvider) {},
+			identity: Identity{
+				Subject:       "subject-1",
+				Email:         "user@example.com",
+				EmailVerified: true,
+				Name:          "User",
+			},
+		},
+		{
+			name: "email verified as string",
+			prepare: func(f *fakeProvider) {
+				f.claims["email_verified"] = "false"
+			},
+			identity: Identity{Subject: "subject-1", Email: "user@example.com", Name: "User"},
+		},
+		{
+			name: "rotated key is refetched",
+			prepare: func(f *fakeProvider) {
+				f.key, f.kid = otherKey, "key-2"
+				f.signKey, f.signKid = otherKey, "key-2"
+			},
+			identity: Identity{
+				Subject:       "subject-1",
+				Email:         "user@example.com",
+				EmailVerified: true,
+				Name:          "User",
+			},
+		},
+		{
+			name:    "code rejected",
+			prepare: func(f *fakeProvider) {},
+			code:    "invalid-code",
+			err:     ErrExchangeFailed,
+		},
+		{
+			name: "wrong signing key",
+			prepare: func(f *fakeProvider) {
+				f.signKey = otherKey
+			},
+			err: ErrInvalidIDToken,
+		},
+		{
+			name: "unknown kid",
+			prepare: func(f *fakeProvider) {
+				f.signKid = "unknown"
+			},
+			err: ErrInvalidIDToken,
+		},
+		{
+			name: "wrong issuer",
+			prepare: func(f *fakeProvider) {
+				f.claims["iss"] = "https://evil.example.com"
+			},
+			err: ErrInvalidIDToken,
+		},
+		{
+			name: "wrong audience",
+			prepare: func(f *fakeProvider) {
+				f.claims["aud"] = "other-client"
+			},
+			err: ErrInvalidIDToken,
+		},
+		{
+			name: "multiple audiences without authorized party",
+			prepare: func(f *fakeProvider) {
+				f.claims["aud"] = []string{testClientID, "other-client"}
+			},
+			err: ErrInvalidIDToken,
+		},
+		{
+			name: "expired",
+			prepare: func(f *fakeProvider) {
+				f.claims["exp"] = time.Now().Add(-time.Hour).Unix()
+			},
+			err: ErrInvalidIDToken,
+		},
+		{
+			name:    "nonce mismatch",
+			prepare: func(f *fakeProvider) {},
+			nonce:   "other-nonce",
+			err:     ErrInvalidIDToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := newFakeProvider(t)
+			provider := fake.newProvider()
+
+			// warm up the key cache, so rotation has something to invalidate
+			md, err := provider.discover(context.Background())
+			require.NoError(t, err)
+			_, err = provider.publicKey(context.Background(), md, fake.kid)
+			require.NoError(t, err)
+
+			fake.claims = fake.validClaims()
+			tc.prepare(fake)
+
+			code, nonce := testCode, testNonce
+			if tc.code != "" {
+				code = tc.code
+			}
+			if tc.nonce != "" {
+				nonce = tc.nonce
+			}
+
+			identity, err := provider.Exchange(context.Background(), code, testVerifier, nonce)
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.identity, identity)
+		})
+	}
+}
+
+func TestProvider_Exchange_keysCached(t *testing.T) {
+	fake := newFakeProvider(t)
+	provider := fake.newProvider()
+	fake.claims = fake.validClaims()
+
+	for i := 0; i < 3; i++ {
+		_, err := provider.Exchange(context.Background(), testCode, testVerifier, testNonce)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, fake.jwksRequests)
+}
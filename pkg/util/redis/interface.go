@@ -3,4 +3,15 @@ package redis
 type Interface interface {
 	Get(key string) (reply interface{}, err error)
 	Set(key string, value interface{}, expireSeconds int) (err error)
+	SetNX(key string, value interface{}, expireSeconds int) (ok bool, err error)
+	SetXX(key string, value interface{}, expireSeconds int) (ok bool, err error)
+	GetSet(key string, value interface{}, expireSeconds int) (reply interface{}, err error)
+	GetDel(key string) (reply interface{}, err error)
+	Incr(key string, expireSeconds int) (value int64, err error)
+	Del(keys ...string) (err error)
+	Expire(key string, expireSeconds int) (err error)
+	TTL(key string) (expireSeconds int, err error)
+	SAdd(key string, members ...interface{}) (err error)
+	SRem(key string, members ...interface{}) (err error)
+	SMembers(key string) (members []string, err error)
 }
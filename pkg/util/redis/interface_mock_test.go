@@ -12,8 +12,30 @@ func TestNewMockInterface(t *testing.T) {
 
 	mock.EXPECT().Get(gomock.Any())
 	mock.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any())
+	mock.EXPECT().SetNX(gomock.Any(), gomock.Any(), gomock.Any())
+	mock.EXPECT().SetXX(gomock.Any(), gomock.Any(), gomock.Any())
+	mock.EXPECT().GetSet(gomock.Any(), gomock.Any(), gomock.Any())
+	mock.EXPECT().GetDel(gomock.Any())
+	mock.EXPECT().Incr(gomock.Any(), gomock.Any())
+	mock.EXPECT().Del(gomock.Any())
+	mock.EXPECT().Expire(gomock.Any(), gomock.Any())
+	mock.EXPECT().TTL(gomock.Any())
+	mock.EXPECT().SAdd(gomock.Any(), gomock.Any())
+	mock.EXPECT().SRem(gomock.Any(), gomock.Any())
+	mock.EXPECT().SMembers(gomock.Any())
 
 	_, _ = mock.Get("")
 	_ = mock.Set("", "", 0)
+	_, _ = mock.SetNX("", "", 0)
+	_, _ = mock.SetXX("", "", 0)
+	_, _ = mock.GetSet("", "", 0)
+	_, _ = mock.GetDel("")
+	_, _ = mock.Incr("", 0)
+	_ = mock.Del("")
+	_ = mock.Expire("", 0)
+	_, _ = mock.TTL("")
+	_ = mock.SAdd("", "")
+	_ = mock.SRem("", "")
+	_, _ = mock.SMembers("")
 
 }
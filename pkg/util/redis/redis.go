@@ -2,14 +2,26 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
 	"github.com/redis/go-redis/v9"
-	"log"
 	"time"
 )
 
 var (
 	ctx = context.Background()
+
+	// ErrNilReturned is returned when the requested key does not exist
+	ErrNilReturned = errors.New("redis: nil returned")
+
+	// increments and sets the expiration of a key without one in a single step, a counter can't be left without
+	// expiration by a failure between both
+	incrScript = redis.NewScript(`
+local value = redis.call("INCR", KEYS[1])
+if tonumber(ARGV[1]) > 0 and redis.call("TTL", KEYS[1]) == -1 then
+	redis.call("EXPIRE", KEYS[1], ARGV[1])
+end
+return value`)
 )
 
 type Client struct {
@@ -34,40 +46,112 @@ func (c *Client) Get(key string) (reply interface{}, err error) {
 	result, err := c.redis.Get(ctx, key).Result()
 
 	if err != nil {
-		return nil, err
+		return nil, wrapErr(err)
 	}
 
-	//close connection
-	defer func(redis *redis.Client) {
-		err = redis.Close()
+	return result, nil
+}
+
+func (c *Client) Set(key string, value interface{}, expireSeconds int) (err error) {
+	return c.redis.Set(ctx, key, value, expiration(expireSeconds)).Err()
+}
+
+// SetNX sets key to value only if key does not exist, ok reports whether the key was set.
+func (c *Client) SetNX(key string, value interface{}, expireSeconds int) (ok bool, err error) {
+	return c.redis.SetNX(ctx, key, value, expiration(expireSeconds)).Result()
+}
+
+// SetXX sets key to value only if key already exists, ok reports whether the key was set.
+func (c *Client) SetXX(key string, value interface{}, expireSeconds int) (ok bool, err error) {
+	return c.redis.SetXX(ctx, key, value, expiration(expireSeconds)).Result()
+}
+
+// GetSet atomically sets key to value and returns the value previously stored at key.
+func (c *Client) GetSet(key string, value interface{}, expireSeconds int) (reply interface{}, err error) {
+	result, err := c.redis.SetArgs(ctx, key, value, redis.SetArgs{
+		TTL: expiration(expireSeconds),
+		Get: true,
+	}).Result()
 
-		if err != nil {
-			log.Fatalf("error closing redis connection: %v", err)
-		}
-	}(c.redis)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
 
 	return result, nil
 }
 
-func (c *Client) Set(key string, value interface{}, expireSeconds int) (err error) {
-	if expireSeconds <= 0 {
-		err = c.redis.Set(ctx, key, value, 0).Err()
-	} else {
-		err = c.redis.Set(ctx, key, value, time.Duration(expireSeconds)*time.Second).Err()
+// GetDel atomically returns the value of key and deletes it
+func (c *Client) GetDel(key string) (reply interface{}, err error) {
+	result, err := c.redis.GetDel(ctx, key).Result()
+
+	if err != nil {
+		return nil, wrapErr(err)
 	}
 
+	return result, nil
+}
+
+// Incr increments the number stored at key by one, a missing key is set to 0 before the operation.
+// The expiration is only applied when the key has none, so a counter keeps its first expiration.
+func (c *Client) Incr(key string, expireSeconds int) (value int64, err error) {
+	return incrScript.Run(ctx, c.redis, []string{key}, expireSeconds).Int64()
+}
+
+// Del removes the given keys
+func (c *Client) Del(keys ...string) (err error) {
+	return c.redis.Del(ctx, keys...).Err()
+}
+
+// Expire sets a timeout on key
+func (c *Client) Expire(key string, expireSeconds int) (err error) {
+	return c.redis.Expire(ctx, key, expiration(expireSeconds)).Err()
+}
+
+// TTL returns the remaining time to live of key in seconds.
+// It returns -2 if the key does not exist and -1 if the key has no expiration.
+func (c *Client) TTL(key string) (expireSeconds int, err error) {
+	result, err := c.redis.TTL(ctx, key).Result()
+
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	//close connection
-	defer func(redis *redis.Client) {
-		err = redis.Close()
+	if result < 0 {
+		return int(result), nil
+	}
+
+	return int(result.Seconds()), nil
+}
+
+// SAdd adds members to the set stored at key
+func (c *Client) SAdd(key string, members ...interface{}) (err error) {
+	return c.redis.SAdd(ctx, key, members...).Err()
+}
 
-		if err != nil {
-			log.Fatalf("error closing redis connection: %v", err)
-		}
-	}(c.redis)
+// SRem removes members from the set stored at key
+func (c *Client) SRem(key string, members ...interface{}) (err error) {
+	return c.redis.SRem(ctx, key, members...).Err()
+}
+
+// SMembers returns all members of the set stored at key
+func (c *Client) SMembers(key string) (members []string, err error) {
+	return c.redis.SMembers(ctx, key).Result()
+}
+
+// expiration converts expireSeconds into duration, zero means no expiration
+func expiration(expireSeconds int) time.Duration {
+	if expireSeconds <= 0 {
+		return 0
+	}
+
+	return time.Duration(expireSeconds) * time.Second
+}
+
+// wrapErr translates redis.Nil into ErrNilReturned
+func wrapErr(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return ErrNilReturned
+	}
 
-	return nil
+	return err
 }
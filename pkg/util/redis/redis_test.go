@@ -3,8 +3,10 @@ package redis
 import (
 	"github.com/alicebob/miniredis/v2"
 	"github.com/lactobasilusprotectus/go-template/pkg/common/config"
+	"github.com/stretchr/testify/assert"
 	"log"
 	"testing"
+	"time"
 )
 
 func TestNewRedisClient(t *testing.T) {
@@ -23,3 +25,124 @@ func TestNewRedisClient(t *testing.T) {
 	})
 
 }
+
+func TestClient_GetSet(t *testing.T) {
+	run, err := miniredis.Run()
+
+	if err != nil {
+		log.Fatalf("miniredis.Run returns err: %+v\n", err)
+	}
+
+	defer run.Close()
+
+	client := NewRedisClient(config.RedisConfig{Host: run.Addr()})
+
+	// missing key returns ErrNilReturned
+	_, err = client.Get("key")
+	assert.ErrorIs(t, err, ErrNilReturned)
+
+	// set then get
+	assert.NoError(t, client.Set("key", "value", 10))
+
+	reply, err := client.Get("key")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", reply)
+
+	// the connection is reusable after previous calls
+	reply, err = client.GetSet("key", "new-value", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", reply)
+	assert.Equal(t, 10*time.Second, run.TTL("key"))
+
+	_, err = client.GetSet("other-key", "value", 0)
+	assert.ErrorIs(t, err, ErrNilReturned)
+
+	// key is only set when missing
+	ok, err := client.SetNX("nx-key", "value", 10)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = client.SetNX("nx-key", "value", 10)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// key is only set when present
+	ok, err = client.SetXX("xx-key", "value", 10)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.False(t, run.Exists("xx-key"))
+
+	ok, err = client.SetXX("nx-key", "new-value", 20)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 20*time.Second, run.TTL("nx-key"))
+
+	// value can only be taken once
+	reply, err = client.GetDel("other-key")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", reply)
+
+	_, err = client.GetDel("other-key")
+	assert.ErrorIs(t, err, ErrNilReturned)
+}
+
+func TestClient_KeysAndSets(t *testing.T) {
+	run, err := miniredis.Run()
+
+	if err != nil {
+		log.Fatalf("miniredis.Run returns err: %+v\n", err)
+	}
+
+	defer run.Close()
+
+	client := NewRedisClient(config.RedisConfig{Host: run.Addr()})
+
+	// ttl of missing key
+	ttl, err := client.TTL("key")
+	assert.NoError(t, err)
+	assert.Equal(t, -2, ttl)
+
+	assert.NoError(t, client.Set("key", "value", 0))
+
+	ttl, err = client.TTL("key")
+	assert.NoError(t, err)
+	assert.Equal(t, -1, ttl)
+
+	assert.NoError(t, client.Expire("key", 30))
+
+	ttl, err = client.TTL("key")
+	assert.NoError(t, err)
+	assert.Equal(t, 30, ttl)
+
+	// counter keeps its first expiration
+	value, err := client.Incr("counter", 30)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), value)
+
+	run.FastForward(10 * time.Second)
+
+	value, err = client.Incr("counter", 30)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), value)
+	assert.Equal(t, 20*time.Second, run.TTL("counter"))
+
+	// counter left without expiration gets one
+	_, err = run.Incr("stale-counter", 3)
+	assert.NoError(t, err)
+
+	value, err = client.Incr("stale-counter", 30)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), value)
+	assert.Equal(t, 30*time.Second, run.TTL("stale-counter"))
+
+	assert.NoError(t, client.Del("key"))
+	assert.False(t, run.Exists("key"))
+
+	// sets
+	assert.NoError(t, client.SAdd("set", "a", "b", "c"))
+	assert.NoError(t, client.SRem("set", "b"))
+
+	members, err := client.SMembers("set")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "c"}, members)
+}
@@ -0,0 +1,74 @@
+package totp
+
+import (
+	"encoding/base32"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+// secret of RFC 6238 test vectors for SHA1
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestGenerateCode(t *testing.T) {
+	// RFC 6238 appendix B, last 6 of the 8 digits
+	testCases := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+		{unix: 20000000000, code: "353130"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.code, func(t *testing.T) {
+			code, err := GenerateCode(rfcSecret, time.Unix(tc.unix, 0))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.code, code)
+		})
+	}
+}
+
+func TestGenerateCode_invalidSecret(t *testing.T) {
+	_, err := GenerateCode("not base32!", time.Now())
+
+	assert.ErrorIs(t, err, ErrInvalidSecret)
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	code, err := GenerateCode(secret, now)
+	assert.NoError(t, err)
+
+	valid, step := Validate(secret, code, now)
+	assert.True(t, valid)
+	assert.Equal(t, timeStep(now), step)
+
+	// one step of clock drift is accepted
+	valid, _ = Validate(secret, code, now.Add(Period))
+	assert.True(t, valid)
+
+	// code from the past is rejected
+	valid, _ = Validate(secret, code, now.Add(3*Period))
+	assert.False(t, valid)
+
+	valid, _ = Validate(secret, "12345", now)
+	assert.False(t, valid)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("My App", "user@example.com", "SECRET")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/My%20App:user@example.com?"))
+	assert.Contains(t, uri, "secret=SECRET")
+	assert.Contains(t, uri, "issuer=My+App")
+}